@@ -0,0 +1,65 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+
+	"k8s.io/klog/v2"
+	"mayadata.io/d-operators/pkg/crd"
+)
+
+var (
+	output = flag.String(
+		"output",
+		"",
+		"The file to write the generated CRDs to. CRDs are written to stdout if empty.",
+	)
+
+	srcDir = flag.String(
+		"src-dir",
+		".",
+		"The directory within the Go module used to find the sources of Go types.",
+	)
+)
+
+// main generates the CustomResourceDefinitions of dope custom
+// resources from their Go types
+//
+// NOTE:
+//	Run 'make generate-crds' after changing any of these Go types
+func main() {
+	flag.Parse()
+
+	raw, err := crd.NewGenerator(*srcDir).GenerateYAML(
+		crd.GeneratedHeader,
+		crd.DopeDefinitions...,
+	)
+	if err != nil {
+		klog.Fatalf("Failed to generate CRDs: %+v", err)
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(raw)
+	} else {
+		err = ioutil.WriteFile(*output, raw, 0644)
+	}
+	if err != nil {
+		klog.Fatalf("Failed to write CRDs: %+v", err)
+	}
+}
@@ -25,10 +25,38 @@ import (
 	"mayadata.io/d-operators/controller/http"
 	"mayadata.io/d-operators/controller/recipe"
 	"mayadata.io/d-operators/controller/run"
+	"mayadata.io/d-operators/pkg/metrics"
+	"mayadata.io/d-operators/pkg/webhook"
 	"openebs.io/metac/controller/generic"
 	"openebs.io/metac/start"
 )
 
+var (
+	metricsAddr = flag.String(
+		"metrics-addr",
+		":8080",
+		"The address to expose prometheus metrics at. Set to empty to disable.",
+	)
+
+	webhookAddr = flag.String(
+		"webhook-addr",
+		"",
+		"The address to serve the validating webhook at e.g. :8443. Set to empty to disable.",
+	)
+
+	webhookCertFile = flag.String(
+		"webhook-cert-file",
+		"",
+		"The TLS certificate file used by the validating webhook.",
+	)
+
+	webhookKeyFile = flag.String(
+		"webhook-key-file",
+		"",
+		"The TLS private key file used by the validating webhook.",
+	)
+)
+
 // main function is the entry point of this binary.
 //
 // This registers various controller (i.e. kubernetes reconciler)
@@ -74,5 +102,28 @@ func main() {
 	for name, ctrl := range controllers {
 		generic.AddToInlineRegistry(name, ctrl)
 	}
+
+	if *metricsAddr != "" {
+		go func() {
+			err := metrics.Serve(*metricsAddr)
+			if err != nil {
+				klog.Errorf("Failed to serve metrics: %+v", err)
+			}
+		}()
+	}
+
+	if *webhookAddr != "" {
+		server := webhook.NewServer(webhook.ServerConfig{
+			Addr:     *webhookAddr,
+			CertFile: *webhookCertFile,
+			KeyFile:  *webhookKeyFile,
+		})
+		go func() {
+			err := server.Start()
+			if err != nil {
+				klog.Fatalf("Failed to serve webhook: %+v", err)
+			}
+		}()
+	}
 	start.Start()
 }
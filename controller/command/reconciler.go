@@ -18,6 +18,8 @@ package command
 
 import (
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	"openebs.io/metac/controller/generic"
 
@@ -26,6 +28,7 @@ import (
 	"mayadata.io/d-operators/common/pointer"
 	"mayadata.io/d-operators/common/unstruct"
 	"mayadata.io/d-operators/pkg/command"
+	"mayadata.io/d-operators/pkg/event"
 	types "mayadata.io/d-operators/types/command"
 )
 
@@ -37,6 +40,9 @@ type Reconciler struct {
 
 	observedCommand types.Command
 	status          types.CommandStatus
+
+	// recorder records events against the observed Command
+	recorder record.EventRecorder
 }
 
 func (r *Reconciler) eval() {
@@ -130,12 +136,55 @@ func (r *Reconciler) setWatchAttributes() {
 	}
 }
 
+// recordEvents records events if the phase of the observed
+// Command is changed by this reconciliation
+//
+// NOTE:
+//	Events related to the Command's job are recorded by the
+// command package
+func (r *Reconciler) recordEvents() {
+	if r.recorder == nil || r.observedCommand.GetName() == "" {
+		return
+	}
+	var (
+		observed = r.observedCommand.Status
+		ref      = event.NewReference(
+			r.observedCommand.TypeMeta,
+			&r.observedCommand,
+		)
+	)
+	if r.Err != nil {
+		if observed.Phase == types.CommandPhaseError &&
+			observed.Reason == r.Err.Error() {
+			// this error was recorded previously
+			return
+		}
+		r.recorder.Event(
+			ref,
+			corev1.EventTypeWarning,
+			event.ReasonReconcileError,
+			r.Err.Error(),
+		)
+		return
+	}
+	if r.status.Phase == types.CommandPhaseSkipped &&
+		observed.Phase != types.CommandPhaseSkipped {
+		r.recorder.Event(
+			ref,
+			corev1.EventTypeNormal,
+			string(types.CommandPhaseSkipped),
+			r.status.Reason,
+		)
+	}
+}
+
 func (r *Reconciler) setResponse() {
 	// Reconciling atachments are skipped since attachments
 	// are not reconciled as part of reconciling Command resource
 	r.HookResponse.SkipReconcile = true
 	r.setResyncInterval()
 	r.setWatchAttributes()
+	r.recordEvents()
 }
 
 // Sync implements the idempotent logic to sync Command resource
@@ -154,6 +203,7 @@ func Sync(request *generic.SyncHookRequest, response *generic.SyncHookResponse)
 			HookRequest:  request,
 			HookResponse: response,
 		},
+		recorder: event.Recorder(),
 	}
 	// add functions to achieve desired state
 	r.ReconcileFns = []func(){
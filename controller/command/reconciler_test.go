@@ -22,7 +22,9 @@ import (
 	"fmt"
 	"testing"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"mayadata.io/d-operators/common/controller"
 	"mayadata.io/d-operators/common/pointer"
 	types "mayadata.io/d-operators/types/command"
@@ -182,3 +184,70 @@ func TestReconcilerSetWatchAttributes(t *testing.T) {
 		})
 	}
 }
+
+func TestReconcilerRecordEvents(t *testing.T) {
+	var tests = map[string]struct {
+		Observed      types.CommandStatus
+		Status        types.CommandStatus
+		Error         error
+		ExpectedEvent string
+	}{
+		"no status no error": {},
+		"new error": {
+			Error:         fmt.Errorf("some err"),
+			ExpectedEvent: "Warning ReconcileError some err",
+		},
+		"previously observed error": {
+			Observed: types.CommandStatus{
+				Phase:  types.CommandPhaseError,
+				Reason: "some err",
+			},
+			Error: fmt.Errorf("some err"),
+		},
+		"newly skipped": {
+			Status: types.CommandStatus{
+				Phase:  types.CommandPhaseSkipped,
+				Reason: "Resource is not enabled",
+			},
+			ExpectedEvent: "Normal Skipped Resource is not enabled",
+		},
+		"previously skipped": {
+			Observed: types.CommandStatus{
+				Phase: types.CommandPhaseSkipped,
+			},
+			Status: types.CommandStatus{
+				Phase:  types.CommandPhaseSkipped,
+				Reason: "Resource is not enabled",
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+			r := &Reconciler{
+				observedCommand: types.Command{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "cmd",
+					},
+					Status: mock.Observed,
+				},
+				status:   mock.Status,
+				recorder: recorder,
+				Reconciler: controller.Reconciler{
+					Err: mock.Error,
+				},
+			}
+			r.recordEvents()
+			var got string
+			select {
+			case got = <-recorder.Events:
+			default:
+			}
+			if got != mock.ExpectedEvent {
+				t.Fatalf("Expected event %q got %q", mock.ExpectedEvent, got)
+			}
+		})
+	}
+}
@@ -17,12 +17,17 @@ limitations under the License.
 package doperator
 
 import (
-	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"reflect"
+
 	"openebs.io/metac/controller/generic"
 
 	ctrlutil "mayadata.io/d-operators/common/controller"
 	"mayadata.io/d-operators/common/unstruct"
+	"mayadata.io/d-operators/pkg/crd"
+	dopehttp "mayadata.io/d-operators/pkg/http"
 	types "mayadata.io/d-operators/types/doperator"
+	"mayadata.io/d-operators/types/gvk"
+	httptypes "mayadata.io/d-operators/types/http"
 )
 
 // Reconciler manages CStorPoolAuto operational needs
@@ -47,211 +52,84 @@ func (r *Reconciler) walkObservedDOperator() {
 	r.observedDOperator = &dope
 }
 
-func (r *Reconciler) setDesiredCRDBlockDeviceSet() {
-	crd := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "apiextensions.k8s.io/v1beta1",
-			"kind":       "CustomResourceDefinition",
-			"metadata": map[string]interface{}{
-				"name": "blockdevicesets.dao.mayadata.io",
-				"annotations": map[string]interface{}{
-					// refer to the object that triggered this creation
-					"doperator.dao.mayadata.io/uid": r.observedDOperator.UID,
-				},
-			},
-			"spec": map[string]interface{}{
-				"group":   "dao.mayadata.io",
-				"version": "v1alpha1",
-				"scope":   "Namespaced",
-				"names": map[string]interface{}{
-					"plural":   "blockdevicesets",
-					"singular": "blockdeviceset",
-					"kind":     "BlockDeviceSet",
-					"shortNames": []interface{}{
-						"bdset",
-					},
-				},
-			},
-		},
-	}
-	r.HookResponse.Attachments = append(
-		r.HookResponse.Attachments,
-		crd,
-	)
-}
-
-func (r *Reconciler) setDesiredCRDHTTP() {
-	crd := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "apiextensions.k8s.io/v1beta1",
-			"kind":       "CustomResourceDefinition",
-			"metadata": map[string]interface{}{
-				"name": "https.dao.mayadata.io",
-				"annotations": map[string]interface{}{
-					// refer to the object that triggered this creation
-					"doperator.dao.mayadata.io/uid": r.observedDOperator.UID,
-				},
-			},
-			"spec": map[string]interface{}{
-				"group":   "dao.mayadata.io",
-				"version": "v1alpha1",
-				"scope":   "Namespaced",
-				"names": map[string]interface{}{
-					"plural":   "https",
-					"singular": "http",
-					"kind":     "HTTP",
-					"shortNames": []interface{}{
-						"http",
-					},
-				},
-			},
-		},
-	}
-	r.HookResponse.Attachments = append(
-		r.HookResponse.Attachments,
-		crd,
-	)
-}
-
-func (r *Reconciler) setDesiredCRDHTTPData() {
-	crd := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "apiextensions.k8s.io/v1beta1",
-			"kind":       "CustomResourceDefinition",
-			"metadata": map[string]interface{}{
-				"name": "httpdatas.dao.mayadata.io",
-				"annotations": map[string]interface{}{
-					// refer to the object that triggered this creation
-					"doperator.dao.mayadata.io/uid": r.observedDOperator.UID,
-				},
-			},
-			"spec": map[string]interface{}{
-				"group":   "dao.mayadata.io",
-				"version": "v1alpha1",
-				"scope":   "Namespaced",
-				"names": map[string]interface{}{
-					"plural":   "httpdatas",
-					"singular": "httpdata",
-					"kind":     "HTTPData",
-					"shortNames": []interface{}{
-						"httpdata",
-					},
-				},
-			},
-		},
-	}
-	r.HookResponse.Attachments = append(
-		r.HookResponse.Attachments,
-		crd,
-	)
-}
-
-func (r *Reconciler) setDesiredCRDCStorPoolCapacityRecommendation() {
-	crd := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "apiextensions.k8s.io/v1beta1",
-			"kind":       "CustomResourceDefinition",
-			"metadata": map[string]interface{}{
-				"name": "cstorpoolcapacityrecommendations.dao.mayadata.io",
-				"annotations": map[string]interface{}{
-					// refer to the object that triggered this creation
-					"doperator.dao.mayadata.io/uid": r.observedDOperator.UID,
-				},
-			},
-			"spec": map[string]interface{}{
-				"group":   "dao.mayadata.io",
-				"version": "v1alpha1",
-				"scope":   "Namespaced",
-				"names": map[string]interface{}{
-					"plural":   "cstorpoolcapacityrecommendations",
-					"singular": "cstorpoolcapacityrecommendation",
-					"kind":     "CStorPoolCapacityRecommendation",
-					"shortNames": []interface{}{
-						"cspcapr",
-					},
-				},
-			},
-		},
-	}
-	r.HookResponse.Attachments = append(
-		r.HookResponse.Attachments,
-		crd,
-	)
-}
-
-func (r *Reconciler) setDesiredCRDDirectorHTTP() {
-	crd := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "apiextensions.k8s.io/v1beta1",
-			"kind":       "CustomResourceDefinition",
-			"metadata": map[string]interface{}{
-				"name": "directorhttps.dao.mayadata.io",
-				"annotations": map[string]interface{}{
-					// refer to the object that triggered this creation
-					"doperator.dao.mayadata.io/uid": r.observedDOperator.UID,
-				},
-			},
-			"spec": map[string]interface{}{
-				"group":   "dao.mayadata.io",
-				"version": "v1alpha1",
-				"scope":   "Namespaced",
-				"names": map[string]interface{}{
-					"plural":   "directorhttps",
-					"singular": "directorhttp",
-					"kind":     "DirectorHTTP",
-					"shortNames": []interface{}{
-						"drhttp",
-					},
-				},
-			},
-		},
-	}
-	r.HookResponse.Attachments = append(
-		r.HookResponse.Attachments,
-		crd,
-	)
-}
-
-func (r *Reconciler) setDesiredCRDCStorPoolAuto() {
-	crd := &unstructured.Unstructured{
-		Object: map[string]interface{}{
-			"apiVersion": "apiextensions.k8s.io/v1beta1",
-			"kind":       "CustomResourceDefinition",
-			"metadata": map[string]interface{}{
-				"name": "cstorpoolautos.dao.mayadata.io",
-				"annotations": map[string]interface{}{
-					// refer to the object that triggered this creation
-					"doperator.dao.mayadata.io/uid": r.observedDOperator.UID,
-				},
-			},
-			"spec": map[string]interface{}{
-				"group":   "dao.mayadata.io",
-				"version": "v1alpha1",
-				"scope":   "Namespaced",
-				"names": map[string]interface{}{
-					"plural":   "cstorpoolautos",
-					"singular": "cstorpoolauto",
-					"kind":     "CStorPoolAuto",
-					"shortNames": []interface{}{
-						"cspauto",
-					},
-				},
-			},
-		},
-	}
-	r.HookResponse.Attachments = append(
-		r.HookResponse.Attachments,
-		crd,
-	)
+// daoDefinitions are the custom resources whose definitions are
+// managed by DOperator
+//
+// NOTE:
+//	Custom resources without Go types allow any fields
+var daoDefinitions = []crd.Definition{
+	{
+		Group:      gvk.GroupDAOMayadataIO,
+		Version:    gvk.VersionV1Alpha1,
+		Kind:       "BlockDeviceSet",
+		Plural:     "blockdevicesets",
+		Singular:   "blockdeviceset",
+		ShortNames: []string{"bdset"},
+	},
+	{
+		Group:      gvk.GroupDAOMayadataIO,
+		Version:    gvk.VersionV1Alpha1,
+		Kind:       gvk.KindHTTP,
+		Plural:     "https",
+		Singular:   "http",
+		ShortNames: []string{"http"},
+		Type:       reflect.TypeOf(httptypes.HTTP{}),
+		ValueRules: dopehttp.ValueRules(),
+	},
+	{
+		Group:      gvk.GroupDAOMayadataIO,
+		Version:    gvk.VersionV1Alpha1,
+		Kind:       gvk.KindHTTPData,
+		Plural:     "httpdatas",
+		Singular:   "httpdata",
+		ShortNames: []string{"httpdata"},
+		Type:       reflect.TypeOf(httptypes.HTTPData{}),
+	},
+	{
+		Group:      gvk.GroupDAOMayadataIO,
+		Version:    gvk.VersionV1Alpha1,
+		Kind:       "CStorPoolCapacityRecommendation",
+		Plural:     "cstorpoolcapacityrecommendations",
+		Singular:   "cstorpoolcapacityrecommendation",
+		ShortNames: []string{"cspcapr"},
+	},
+	{
+		Group:      gvk.GroupDAOMayadataIO,
+		Version:    gvk.VersionV1Alpha1,
+		Kind:       gvk.KindDirectorHTTP,
+		Plural:     "directorhttps",
+		Singular:   "directorhttp",
+		ShortNames: []string{"drhttp"},
+	},
+	{
+		Group:      gvk.GroupDAOMayadataIO,
+		Version:    gvk.VersionV1Alpha1,
+		Kind:       "CStorPoolAuto",
+		Plural:     "cstorpoolautos",
+		Singular:   "cstorpoolauto",
+		ShortNames: []string{"cspauto"},
+	},
 }
 
 func (r *Reconciler) setDesiredCRDs() {
-	r.setDesiredCRDBlockDeviceSet()
-	r.setDesiredCRDHTTP()
-	r.setDesiredCRDHTTPData()
-	r.setDesiredCRDCStorPoolCapacityRecommendation()
-	r.setDesiredCRDDirectorHTTP()
-	r.setDesiredCRDCStorPoolAuto()
+	// descriptions are not available since Go sources are not
+	// available at runtime
+	generator := crd.NewGenerator("")
+	for _, def := range daoDefinitions {
+		desired, err := generator.GenerateUnstructured(def)
+		if err != nil {
+			r.Err = err
+			return
+		}
+		desired.SetAnnotations(map[string]string{
+			// refer to the object that triggered this creation
+			"doperator.dao.mayadata.io/uid": string(r.observedDOperator.UID),
+		})
+		r.HookResponse.Attachments = append(
+			r.HookResponse.Attachments,
+			desired,
+		)
+	}
 }
 
 // Sync implements the idempotent logic to sync HTTP
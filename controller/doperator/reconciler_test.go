@@ -0,0 +1,94 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package doperator
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"openebs.io/metac/controller/generic"
+
+	"mayadata.io/d-operators/types/gvk"
+)
+
+func TestSync(t *testing.T) {
+	request := &generic.SyncHookRequest{
+		Watch: &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind": "DOperator",
+				"metadata": map[string]interface{}{
+					"name": "test",
+					"uid":  "uid-1",
+				},
+			},
+		},
+	}
+	response := &generic.SyncHookResponse{}
+	err := Sync(request, response)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if len(response.Attachments) != len(daoDefinitions) {
+		t.Fatalf(
+			"Expected %d CRDs got %d",
+			len(daoDefinitions),
+			len(response.Attachments),
+		)
+	}
+	for _, crd := range response.Attachments {
+		if crd.GetAPIVersion() != gvk.APIExtensionsK8sIOV1 {
+			t.Fatalf(
+				"Expected api version %q got %q: %s",
+				gvk.APIExtensionsK8sIOV1,
+				crd.GetAPIVersion(),
+				crd.GetName(),
+			)
+		}
+		if crd.GetAnnotations()["doperator.dao.mayadata.io/uid"] != "uid-1" {
+			t.Fatalf("Expected uid annotation got %v", crd.GetAnnotations())
+		}
+		versions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+		if len(versions) != 1 {
+			t.Fatalf("Expected 1 version got %d: %s", len(versions), crd.GetName())
+		}
+		schemaType, _, _ := unstructured.NestedString(
+			versions[0].(map[string]interface{}),
+			"schema",
+			"openAPIV3Schema",
+			"type",
+		)
+		if schemaType != "object" {
+			t.Fatalf("Expected schema of type object got %q: %s", schemaType, crd.GetName())
+		}
+	}
+	// schema of HTTP is derived from its Go type
+	https := response.Attachments[1]
+	versions, _, _ := unstructured.NestedSlice(https.Object, "spec", "versions")
+	required, _, _ := unstructured.NestedStringSlice(
+		versions[0].(map[string]interface{}),
+		"schema",
+		"openAPIV3Schema",
+		"properties",
+		"spec",
+		"required",
+	)
+	if len(required) != 1 || required[0] != "url" {
+		t.Fatalf("Expected spec.url to be required got %v: %s", required, https.GetName())
+	}
+}
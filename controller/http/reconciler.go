@@ -19,14 +19,18 @@ package http
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"openebs.io/metac/controller/generic"
 
 	ctrlutil "mayadata.io/d-operators/common/controller"
 	"mayadata.io/d-operators/common/unstruct"
+	"mayadata.io/d-operators/pkg/event"
 	"mayadata.io/d-operators/pkg/http"
+	"mayadata.io/d-operators/pkg/metrics"
 	types "mayadata.io/d-operators/types/http"
 )
 
@@ -52,6 +56,9 @@ type Reconciler struct {
 
 	// response received after invoking the http request
 	response types.HTTPResponse
+
+	// recorder records events against the observed HTTP resource
+	recorder record.EventRecorder
 }
 
 // evalObservedHTTP tranforms the HTTP custom resource observed in Kubernetes
@@ -184,7 +191,15 @@ func (r *Reconciler) invokeHTTP() {
 		PathParams:  r.observedHTTP.Spec.PathParams,
 		QueryParams: r.observedHTTP.Spec.QueryParams,
 	})
+	start := time.Now()
 	r.response, r.Err = i.Invoke()
+	// a code of 0 implies no response was received
+	metrics.ObserveHTTPResponse(
+		r.observedHTTP.GetNamespace(),
+		r.observedHTTP.GetName(),
+		r.response.HTTPStatusCode,
+		time.Since(start),
+	)
 }
 
 // handleRuntimeError handles runtime error if any
@@ -210,6 +225,11 @@ func (r *Reconciler) updateHTTPStatus() {
 	// check for runtime errors
 	if r.Err != nil {
 		r.handleRuntimeError()
+		r.recordPhaseTransition(
+			types.HTTPStatusPhaseError,
+			r.Err.Error(),
+			r.Err.Error(),
+		)
 		// skip setting other status fields
 		return
 	}
@@ -217,6 +237,13 @@ func (r *Reconciler) updateHTTPStatus() {
 	// initialise phase to Online
 	var phase = types.HTTPStatusPhaseOnline
 	var warn, reason string
+	defer func() {
+		r.recordPhaseTransition(
+			phase,
+			reason,
+			fmt.Sprintf("Received %q", r.response.HTTPStatus),
+		)
+	}()
 
 	// check for warnings
 	if len(r.Warns) != 0 {
@@ -247,6 +274,26 @@ func (r *Reconciler) updateHTTPStatus() {
 	}
 }
 
+// recordPhaseTransition records an event if the provided phase &
+// reason differ from that of the observed HTTP resource
+func (r *Reconciler) recordPhaseTransition(phase, reason, message string) {
+	if r.recorder == nil || r.observedHTTP == nil {
+		return
+	}
+	observed := r.observedHTTP.Status
+	if observed.Phase == phase && observed.Reason == reason {
+		// this transition was recorded previously
+		return
+	}
+	eventtype := event.TypeForPhase(phase, types.HTTPStatusPhaseError)
+	r.recorder.Event(
+		event.NewReference(r.observedHTTP.TypeMeta, r.observedHTTP),
+		eventtype,
+		phase,
+		message,
+	)
+}
+
 // Sync implements the idempotent logic to reconcile HTTP
 // custom resource.
 //
@@ -264,6 +311,7 @@ func Sync(request *generic.SyncHookRequest, response *generic.SyncHookResponse)
 			HookRequest:  request,
 			HookResponse: response,
 		},
+		recorder: event.Recorder(),
 	}
 
 	// Add functions to achieve desired state by parsing
@@ -17,17 +17,45 @@ limitations under the License.
 package recipe
 
 import (
+	"time"
+
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
 	"openebs.io/metac/controller/generic"
 	k8s "openebs.io/metac/third_party/kubernetes"
 
 	ctrl "mayadata.io/d-operators/common/controller"
 	"mayadata.io/d-operators/common/unstruct"
+	"mayadata.io/d-operators/pkg/event"
+	"mayadata.io/d-operators/pkg/metrics"
 	"mayadata.io/d-operators/pkg/recipe"
 	"mayadata.io/d-operators/pkg/schema"
 	types "mayadata.io/d-operators/types/recipe"
 )
 
+// defaultOnWaitingForApprovalResyncInSeconds is the interval to
+// verify the approval of a Recipe that is waiting for approval
+const defaultOnWaitingForApprovalResyncInSeconds int64 = 30
+
+// retainedStatusFields are the fields of Recipe's status that are
+// retained when Recipe's run results in an error
+//
+// NOTE:
+//	These are taken from the status of the errored run if set, else
+// from the observed status
+var retainedStatusFields = []string{
+	"checkpoint",
+	"history",
+	"lock",
+	"previousRun",
+	"rerunToken",
+	"revision",
+	"schedule",
+}
+
 // Reconciler manages reconciliation of Recipe custom resource
 type Reconciler struct {
 	ctrl.Reconciler
@@ -37,20 +65,39 @@ type Reconciler struct {
 
 	// resulting status after executing the observed Recipe
 	recipeRunStatus types.RecipeStatus
+
+	// recorder records events against the observed Recipe
+	recorder record.EventRecorder
 }
 
 func (r *Reconciler) eval() {
-	// validate the received structured instance
-	v := &schema.FieldPathValidation{
-		Target:                  r.HookRequest.Watch.Object,
-		SupportedAbsolutePaths:  types.SupportedAbsolutePaths,
-		UserAllowedPathPrefixes: types.UserAllowedPathPrefixes,
-	}
-	valResult := v.Validate()
+	// validate the field paths as well as the values of the
+	// received unstructured instance
+	valResult := recipe.ValidateSchema(r.HookRequest.Watch.Object)
 
 	var j types.Recipe
 	// convert from unstructured instance to typed instance
 	err := unstruct.ToTyped(r.HookRequest.Watch, &j)
+	if err != nil &&
+		valResult.Status == schema.FieldPathValidationStatusInvalid {
+		// values with invalid types can't be converted
+		//
+		// NOTE:
+		//	Recipe is set with its identity only so that its
+		// status can be updated with the validation failures
+		j = types.Recipe{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: r.HookRequest.Watch.GetAPIVersion(),
+				Kind:       r.HookRequest.Watch.GetKind(),
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: r.HookRequest.Watch.GetNamespace(),
+				Name:      r.HookRequest.Watch.GetName(),
+				UID:       r.HookRequest.Watch.GetUID(),
+			},
+		}
+		err = nil
+	}
 	if err != nil {
 		r.Err = errors.Wrapf(
 			err,
@@ -67,9 +114,31 @@ func (r *Reconciler) invoke() {
 		recipe.RunnerConfig{
 			Recipe:                    *r.ObservedRecipe,
 			FieldPathValidationResult: *r.FieldPathValidationResult,
+			Recorder:                  r.recorder,
 		},
 	)
 	r.recipeRunStatus, r.Err = runner.Run()
+	r.observeMetrics()
+}
+
+func (r *Reconciler) observeMetrics() {
+	phase := string(r.recipeRunStatus.Phase)
+	if r.Err != nil {
+		phase = string(types.RecipeStatusError)
+	}
+	var duration *time.Duration
+	if r.recipeRunStatus.ExecutionTime != nil {
+		d := time.Duration(
+			r.recipeRunStatus.ExecutionTime.ValueInSeconds * float64(time.Second),
+		)
+		duration = &d
+	}
+	metrics.ObserveRecipeExecution(
+		r.ObservedRecipe.GetNamespace(),
+		r.ObservedRecipe.GetName(),
+		phase,
+		duration,
+	)
 }
 
 func (r *Reconciler) setSyncResponse() {
@@ -85,6 +154,11 @@ func (r *Reconciler) setRecipeStatusFromResult() {
 		// nothing needs to be done
 		return
 	}
+	if r.recipeRunStatus.Phase == types.RecipeStatusSuspended {
+		// Recipe is not resynced since it gets reconciled once
+		// spec.suspend is modified
+		return
+	}
 	if r.recipeRunStatus.Phase == types.RecipeStatusNotEligible &&
 		r.ObservedRecipe.Spec.Resync.OnNotEligibleResyncInSeconds != nil {
 		// set configured resync interval when Recipe's phase
@@ -99,6 +173,29 @@ func (r *Reconciler) setRecipeStatusFromResult() {
 		// holds more priority
 		return
 	}
+	if r.recipeRunStatus.Phase == types.RecipeStatusWaitingForApproval {
+		// resync to verify the approval since approvals via
+		// ConfigMap or timeouts do not trigger reconciliation
+		var resync = defaultOnWaitingForApprovalResyncInSeconds
+		if r.ObservedRecipe.Spec.Resync.OnWaitingForApprovalResyncInSeconds != nil {
+			resync = *r.ObservedRecipe.Spec.Resync.OnWaitingForApprovalResyncInSeconds
+		}
+		r.HookResponse.ResyncAfterSeconds = float64(resync)
+		return
+	}
+	if r.recipeRunStatus.Schedule != nil &&
+		r.recipeRunStatus.Schedule.NextScheduleTime != nil {
+		// resync at the next scheduled time of this Recipe
+		//
+		// NOTE:
+		//	Schedule holds more priority than resync interval
+		wait := time.Until(r.recipeRunStatus.Schedule.NextScheduleTime.Time)
+		if wait < time.Second {
+			wait = time.Second
+		}
+		r.HookResponse.ResyncAfterSeconds = wait.Seconds()
+		return
+	}
 	if r.ObservedRecipe.Spec.Resync.IntervalInSeconds != nil {
 		// set configured resync interval during normal conditions
 		//
@@ -119,13 +216,102 @@ func (r *Reconciler) setRecipeStatusFromError() {
 		r.HookResponse.ResyncAfterSeconds =
 			float64(*r.ObservedRecipe.Spec.Resync.OnErrorResyncInSeconds)
 	}
+	if r.recipeRunStatus.Schedule != nil &&
+		r.recipeRunStatus.Schedule.NextScheduleTime != nil {
+		// resync at the next scheduled time of this Recipe if it
+		// is earlier than the resync interval on error
+		wait := time.Until(r.recipeRunStatus.Schedule.NextScheduleTime.Time)
+		if wait < time.Second {
+			wait = time.Second
+		}
+		if r.HookResponse.ResyncAfterSeconds == 0 ||
+			wait.Seconds() < r.HookResponse.ResyncAfterSeconds {
+			r.HookResponse.ResyncAfterSeconds = wait.Seconds()
+		}
+	}
 	r.HookResponse.Status = map[string]interface{}{
-		"phase":  "Error",
+		"phase":  string(types.RecipeStatusError),
 		"reason": r.Err.Error(),
 	}
+	// results of the tasks that were run before this error are
+	// retained since these may have diagnostics e.g. results of
+	// onFailure tasks
+	for key, results := range map[string]map[string]types.TaskResult{
+		"tasks":          r.recipeRunStatus.TaskResults,
+		"onFailureTasks": r.recipeRunStatus.OnFailureTaskResults,
+		"finallyTasks":   r.recipeRunStatus.FinallyTaskResults,
+	} {
+		if len(results) == 0 {
+			continue
+		}
+		var value map[string]interface{}
+		err := unstruct.MarshalThenUnmarshal(results, &value)
+		if err != nil {
+			// swallow this error since the original error is
+			// more important
+			klog.Errorf("Failed to set %q in status: %s", key, err.Error())
+			continue
+		}
+		r.HookResponse.Status[key] = value
+	}
+	r.retainStatusFields()
 	r.HookResponse.Labels = map[string]*string{
-		types.LblKeyRecipePhase: k8s.StringPtr("Error"),
+		types.LblKeyRecipePhase: k8s.StringPtr(string(types.RecipeStatusError)),
+	}
+	r.recordErrorEvent()
+}
+
+// retainStatusFields sets the retained fields in the error status
+// since metac replaces the entire status of this Recipe
+//
+// NOTE:
+//	Checkpoint is retained to resume from the task that resulted
+// in this error. History is retained to record this error as well
+// as the previous runs. Schedule is retained to resync at the next
+// scheduled time & to honour the missed run policy. Revision, rerun
+// token & previous run are retained to avoid re-running this Recipe
+// during every reconcile.
+func (r *Reconciler) retainStatusFields() {
+	var current, observed map[string]interface{}
+	err := unstruct.MarshalThenUnmarshal(r.recipeRunStatus, &current)
+	if err != nil {
+		// swallow this error since the original error is more
+		// important
+		klog.Errorf("Failed to retain status fields: %s", err.Error())
+		return
+	}
+	if r.ObservedRecipe != nil {
+		err = unstruct.MarshalThenUnmarshal(r.ObservedRecipe.Status, &observed)
+		if err != nil {
+			klog.Errorf("Failed to retain observed status fields: %s", err.Error())
+		}
 	}
+	for _, key := range retainedStatusFields {
+		if value, found := current[key]; found {
+			r.HookResponse.Status[key] = value
+		} else if value, found := observed[key]; found {
+			r.HookResponse.Status[key] = value
+		}
+	}
+}
+
+// recordErrorEvent records an event if this error is different
+// from the one observed in the Recipe's status
+func (r *Reconciler) recordErrorEvent() {
+	if r.ObservedRecipe == nil || r.recorder == nil {
+		return
+	}
+	if r.ObservedRecipe.Status.Phase == types.RecipeStatusError &&
+		r.ObservedRecipe.Status.Reason == r.Err.Error() {
+		// this error was recorded previously
+		return
+	}
+	r.recorder.Event(
+		event.NewReference(r.ObservedRecipe.TypeMeta, r.ObservedRecipe),
+		corev1.EventTypeWarning,
+		event.ReasonReconcileError,
+		r.Err.Error(),
+	)
 }
 
 func (r *Reconciler) setRecipeStatus() {
@@ -155,6 +341,7 @@ func Sync(request *generic.SyncHookRequest, response *generic.SyncHookResponse)
 			HookRequest:  request,
 			HookResponse: response,
 		},
+		recorder: event.Recorder(),
 	}
 	// add functions to achieve desired state
 	r.ReconcileFns = []func(){
@@ -0,0 +1,171 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"mayadata.io/d-operators/common/controller"
+	types "mayadata.io/d-operators/types/recipe"
+	"openebs.io/metac/controller/generic"
+)
+
+func TestReconcilerSetRecipeStatusFromError(t *testing.T) {
+	var next = metav1.NewTime(time.Now().Add(time.Minute))
+	var tests = map[string]struct {
+		observed         types.RecipeStatus
+		status           types.RecipeStatus
+		expectedRetained []string
+		isResync         bool
+	}{
+		"no fields to retain": {},
+		"fields of errored run are retained": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+			},
+			status: types.RecipeStatus{
+				Phase: types.RecipeStatusError,
+				History: []types.RecipeRunSummary{
+					{Phase: types.RecipeStatusFailed},
+					{Phase: types.RecipeStatusError},
+				},
+				Checkpoint: &types.CheckpointStatus{
+					Markers: map[string]string{"one": "abc"},
+				},
+			},
+			expectedRetained: []string{"checkpoint", "history"},
+		},
+		"observed fields are retained if run did not start": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+				History: []types.RecipeRunSummary{
+					{Phase: types.RecipeStatusFailed},
+				},
+			},
+			expectedRetained: []string{"history"},
+		},
+		"schedule & lock are retained": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusPassed,
+				Schedule: &types.ScheduleStatus{
+					Message: "Next run at 10:00",
+				},
+			},
+			status: types.RecipeStatus{
+				Phase: types.RecipeStatusError,
+				Lock: &types.LockStatus{
+					HolderIdentity: "pod-1",
+				},
+			},
+			expectedRetained: []string{"lock", "schedule"},
+		},
+		"resync at next scheduled time": {
+			status: types.RecipeStatus{
+				Phase: types.RecipeStatusError,
+				Schedule: &types.ScheduleStatus{
+					NextScheduleTime: &next,
+				},
+			},
+			expectedRetained: []string{"schedule"},
+			isResync:         true,
+		},
+		"revision & rerun details are retained": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+				Revision: &types.SpecRevision{
+					Generation: 1,
+					SpecHash:   "abc",
+				},
+			},
+			status: types.RecipeStatus{
+				Phase:      types.RecipeStatusError,
+				RerunToken: "2",
+				PreviousRun: &types.RecipeRunSummary{
+					Phase: types.RecipeStatusCompleted,
+				},
+			},
+			expectedRetained: []string{"previousRun", "rerunToken", "revision"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Reconciler{
+				ObservedRecipe: &types.Recipe{
+					Status: mock.observed,
+				},
+				recipeRunStatus: mock.status,
+				Reconciler: controller.Reconciler{
+					Err:          fmt.Errorf("some err"),
+					HookResponse: &generic.SyncHookResponse{},
+				},
+			}
+			r.setRecipeStatusFromError()
+			if r.HookResponse.Status["phase"] != string(types.RecipeStatusError) {
+				t.Fatalf(
+					"Expected phase %q got %v",
+					types.RecipeStatusError,
+					r.HookResponse.Status["phase"],
+				)
+			}
+			for _, key := range retainedStatusFields {
+				_, found := r.HookResponse.Status[key]
+				var isExpected bool
+				for _, expected := range mock.expectedRetained {
+					if key == expected {
+						isExpected = true
+						break
+					}
+				}
+				if found != isExpected {
+					t.Fatalf(
+						"Expected %q retained %t got %t: %v",
+						key,
+						isExpected,
+						found,
+						r.HookResponse.Status,
+					)
+				}
+			}
+			if mock.isResync != (r.HookResponse.ResyncAfterSeconds > 0) {
+				t.Fatalf(
+					"Expected resync %t got resync after %f",
+					mock.isResync,
+					r.HookResponse.ResyncAfterSeconds,
+				)
+			}
+			history, _ := r.HookResponse.Status["history"].([]interface{})
+			var expectedHistory = mock.status.History
+			if len(expectedHistory) == 0 {
+				expectedHistory = mock.observed.History
+			}
+			if len(history) != len(expectedHistory) {
+				t.Fatalf(
+					"Expected history count %d got %d",
+					len(expectedHistory),
+					len(history),
+				)
+			}
+		})
+	}
+}
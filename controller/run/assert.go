@@ -321,17 +321,39 @@ func (a *Assertion) includeWarningIfEnabled(message ...string) {
 	)
 }
 
-func (a *Assertion) verifyAllConditions() {
-	// flag OR operator if all conditions need to OR-ed
-	isOperatorOR :=
-		a.Request.Assert.CheckOperator == types.ResourceCheckOperatorOR
-	// flag AND operator if all conditions need to be AND-ed
-	isOperatorAND :=
-		a.Request.Assert.CheckOperator == types.ResourceCheckOperatorAND
-	var atleastOneSuccess bool
-	// run all conditions against all the available resources
-	for _, cond := range a.Request.Assert.SelectChecks {
-		// create a new instance of the current if condition
+// evalCheck evaluates the provided check along with its nested
+// checks against the resources. Evaluation of a check stops as soon
+// as its outcome is known.
+func (a *Assertion) evalCheck(check types.ResourceCheck) types.ResourceCheckResult {
+	var op = check.CheckOperator
+	if op == "" {
+		// OR is the default operator
+		op = types.ResourceCheckOperatorOR
+	}
+	var result = types.ResourceCheckResult{
+		CheckOperator: op,
+	}
+	var total = len(check.SelectChecks) + len(check.Checks)
+	var evalCount, successCount int
+	var isDone bool
+	// register the outcome of an operand & flag if the outcome
+	// of this check is already known
+	register := func(success bool) {
+		evalCount++
+		if success {
+			successCount++
+		}
+		if op == types.ResourceCheckOperatorOR && success {
+			// at-least one success is a complete success
+			isDone = true
+		} else if op == types.ResourceCheckOperatorAND && !success {
+			// any failure is a complete failure
+			isDone = true
+		}
+	}
+	// run select checks against all the available resources
+	for idx, cond := range check.SelectChecks {
+		// create a new instance of the current condition
 		// against all the resources
 		listCond := NewResourceListCondition(
 			ResourceListConditionConfig{
@@ -344,27 +366,89 @@ func (a *Assertion) verifyAllConditions() {
 		success, err := listCond.IsSuccess()
 		if err != nil {
 			a.err = err
-			return
+			return result
 		}
 		// add matching conditions to info
 		a.includeMatchInfoIfEnabled(listCond.Result.DesiredResourcesInfo...)
 		// add non-matching conditions to info
 		a.includeNoMatchInfoIfEnabled(listCond.Result.SkippedResourcesInfo...)
-		if success && !atleastOneSuccess {
-			atleastOneSuccess = true
+		result.SelectCheckResults = append(
+			result.SelectCheckResults,
+			types.ResourceSelectCheckResult{
+				Index:  idx,
+				Passed: success,
+			},
+		)
+		register(success)
+		if isDone {
+			break
 		}
-		if isOperatorOR && success {
-			// at-least one success is a complete success
-			a.isSuccess = true
-			return
+	}
+	// run nested checks if outcome is not yet known
+	for _, nested := range check.Checks {
+		if isDone {
+			break
 		}
-		if isOperatorAND && !success {
-			// any failure is a complete failure
-			a.isSuccess = false
-			return
+		nestedResult := a.evalCheck(nested)
+		if a.err != nil {
+			return result
 		}
+		result.CheckResults = append(result.CheckResults, nestedResult)
+		register(nestedResult.Passed)
+	}
+	switch op {
+	case types.ResourceCheckOperatorNOT:
+		result.Passed = evalCount == 1 && successCount == 0
+	case types.ResourceCheckOperatorAND:
+		result.Passed = total > 0 && successCount == total
+	default:
+		result.Passed = successCount > 0
+	}
+	result.Message = fmt.Sprintf(
+		"Passed %d of %d evaluated check(s): Total %d",
+		successCount,
+		evalCount,
+		total,
+	)
+	return result
+}
+
+func (a *Assertion) verifyAllConditions() {
+	result := a.evalCheck(a.Request.Assert.ResourceCheck)
+	if a.err != nil {
+		return
 	}
-	a.isSuccess = atleastOneSuccess
+	a.Result.CheckResult = &result
+	a.isSuccess = result.Passed
+}
+
+// validateResourceCheck verifies the operator & operands of the
+// provided check & its nested checks
+func validateResourceCheck(check types.ResourceCheck) error {
+	if check.CheckOperator != "" &&
+		!types.IsResourceCheckOperatorValid(check.CheckOperator) {
+		return errors.Errorf(
+			"Unsupported check operator %q",
+			check.CheckOperator,
+		)
+	}
+	var count = len(check.SelectChecks) + len(check.Checks)
+	if count == 0 {
+		return errors.Errorf("Check without any select or nested checks")
+	}
+	if check.CheckOperator == types.ResourceCheckOperatorNOT && count != 1 {
+		return errors.Errorf(
+			"Operator %q needs exactly one check: Got %d",
+			check.CheckOperator,
+			count,
+		)
+	}
+	for _, nested := range check.Checks {
+		if err := validateResourceCheck(nested); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (a *Assertion) verifyState() {
@@ -509,6 +593,7 @@ func ExecuteAssertAsConditions(req AssertRequest) (*AssertResponse, error) {
 			ResourceCheck: types.ResourceCheck{
 				CheckOperator: op,
 				SelectChecks:  req.Assert.SelectChecks,
+				Checks:        req.Assert.Checks,
 			},
 		},
 		Resources: req.Resources,
@@ -529,6 +614,7 @@ func ExecuteAssertAsConditions(req AssertRequest) (*AssertResponse, error) {
 				SkippedResourcesInfo: a.Result.SkippedResourcesInfo,
 				HasRunOnce:           pointer.Bool(true),
 				Warns:                a.Result.Warns,
+				CheckResult:          a.Result.CheckResult,
 			},
 		}, nil
 	}
@@ -539,6 +625,7 @@ func ExecuteAssertAsConditions(req AssertRequest) (*AssertResponse, error) {
 			SkippedResourcesInfo: a.Result.SkippedResourcesInfo,
 			HasRunOnce:           pointer.Bool(true),
 			Warns:                a.Result.Warns,
+			CheckResult:          a.Result.CheckResult,
 		},
 	}, nil
 }
@@ -588,13 +675,15 @@ func ExecuteCondition(req AssertRequest) (*AssertResponse, error) {
 			req.TaskKey,
 		)
 	}
-	if len(req.Assert.State) != 0 && len(req.Assert.SelectChecks) != 0 {
+	var hasChecks = len(req.Assert.SelectChecks) != 0 ||
+		len(req.Assert.Checks) != 0
+	if len(req.Assert.State) != 0 && hasChecks {
 		return nil, errors.Errorf(
 			"Can't assert: Both assert state & conditions can't be used together: %s",
 			req.TaskKey,
 		)
 	}
-	if len(req.Assert.State) == 0 && len(req.Assert.SelectChecks) == 0 {
+	if len(req.Assert.State) == 0 && !hasChecks {
 		return nil, errors.Errorf(
 			"Can't assert: Either assert state or conditions need to be set: %s",
 			req.TaskKey,
@@ -615,5 +704,12 @@ func ExecuteCondition(req AssertRequest) (*AssertResponse, error) {
 	if len(req.Assert.State) != 0 {
 		return ExecuteAssertState(req)
 	}
+	if err := validateResourceCheck(req.Assert.ResourceCheck); err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Can't assert: Invalid conditions: %s",
+			req.TaskKey,
+		)
+	}
 	return ExecuteAssertAsConditions(req)
 }
@@ -19,6 +19,8 @@ limitations under the License.
 package run
 
 import (
+	"fmt"
+	"strings"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -2782,3 +2784,199 @@ func TestExecuteAssert(t *testing.T) {
 		})
 	}
 }
+
+func TestExecuteAssertNestedChecks(t *testing.T) {
+	selectCheck := func(app string) types.ResourceSelectCheck {
+		return types.ResourceSelectCheck{
+			Selector: v1alpha1.ResourceSelector{
+				SelectorTerms: []*v1alpha1.SelectorTerm{
+					&v1alpha1.SelectorTerm{
+						MatchLabels: map[string]string{
+							"app": app,
+						},
+					},
+				},
+			},
+		}
+	}
+	resources := []*unstructured.Unstructured{
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"app": "a",
+					},
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{
+						"app": "b",
+					},
+				},
+			},
+		},
+	}
+	var tests = map[string]struct {
+		check         types.ResourceCheck
+		expectedPhase types.ResultPhase
+		expectedTree  string
+		isErr         bool
+	}{
+		"(a and b) or not c": {
+			check: types.ResourceCheck{
+				Checks: []types.ResourceCheck{
+					{
+						CheckOperator: types.ResourceCheckOperatorAND,
+						SelectChecks: []types.ResourceSelectCheck{
+							selectCheck("a"),
+							selectCheck("b"),
+						},
+					},
+					{
+						CheckOperator: types.ResourceCheckOperatorNOT,
+						SelectChecks: []types.ResourceSelectCheck{
+							selectCheck("c"),
+						},
+					},
+				},
+			},
+			expectedPhase: types.ResultPhaseAssertPassed,
+			// OR stops after the first successful check
+			expectedTree: "OR:true[AND:true]",
+		},
+		"(a and c) or not b": {
+			check: types.ResourceCheck{
+				Checks: []types.ResourceCheck{
+					{
+						CheckOperator: types.ResourceCheckOperatorAND,
+						SelectChecks: []types.ResourceSelectCheck{
+							selectCheck("a"),
+							selectCheck("c"),
+						},
+					},
+					{
+						CheckOperator: types.ResourceCheckOperatorNOT,
+						SelectChecks: []types.ResourceSelectCheck{
+							selectCheck("b"),
+						},
+					},
+				},
+			},
+			expectedPhase: types.ResultPhaseAssertFailed,
+			expectedTree:  "OR:false[AND:false NOT:false]",
+		},
+		"a and not (c or d)": {
+			check: types.ResourceCheck{
+				CheckOperator: types.ResourceCheckOperatorAND,
+				SelectChecks: []types.ResourceSelectCheck{
+					selectCheck("a"),
+				},
+				Checks: []types.ResourceCheck{
+					{
+						CheckOperator: types.ResourceCheckOperatorNOT,
+						Checks: []types.ResourceCheck{
+							{
+								SelectChecks: []types.ResourceSelectCheck{
+									selectCheck("c"),
+									selectCheck("d"),
+								},
+							},
+						},
+					},
+				},
+			},
+			expectedPhase: types.ResultPhaseAssertPassed,
+			expectedTree:  "AND:true[NOT:true[OR:false]]",
+		},
+		"not with two checks": {
+			check: types.ResourceCheck{
+				CheckOperator: types.ResourceCheckOperatorNOT,
+				SelectChecks: []types.ResourceSelectCheck{
+					selectCheck("a"),
+					selectCheck("b"),
+				},
+			},
+			isErr: true,
+		},
+		"nested check without operands": {
+			check: types.ResourceCheck{
+				Checks: []types.ResourceCheck{
+					{
+						CheckOperator: types.ResourceCheckOperatorAND,
+					},
+				},
+			},
+			isErr: true,
+		},
+		"invalid nested operator": {
+			check: types.ResourceCheck{
+				Checks: []types.ResourceCheck{
+					{
+						CheckOperator: "XOR",
+						SelectChecks: []types.ResourceSelectCheck{
+							selectCheck("a"),
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+	}
+	// flatten returns the operator & outcome of the nested
+	// results in a compact form
+	var flatten func(r types.ResourceCheckResult) string
+	flatten = func(r types.ResourceCheckResult) string {
+		out := fmt.Sprintf("%s:%t", r.CheckOperator, r.Passed)
+		if len(r.CheckResults) == 0 {
+			return out
+		}
+		var nested []string
+		for _, n := range r.CheckResults {
+			nested = append(nested, flatten(n))
+		}
+		return out + "[" + strings.Join(nested, " ") + "]"
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := ExecuteCondition(AssertRequest{
+				TaskKey: name,
+				Assert: &types.Assert{
+					ResourceCheck: mock.check,
+				},
+				Resources: resources,
+			})
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if got.AssertResult.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected phase %q got %q",
+					mock.expectedPhase,
+					got.AssertResult.Phase,
+				)
+			}
+			if got.AssertResult.CheckResult == nil {
+				t.Fatalf("Expected check result got nil")
+			}
+			tree := flatten(*got.AssertResult.CheckResult)
+			if tree != mock.expectedTree {
+				t.Fatalf(
+					"Expected check result %q got %q",
+					mock.expectedTree,
+					tree,
+				)
+			}
+		})
+	}
+}
@@ -7,6 +7,9 @@ require (
 	github.com/go-resty/resty/v2 v2.2.0
 	github.com/google/go-cmp v0.4.0
 	github.com/pkg/errors v0.9.1
+	github.com/prometheus/client_golang v1.0.0
+	github.com/robfig/cron/v3 v3.0.1
+	k8s.io/api v0.17.3
 	k8s.io/apiextensions-apiserver v0.17.3
 	k8s.io/apimachinery v0.17.3
 	k8s.io/client-go v0.17.3
@@ -21,15 +21,19 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 	metac "openebs.io/metac/start"
 
 	"mayadata.io/d-operators/common/pointer"
+	"mayadata.io/d-operators/pkg/event"
 	"mayadata.io/d-operators/pkg/kubernetes"
 	"mayadata.io/d-operators/pkg/lock"
+	"mayadata.io/d-operators/pkg/metrics"
 	types "mayadata.io/d-operators/types/command"
 	"openebs.io/metac/dynamic/clientset"
 )
@@ -99,6 +103,9 @@ type Reconciliation struct {
 
 	commandStatus *types.CommandStatus
 
+	// recorder records events against the Command resource
+	recorder record.EventRecorder
+
 	// error as value
 	err error
 }
@@ -339,6 +346,7 @@ func NewReconciler(config ReconciliationConfig) (*Reconciliation, error) {
 		command:       config.Command,
 		childJob:      config.Child,
 		commandStatus: &types.CommandStatus{},
+		recorder:      event.Recorder(),
 	}
 	// initialize fields
 	err := r.init()
@@ -363,7 +371,7 @@ func (r *Reconciliation) createChildJob() (types.CommandStatus, error) {
 			r.childJob.GetName(),
 		)
 	}
-	return types.CommandStatus{
+	status := types.CommandStatus{
 		Phase: types.CommandPhaseJobCreated,
 		Message: fmt.Sprintf(
 			"Command Job created: %q %q: %q",
@@ -371,7 +379,9 @@ func (r *Reconciliation) createChildJob() (types.CommandStatus, error) {
 			got.GetName(),
 			got.GetUID(),
 		),
-	}, nil
+	}
+	r.recordEvent(corev1.EventTypeNormal, event.ReasonJobCreated, status.Message)
+	return status, nil
 }
 
 func (r *Reconciliation) isChildJobAvailable() (*unstructured.Unstructured, bool, error) {
@@ -423,6 +433,56 @@ func (r *Reconciliation) deleteChildJob() (types.CommandStatus, error) {
 	}, nil
 }
 
+// recordEvent records an event against the Command resource
+func (r *Reconciliation) recordEvent(eventtype, reason, message string) {
+	if r.recorder == nil {
+		return
+	}
+	r.recorder.Event(
+		event.NewReference(r.command.TypeMeta, &r.command),
+		eventtype,
+		reason,
+		message,
+	)
+}
+
+// observeCompletedChildJob records the metrics & event of the
+// completed child job based on the status reported by this job
+func (r *Reconciliation) observeCompletedChildJob() {
+	var exitCodes []int
+	for _, output := range r.command.Status.Outputs {
+		exitCodes = append(exitCodes, output.Exit)
+	}
+	metrics.ObserveCommandJob(
+		r.command.GetNamespace(),
+		r.command.GetName(),
+		string(r.command.Status.Phase),
+		r.command.Status.ExecutionTime.ValueInSeconds,
+		exitCodes,
+	)
+	var (
+		eventtype = corev1.EventTypeNormal
+		reason    = event.ReasonJobCompleted
+	)
+	if r.command.Status.Phase == types.CommandPhaseError ||
+		r.command.Status.Phase == types.CommandPhaseTimedOut {
+		eventtype = corev1.EventTypeWarning
+		reason = event.ReasonJobFailed
+	}
+	r.recordEvent(
+		eventtype,
+		reason,
+		fmt.Sprintf(
+			"Command Job %q completed with phase %s in %s: Errors %d: Timeouts %d",
+			r.childJob.GetName(),
+			r.command.Status.Phase,
+			r.command.Status.ExecutionTime.ReadableValue,
+			r.command.Status.Counter.ErrorCount,
+			r.command.Status.Counter.TimeoutCount,
+		),
+	)
+}
+
 func (r *Reconciliation) reconcileRunOnceCommand() (types.CommandStatus, error) {
 	klog.V(1).Infof(
 		"Reconcile started: Run once: Command %q / %q",
@@ -461,6 +521,7 @@ func (r *Reconciliation) reconcileRunOnceCommand() (types.CommandStatus, error)
 			r.command.GetNamespace(),
 			r.command.GetName(),
 		)
+		r.observeCompletedChildJob()
 		return r.deleteChildJob()
 	}
 	klog.V(1).Infof(
@@ -497,6 +558,7 @@ func (r *Reconciliation) reconcileRunAlwaysCommand() (types.CommandStatus, error
 			r.command.GetNamespace(),
 			r.command.GetName(),
 		)
+		r.observeCompletedChildJob()
 		_, err := r.deleteChildJob()
 		if err != nil {
 			return types.CommandStatus{}, err
@@ -586,6 +648,11 @@ func (r *Reconciliation) Reconcile() (status types.CommandStatus, err error) {
 			r.command.GetNamespace(),
 			r.command.GetName(),
 		)
+		metrics.IncLockContention(
+			"Command",
+			r.command.GetNamespace(),
+			r.command.GetName(),
+		)
 		return types.CommandStatus{
 			Phase: types.CommandPhaseLocked,
 		}, nil
@@ -0,0 +1,100 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package command
+
+import (
+	"fmt"
+	"strings"
+
+	"mayadata.io/d-operators/pkg/schema"
+	types "mayadata.io/d-operators/types/command"
+)
+
+// commandValueRules validate the values of individual Command fields
+var commandValueRules = []schema.ValueRule{
+	{
+		Path: "spec.enabled.when",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.EnabledAlways),
+			string(types.EnabledNever),
+			string(types.EnabledOnce),
+		},
+	},
+	{Path: "spec.template.job", Type: schema.ValueTypeMap},
+	{Path: "spec.env", Type: schema.ValueTypeMap},
+	{Path: "spec.mustRunAllCommands", Type: schema.ValueTypeBool},
+	{Path: "spec.timeoutInSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.resync.intervalInSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.resync.onErrorResyncInSeconds", Type: schema.ValueTypeInt},
+	{
+		Path: "spec.retry.when",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.RetryOnError),
+			string(types.RetryOnTimeout),
+		},
+	},
+	{Path: "spec.commands", Type: schema.ValueTypeList, Required: true},
+	{Path: "spec.commands.[*].name", Type: schema.ValueTypeString, Required: true},
+	{Path: "spec.commands.[*].desc", Type: schema.ValueTypeString},
+	{Path: "spec.commands.[*].cmd", Type: schema.ValueTypeList},
+	{Path: "spec.commands.[*].script", Type: schema.ValueTypeString},
+	{Path: "spec.commands.[*].timeoutInSeconds", Type: schema.ValueTypeInt},
+}
+
+// validateCMDOrScript verifies if the command has exactly one of
+// cmd & script
+func validateCMDOrScript(path string, cmd map[string]interface{}) []schema.ErrorMessage {
+	set := schema.SetFields(cmd, "cmd", "script")
+	if len(set) == 1 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid command: Path %q: Want exactly one of cmd & script got %d [%s]",
+				path,
+				len(set),
+				strings.Join(set, ", "),
+			),
+			Remedy: "Set either cmd or script",
+		},
+	}
+}
+
+// commandObjectRules validate the relations between Command fields
+var commandObjectRules = []schema.ObjectRule{
+	{Path: "spec.commands.[*]", Validate: validateCMDOrScript},
+}
+
+// ValueRules returns the rules that validate the values of
+// individual Command fields
+func ValueRules() []schema.ValueRule {
+	return commandValueRules
+}
+
+// ValidateSchemaValues validates the types, enums, required fields
+// & relations between the fields of the provided Command
+func ValidateSchemaValues(command map[string]interface{}) *schema.FieldPathValidationResult {
+	v := &schema.ValueValidation{
+		Target:      command,
+		ValueRules:  commandValueRules,
+		ObjectRules: commandObjectRules,
+	}
+	return v.Validate()
+}
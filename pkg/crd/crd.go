@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"mayadata.io/d-operators/pkg/schema"
+)
+
+// Definition defines a custom resource whose CRD gets generated
+type Definition struct {
+	Group      string
+	Version    string
+	Kind       string
+	Plural     string
+	Singular   string
+	ShortNames []string
+
+	// Scope defaults to Namespaced
+	Scope apiextv1.ResourceScope
+
+	// Type is the Go type of this custom resource. Schema of this
+	// custom resource is derived from this type.
+	//
+	// NOTE:
+	//	Schema allows any fields if this is not set
+	Type reflect.Type
+
+	// ValueRules set the enums & required fields of the schema
+	ValueRules []schema.ValueRule
+
+	// PreserveUnknownSpecFields preserves the unknown fields found
+	// at any object of the spec instead of pruning them. This lets
+	// the controller report these fields e.g. as an invalid schema.
+	PreserveUnknownSpecFields bool
+
+	// Status subresource is enabled if set to true
+	HasStatusSubresource bool
+
+	PrinterColumns []apiextv1.CustomResourceColumnDefinition
+}
+
+// Generator generates CustomResourceDefinitions
+type Generator struct {
+	SchemaGenerator
+}
+
+// NewGenerator returns a new instance of Generator. Descriptions &
+// defaults are set from the Go sources that are resolved from the
+// provided directory. These are skipped if directory is empty.
+func NewGenerator(srcDir string) *Generator {
+	g := &Generator{}
+	if srcDir != "" {
+		g.Docs = NewDocsLoader(srcDir)
+	}
+	return g
+}
+
+// Generate returns the CustomResourceDefinition of the provided
+// definition
+func (g *Generator) Generate(def Definition) (*apiextv1.CustomResourceDefinition, error) {
+	props := &apiextv1.JSONSchemaProps{
+		Type:                   "object",
+		XPreserveUnknownFields: boolPtr(true),
+	}
+	if def.Type != nil {
+		var err error
+		props, err = g.Schema(def.Type)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to generate schema: Kind %q", def.Kind)
+		}
+		doc, err := g.lookup(def.Type)
+		if err != nil {
+			return nil, err
+		}
+		props.Description = doc.Description
+		err = ApplyValueRules(props, def.ValueRules)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Kind %q", def.Kind)
+		}
+		if def.PreserveUnknownSpecFields {
+			spec, found := props.Properties["spec"]
+			if !found {
+				return nil, errors.Errorf("Missing spec in schema: Kind %q", def.Kind)
+			}
+			PreserveUnknownFields(&spec)
+			props.Properties["spec"] = spec
+		}
+	}
+	scope := def.Scope
+	if scope == "" {
+		scope = apiextv1.NamespaceScoped
+	}
+	var subresources *apiextv1.CustomResourceSubresources
+	if def.HasStatusSubresource {
+		subresources = &apiextv1.CustomResourceSubresources{
+			Status: &apiextv1.CustomResourceSubresourceStatus{},
+		}
+	}
+	return &apiextv1.CustomResourceDefinition{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: apiextv1.SchemeGroupVersion.String(),
+			Kind:       "CustomResourceDefinition",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: def.Plural + "." + def.Group,
+		},
+		Spec: apiextv1.CustomResourceDefinitionSpec{
+			Group: def.Group,
+			Names: apiextv1.CustomResourceDefinitionNames{
+				Kind:       def.Kind,
+				ListKind:   def.Kind + "List",
+				Plural:     def.Plural,
+				Singular:   def.Singular,
+				ShortNames: def.ShortNames,
+			},
+			Scope: scope,
+			Versions: []apiextv1.CustomResourceDefinitionVersion{
+				{
+					Name:    def.Version,
+					Served:  true,
+					Storage: true,
+					Schema: &apiextv1.CustomResourceValidation{
+						OpenAPIV3Schema: props,
+					},
+					Subresources:             subresources,
+					AdditionalPrinterColumns: def.PrinterColumns,
+				},
+			},
+		},
+	}, nil
+}
+
+// PreserveUnknownFields sets the provided schema & every object
+// schema nested in it to preserve their unknown fields
+//
+// NOTE:
+//	Known fields are still validated against their types. Maps are
+// skipped since any of their keys are allowed.
+func PreserveUnknownFields(props *apiextv1.JSONSchemaProps) {
+	if props.Type == "object" && props.AdditionalProperties == nil {
+		props.XPreserveUnknownFields = boolPtr(true)
+	}
+	for name, nested := range props.Properties {
+		PreserveUnknownFields(&nested)
+		props.Properties[name] = nested
+	}
+	if props.Items != nil && props.Items.Schema != nil {
+		PreserveUnknownFields(props.Items.Schema)
+	}
+	if props.AdditionalProperties != nil && props.AdditionalProperties.Schema != nil {
+		PreserveUnknownFields(props.AdditionalProperties.Schema)
+	}
+}
+
+// GenerateUnstructured returns the CustomResourceDefinition of the
+// provided definition in its unstructured form
+//
+// NOTE:
+//	Status & creation timestamp that get set due to json encoding of
+// the typed instance are removed
+func (g *Generator) GenerateUnstructured(def Definition) (*unstructured.Unstructured, error) {
+	crd, err := g.Generate(def)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(crd)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to marshal CRD: Kind %q", def.Kind)
+	}
+	var obj map[string]interface{}
+	err = json.Unmarshal(raw, &obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to unmarshal CRD: Kind %q", def.Kind)
+	}
+	delete(obj, "status")
+	unstructured.RemoveNestedField(obj, "metadata", "creationTimestamp")
+	return &unstructured.Unstructured{Object: obj}, nil
+}
+
+// GenerateYAML returns the CustomResourceDefinitions of the provided
+// definitions as a multi document YAML
+func (g *Generator) GenerateYAML(header string, defs ...Definition) ([]byte, error) {
+	var buf bytes.Buffer
+	for _, line := range strings.Split(strings.TrimSpace(header), "\n") {
+		if line == "" {
+			continue
+		}
+		buf.WriteString("# " + line + "\n")
+	}
+	for _, def := range defs {
+		crd, err := g.GenerateUnstructured(def)
+		if err != nil {
+			return nil, err
+		}
+		raw, err := yaml.Marshal(crd.Object)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to marshal CRD to YAML: Kind %q", def.Kind)
+		}
+		buf.WriteString("---\n")
+		buf.Write(raw)
+	}
+	return buf.Bytes(), nil
+}
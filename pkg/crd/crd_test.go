@@ -0,0 +1,350 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"bytes"
+	"io/ioutil"
+	"reflect"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apiextensions-apiserver/pkg/apis/apiextensions"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	structuralschema "k8s.io/apiextensions-apiserver/pkg/apiserver/schema"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"mayadata.io/d-operators/pkg/schema"
+)
+
+// testNode refers to itself
+type testNode struct {
+	Name  string     `json:"name"`
+	Nodes []testNode `json:"nodes,omitempty"`
+}
+
+// testInline is inlined into testSpec
+type testInline struct {
+	Inlined string `json:"inlined,omitempty"`
+}
+
+// testSpec is used to verify the generated schema
+type testSpec struct {
+	testInline
+
+	// Count of things
+	//
+	// +default=3
+	Count *int `json:"count,omitempty"`
+
+	Size    int64                      `json:"size"`
+	Ratio   float64                    `json:"ratio"`
+	Enabled bool                       `json:"enabled"`
+	When    string                     `json:"when,omitempty"`
+	Labels  map[string]string          `json:"labels,omitempty"`
+	Any     interface{}                `json:"any,omitempty"`
+	AnyMap  map[string]interface{}     `json:"anyMap,omitempty"`
+	State   *unstructured.Unstructured `json:"state"`
+	Time    *metav1.Time               `json:"time,omitempty"`
+	Node    testNode                   `json:"node"`
+	Data    []byte                     `json:"data,omitempty"`
+	Skipped string                     `json:"-"`
+	private string
+}
+
+func TestSchemaGeneratorSchema(t *testing.T) {
+	g := NewGenerator(".")
+	got, err := g.Schema(reflect.TypeOf(testSpec{}))
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	var tests = map[string]struct {
+		expected apiextv1.JSONSchemaProps
+	}{
+		"inlined": {
+			expected: apiextv1.JSONSchemaProps{Type: "string"},
+		},
+		"count": {
+			expected: apiextv1.JSONSchemaProps{
+				Type:        "integer",
+				Description: "Count of things",
+				Default:     &apiextv1.JSON{Raw: []byte("3")},
+			},
+		},
+		"size": {
+			expected: apiextv1.JSONSchemaProps{Type: "integer", Format: "int64"},
+		},
+		"ratio": {
+			expected: apiextv1.JSONSchemaProps{Type: "number"},
+		},
+		"enabled": {
+			expected: apiextv1.JSONSchemaProps{Type: "boolean"},
+		},
+		"when": {
+			expected: apiextv1.JSONSchemaProps{Type: "string"},
+		},
+		"labels": {
+			expected: apiextv1.JSONSchemaProps{
+				Type: "object",
+				AdditionalProperties: &apiextv1.JSONSchemaPropsOrBool{
+					Allows: true,
+					Schema: &apiextv1.JSONSchemaProps{Type: "string"},
+				},
+			},
+		},
+		"any": {
+			expected: apiextv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)},
+		},
+		"anyMap": {
+			expected: apiextv1.JSONSchemaProps{
+				Type:                   "object",
+				XPreserveUnknownFields: boolPtr(true),
+			},
+		},
+		"state": {
+			expected: apiextv1.JSONSchemaProps{
+				Type:                   "object",
+				XPreserveUnknownFields: boolPtr(true),
+			},
+		},
+		"time": {
+			expected: apiextv1.JSONSchemaProps{Type: "string", Format: "date-time"},
+		},
+		"node": {
+			expected: apiextv1.JSONSchemaProps{
+				Type:        "object",
+				Description: "testNode refers to itself",
+				Properties: map[string]apiextv1.JSONSchemaProps{
+					"name": {Type: "string"},
+					"nodes": {
+						Type:        "array",
+						Description: "testNode refers to itself",
+						Items: &apiextv1.JSONSchemaPropsOrArray{
+							Schema: &apiextv1.JSONSchemaProps{
+								Type:                   "object",
+								XPreserveUnknownFields: boolPtr(true),
+							},
+						},
+					},
+				},
+			},
+		},
+		"data": {
+			expected: apiextv1.JSONSchemaProps{Type: "string", Format: "byte"},
+		},
+	}
+	if len(got.Properties) != len(tests) {
+		t.Fatalf(
+			"Expected %d properties got %d: %v",
+			len(tests),
+			len(got.Properties),
+			got.Properties,
+		)
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			if diff := cmp.Diff(mock.expected, got.Properties[name]); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestApplyValueRules(t *testing.T) {
+	var tests = map[string]struct {
+		rules            []schema.ValueRule
+		expectedRequired []string
+		expectedEnum     []apiextv1.JSON
+		isErr            bool
+	}{
+		"required & enum": {
+			rules: []schema.ValueRule{
+				{Path: "node.nodes.[*].name", Required: true, Enum: []string{"a", "b"}},
+			},
+			expectedRequired: []string{"name"},
+			expectedEnum: []apiextv1.JSON{
+				{Raw: []byte(`"a"`)},
+				{Raw: []byte(`"b"`)},
+			},
+		},
+		"rule without enum & required is ignored": {
+			rules: []schema.ValueRule{
+				{Path: "junk", Type: schema.ValueTypeString},
+			},
+		},
+		"missing field": {
+			rules: []schema.ValueRule{
+				{Path: "node.junk", Required: true},
+			},
+			isErr: true,
+		},
+		"not a list": {
+			rules: []schema.ValueRule{
+				{Path: "node.[*].name", Required: true},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			props := &apiextv1.JSONSchemaProps{
+				Type: "object",
+				Properties: map[string]apiextv1.JSONSchemaProps{
+					"node": {
+						Type: "object",
+						Properties: map[string]apiextv1.JSONSchemaProps{
+							"nodes": {
+								Type: "array",
+								Items: &apiextv1.JSONSchemaPropsOrArray{
+									Schema: &apiextv1.JSONSchemaProps{
+										Type: "object",
+										Properties: map[string]apiextv1.JSONSchemaProps{
+											"name": {Type: "string"},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			}
+			err := ApplyValueRules(props, mock.rules)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			item := props.Properties["node"].Properties["nodes"].Items.Schema
+			if diff := cmp.Diff(mock.expectedRequired, item.Required); diff != "" {
+				t.Fatalf("Expected no diff in required got:\n%s", diff)
+			}
+			if diff := cmp.Diff(mock.expectedEnum, item.Properties["name"].Enum); diff != "" {
+				t.Fatalf("Expected no diff in enum got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestDopeDefinitionsAreStructural(t *testing.T) {
+	g := NewGenerator(".")
+	for _, def := range DopeDefinitions {
+		def := def
+		t.Run(def.Kind, func(t *testing.T) {
+			crd, err := g.Generate(def)
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			var props apiextensions.JSONSchemaProps
+			err = apiextv1.Convert_v1_JSONSchemaProps_To_apiextensions_JSONSchemaProps(
+				crd.Spec.Versions[0].Schema.OpenAPIV3Schema,
+				&props,
+				nil,
+			)
+			if err != nil {
+				t.Fatalf("Expected no conversion error got %+v", err)
+			}
+			structural, err := structuralschema.NewStructural(&props)
+			if err != nil {
+				t.Fatalf("Expected structural schema got %+v", err)
+			}
+			errs := structuralschema.ValidateStructural(nil, structural)
+			if len(errs) != 0 {
+				t.Fatalf("Expected valid structural schema got %+v", errs.ToAggregate())
+			}
+		})
+	}
+}
+
+func TestGeneratePreserveUnknownSpecFields(t *testing.T) {
+	var tests = map[string]struct {
+		isPreserve bool
+	}{
+		"unknown spec fields are pruned": {},
+		"unknown spec fields are preserved": {
+			isPreserve: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			crd, err := NewGenerator("").Generate(Definition{
+				Group:    "test.io",
+				Version:  "v1",
+				Kind:     "Test",
+				Plural:   "tests",
+				Singular: "test",
+				Type: reflect.TypeOf(struct {
+					Spec   testSpec   `json:"spec"`
+					Status testInline `json:"status"`
+				}{}),
+				PreserveUnknownSpecFields: mock.isPreserve,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			props := crd.Spec.Versions[0].Schema.OpenAPIV3Schema
+			isPreserved := func(p apiextv1.JSONSchemaProps) bool {
+				return p.XPreserveUnknownFields != nil && *p.XPreserveUnknownFields
+			}
+			spec := props.Properties["spec"]
+			for path, got := range map[string]bool{
+				"spec":      isPreserved(spec),
+				"spec.node": isPreserved(spec.Properties["node"]),
+			} {
+				if got != mock.isPreserve {
+					t.Fatalf(
+						"Expected preserve unknown fields %t at %q got %t",
+						mock.isPreserve,
+						path,
+						got,
+					)
+				}
+			}
+			if isPreserved(*props) || isPreserved(props.Properties["status"]) {
+				t.Fatalf("Expected root & status to prune unknown fields")
+			}
+		})
+	}
+}
+
+func TestGeneratedCRDsAreUpToDate(t *testing.T) {
+	got, err := NewGenerator(".").GenerateYAML(GeneratedHeader, DopeDefinitions...)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	existing, err := ioutil.ReadFile("../../deploy/crd.yaml")
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if !bytes.Equal(got, existing) {
+		t.Fatalf(
+			"Expected deploy/crd.yaml to be up to date: Run 'make generate-crds'",
+		)
+	}
+}
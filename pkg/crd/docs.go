@@ -0,0 +1,186 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MarkerDefault is the comment marker that sets the default value
+// of a field. Value is specified in its json form.
+//
+// e.g.
+//	// +default=10
+//	// +default="Once"
+const MarkerDefault = "+default="
+
+// Doc is the documentation of a Go type or a struct field
+type Doc struct {
+	// Description is the comment without any markers
+	Description string
+
+	// Markers are the comment lines starting with '+'
+	Markers []string
+}
+
+// Marker returns the value of the marker with the provided
+// prefix if available
+func (d Doc) Marker(prefix string) (string, bool) {
+	for _, m := range d.Markers {
+		if strings.HasPrefix(m, prefix) {
+			return strings.TrimPrefix(m, prefix), true
+		}
+	}
+	return "", false
+}
+
+// Docs is a lookup of documentation of Go types & their fields
+//
+// NOTE:
+//	A type is keyed as <package path>.<type name> while a field
+// is keyed as <package path>.<type name>.<field name>
+type Docs map[string]Doc
+
+// DocsLoader loads the documentation of Go packages from their
+// source files
+type DocsLoader struct {
+	// SrcDir is the directory from where package paths are
+	// resolved. This should be within the Go module.
+	SrcDir string
+
+	docs   Docs
+	loaded map[string]bool
+}
+
+// NewDocsLoader returns a new instance of DocsLoader
+func NewDocsLoader(srcDir string) *DocsLoader {
+	return &DocsLoader{
+		SrcDir: srcDir,
+		docs:   Docs{},
+		loaded: map[string]bool{},
+	}
+}
+
+// Lookup returns the documentation of the provided type or field
+//
+// NOTE:
+//	Package's source files are parsed on first lookup
+func (l *DocsLoader) Lookup(pkgPath string, names ...string) (Doc, error) {
+	if !l.loaded[pkgPath] {
+		err := l.load(pkgPath)
+		if err != nil {
+			return Doc{}, err
+		}
+		l.loaded[pkgPath] = true
+	}
+	key := strings.Join(append([]string{pkgPath}, names...), ".")
+	return l.docs[key], nil
+}
+
+func (l *DocsLoader) load(pkgPath string) error {
+	pkg, err := build.Import(pkgPath, l.SrcDir, build.FindOnly)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to find package %q", pkgPath)
+	}
+	fset := token.NewFileSet()
+	pkgs, err := parser.ParseDir(fset, pkg.Dir, nil, parser.ParseComments)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to parse package %q", pkgPath)
+	}
+	for _, p := range pkgs {
+		for _, file := range p.Files {
+			l.loadFile(pkgPath, file)
+		}
+	}
+	return nil
+}
+
+func (l *DocsLoader) loadFile(pkgPath string, file *ast.File) {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				// comment is attached to the declaration
+				doc = gen.Doc
+			}
+			typeKey := pkgPath + "." + ts.Name.Name
+			l.docs[typeKey] = newDoc(doc)
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			for _, field := range st.Fields.List {
+				fieldDoc := field.Doc
+				if fieldDoc == nil {
+					// trailing comment is used if any
+					fieldDoc = field.Comment
+				}
+				for _, name := range field.Names {
+					l.docs[typeKey+"."+name.Name] = newDoc(fieldDoc)
+				}
+			}
+		}
+	}
+}
+
+// newDoc builds the documentation from the provided comment by
+// separating the markers from the description
+func newDoc(comment *ast.CommentGroup) Doc {
+	var doc Doc
+	if comment == nil {
+		return doc
+	}
+	// lines of a paragraph are joined into a single line
+	var paragraphs []string
+	var lines []string
+	for _, line := range strings.Split(comment.Text(), "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "+") {
+			doc.Markers = append(doc.Markers, trimmed)
+			continue
+		}
+		if strings.HasPrefix(trimmed, "---") {
+			// rest of the comment is not meant for the users
+			break
+		}
+		if trimmed == "" {
+			if len(lines) != 0 {
+				paragraphs = append(paragraphs, strings.Join(lines, " "))
+				lines = nil
+			}
+			continue
+		}
+		lines = append(lines, trimmed)
+	}
+	if len(lines) != 0 {
+		paragraphs = append(paragraphs, strings.Join(lines, " "))
+	}
+	doc.Description = strings.Join(paragraphs, "\n\n")
+	return doc
+}
@@ -0,0 +1,215 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"reflect"
+
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+
+	"mayadata.io/d-operators/pkg/command"
+	dopehttp "mayadata.io/d-operators/pkg/http"
+	"mayadata.io/d-operators/pkg/recipe"
+	commandtypes "mayadata.io/d-operators/types/command"
+	"mayadata.io/d-operators/types/gvk"
+	httptypes "mayadata.io/d-operators/types/http"
+	recipetypes "mayadata.io/d-operators/types/recipe"
+)
+
+// GeneratedHeader is the header of the generated CRDs file
+const GeneratedHeader = `
+Code generated by cmd/crd-gen. DO NOT EDIT.
+
+Run 'make generate-crds' to regenerate these CRDs from the
+Go types of dope custom resources.
+`
+
+// RecipeDefinition defines the Recipe custom resource
+var RecipeDefinition = Definition{
+	Group:                gvk.GroupDopeMayadataIO,
+	Version:              gvk.VersionV1,
+	Kind:                 gvk.KindRecipe,
+	Plural:               "recipes",
+	Singular:             "recipe",
+	ShortNames:           []string{"rcp"},
+	Type:                 reflect.TypeOf(recipetypes.Recipe{}),
+	ValueRules:           recipe.ValueRules(),
+	HasStatusSubresource: true,
+	// unknown fields are reported as InvalidSchema by the Recipe
+	// controller
+	PreserveUnknownSpecFields: true,
+	PrinterColumns: []apiextv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Age",
+			Type:        "date",
+			Description: "Age of this Recipe",
+			JSONPath:    ".metadata.creationTimestamp",
+		},
+		{
+			Name:        "TimeTaken",
+			Type:        "string",
+			Description: "Time taken to execute this Recipe",
+			JSONPath:    ".status.executionTime.readableValue",
+		},
+		{
+			Name:        "Status",
+			Type:        "string",
+			Description: "Current phase of this Recipe",
+			JSONPath:    ".status.phase",
+		},
+		{
+			Name:        "Reason",
+			Type:        "string",
+			Description: "Description of this phase",
+			JSONPath:    ".status.reason",
+		},
+		{
+			Name:        "NextRun",
+			Type:        "date",
+			Description: "Next scheduled run of this Recipe",
+			JSONPath:    ".status.schedule.nextScheduleTime",
+			Priority:    1,
+		},
+		{
+			Name:        "LockHolder",
+			Type:        "string",
+			Description: "Holder of the lock taken to execute this Recipe",
+			JSONPath:    ".status.lock.holderIdentity",
+			Priority:    1,
+		},
+		{
+			Name:        "LockAge",
+			Type:        "date",
+			Description: "Age of the lock taken to execute this Recipe",
+			JSONPath:    ".status.lock.acquireTime",
+			Priority:    1,
+		},
+	},
+}
+
+// RecipeTemplateDefinition defines the RecipeTemplate custom resource
+var RecipeTemplateDefinition = Definition{
+	Group:      gvk.GroupDopeMayadataIO,
+	Version:    gvk.VersionV1,
+	Kind:       gvk.KindRecipeTemplate,
+	Plural:     "recipetemplates",
+	Singular:   "recipetemplate",
+	ShortNames: []string{"rcpt"},
+	Type:       reflect.TypeOf(recipetypes.RecipeTemplate{}),
+	ValueRules: recipe.TemplateValueRules(),
+	PrinterColumns: []apiextv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Age",
+			Type:        "date",
+			Description: "Age of this RecipeTemplate",
+			JSONPath:    ".metadata.creationTimestamp",
+		},
+	},
+}
+
+// HTTPDefinition defines the HTTP custom resource
+var HTTPDefinition = Definition{
+	Group:                gvk.GroupDopeMayadataIO,
+	Version:              gvk.VersionV1,
+	Kind:                 gvk.KindHTTP,
+	Plural:               "https",
+	Singular:             "http",
+	ShortNames:           []string{"http"},
+	Type:                 reflect.TypeOf(httptypes.HTTP{}),
+	ValueRules:           dopehttp.ValueRules(),
+	HasStatusSubresource: true,
+	PrinterColumns: []apiextv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Age",
+			Type:        "date",
+			Description: "Age of this HTTP",
+			JSONPath:    ".metadata.creationTimestamp",
+		},
+		{
+			Name:        "Status",
+			Type:        "string",
+			Description: "Current phase of this HTTP",
+			JSONPath:    ".status.phase",
+		},
+		{
+			Name:        "Code",
+			Type:        "integer",
+			Description: "Status code of the http response",
+			JSONPath:    ".status.response.httpStatusCode",
+		},
+		{
+			Name:        "Reason",
+			Type:        "string",
+			Description: "Description of this phase",
+			JSONPath:    ".status.reason",
+		},
+		{
+			Name:        "URL",
+			Type:        "string",
+			Description: "URL that gets invoked",
+			JSONPath:    ".spec.url",
+			Priority:    1,
+		},
+	},
+}
+
+// CommandDefinition defines the Command custom resource
+var CommandDefinition = Definition{
+	Group:                gvk.GroupDopeMayadataIO,
+	Version:              gvk.VersionV1,
+	Kind:                 gvk.KindCommand,
+	Plural:               "commands",
+	Singular:             "command",
+	ShortNames:           []string{"cmds"},
+	Type:                 reflect.TypeOf(commandtypes.Command{}),
+	ValueRules:           command.ValueRules(),
+	HasStatusSubresource: true,
+	PrinterColumns: []apiextv1.CustomResourceColumnDefinition{
+		{
+			Name:        "Age",
+			Type:        "date",
+			Description: "Age of this Command",
+			JSONPath:    ".metadata.creationTimestamp",
+		},
+		{
+			Name:        "TimeTaken",
+			Type:        "string",
+			Description: "Time taken to execute this Command",
+			JSONPath:    ".status.timetakenInSeconds.readableValue",
+		},
+		{
+			Name:        "Status",
+			Type:        "string",
+			Description: "Current phase of this Command",
+			JSONPath:    ".status.phase",
+		},
+		{
+			Name:        "Reason",
+			Type:        "string",
+			Description: "Description of this phase",
+			JSONPath:    ".status.reason",
+		},
+	},
+}
+
+// DopeDefinitions are the custom resources managed by dope
+var DopeDefinitions = []Definition{
+	RecipeDefinition,
+	RecipeTemplateDefinition,
+	HTTPDefinition,
+	CommandDefinition,
+}
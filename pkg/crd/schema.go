@@ -0,0 +1,352 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crd
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	"mayadata.io/d-operators/pkg/schema"
+)
+
+var (
+	typeTime         = reflect.TypeOf(metav1.Time{})
+	typeDuration     = reflect.TypeOf(metav1.Duration{})
+	typeObjectMeta   = reflect.TypeOf(metav1.ObjectMeta{})
+	typeUnstructured = reflect.TypeOf(unstructured.Unstructured{})
+	typeIntOrString  = reflect.TypeOf(intstr.IntOrString{})
+)
+
+// SchemaGenerator builds OpenAPI v3 schemas from Go types
+//
+// NOTE:
+//	Schemas are derived from the json encoding of the Go types.
+// Descriptions & defaults are set only if Docs is set.
+type SchemaGenerator struct {
+	// Docs provides the descriptions & markers of Go types &
+	// their fields
+	Docs *DocsLoader
+}
+
+// Schema returns the schema of the provided Go type
+func (g *SchemaGenerator) Schema(t reflect.Type) (*apiextv1.JSONSchemaProps, error) {
+	return g.schemaOf(t, map[reflect.Type]bool{})
+}
+
+// lookup returns the documentation of the provided type or field
+func (g *SchemaGenerator) lookup(t reflect.Type, field ...string) (Doc, error) {
+	if g.Docs == nil || t.PkgPath() == "" || t.Name() == "" {
+		return Doc{}, nil
+	}
+	return g.Docs.Lookup(t.PkgPath(), append([]string{t.Name()}, field...)...)
+}
+
+// schemaOf returns the schema of the provided type. Visiting keeps
+// track of the struct types that are being built to detect
+// recursive types.
+//
+// NOTE:
+//	Structural schemas can't be recursive. Hence, a type that refers
+// to itself is allowed to hold any fields from that point onwards.
+func (g *SchemaGenerator) schemaOf(
+	t reflect.Type,
+	visiting map[reflect.Type]bool,
+) (*apiextv1.JSONSchemaProps, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t {
+	case typeTime:
+		return &apiextv1.JSONSchemaProps{Type: "string", Format: "date-time"}, nil
+	case typeDuration:
+		return &apiextv1.JSONSchemaProps{Type: "string"}, nil
+	case typeObjectMeta:
+		return &apiextv1.JSONSchemaProps{Type: "object"}, nil
+	case typeUnstructured:
+		return &apiextv1.JSONSchemaProps{
+			Type:                   "object",
+			XPreserveUnknownFields: boolPtr(true),
+		}, nil
+	case typeIntOrString:
+		return &apiextv1.JSONSchemaProps{XIntOrString: true}, nil
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return &apiextv1.JSONSchemaProps{Type: "string"}, nil
+	case reflect.Bool:
+		return &apiextv1.JSONSchemaProps{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Uint,
+		reflect.Uint8, reflect.Uint16:
+		return &apiextv1.JSONSchemaProps{Type: "integer"}, nil
+	case reflect.Int32, reflect.Uint32:
+		return &apiextv1.JSONSchemaProps{Type: "integer", Format: "int32"}, nil
+	case reflect.Int64, reflect.Uint64:
+		return &apiextv1.JSONSchemaProps{Type: "integer", Format: "int64"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &apiextv1.JSONSchemaProps{Type: "number"}, nil
+	case reflect.Interface:
+		// any value is allowed
+		return &apiextv1.JSONSchemaProps{XPreserveUnknownFields: boolPtr(true)}, nil
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// bytes are encoded as base64 strings
+			return &apiextv1.JSONSchemaProps{Type: "string", Format: "byte"}, nil
+		}
+		items, err := g.schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &apiextv1.JSONSchemaProps{
+			Type:  "array",
+			Items: &apiextv1.JSONSchemaPropsOrArray{Schema: items},
+		}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, errors.Errorf(
+				"Unsupported map key: Want string got %s: %s",
+				t.Key().Kind(),
+				t,
+			)
+		}
+		if t.Elem().Kind() == reflect.Interface {
+			return &apiextv1.JSONSchemaProps{
+				Type:                   "object",
+				XPreserveUnknownFields: boolPtr(true),
+			}, nil
+		}
+		values, err := g.schemaOf(t.Elem(), visiting)
+		if err != nil {
+			return nil, err
+		}
+		return &apiextv1.JSONSchemaProps{
+			Type:                 "object",
+			AdditionalProperties: &apiextv1.JSONSchemaPropsOrBool{Allows: true, Schema: values},
+		}, nil
+	case reflect.Struct:
+		if visiting[t] {
+			return &apiextv1.JSONSchemaProps{
+				Type:                   "object",
+				XPreserveUnknownFields: boolPtr(true),
+			}, nil
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		props := &apiextv1.JSONSchemaProps{
+			Type:       "object",
+			Properties: map[string]apiextv1.JSONSchemaProps{},
+		}
+		err := g.setProperties(props, t, visiting)
+		if err != nil {
+			return nil, err
+		}
+		return props, nil
+	default:
+		return nil, errors.Errorf("Unsupported type %s: Kind %s", t, t.Kind())
+	}
+}
+
+// setProperties sets the provided schema with the properties
+// derived from the fields of the provided struct type
+func (g *SchemaGenerator) setProperties(
+	props *apiextv1.JSONSchemaProps,
+	t reflect.Type,
+	visiting map[reflect.Type]bool,
+) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, inline, skip := jsonName(field)
+		if skip {
+			continue
+		}
+		if inline {
+			embedded := field.Type
+			for embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			err := g.setProperties(props, embedded, visiting)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		fieldProps, err := g.schemaOf(field.Type, visiting)
+		if err != nil {
+			return errors.Wrapf(err, "Field %s.%s", t, field.Name)
+		}
+		doc, err := g.lookup(t, field.Name)
+		if err != nil {
+			return err
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr || ft.Kind() == reflect.Slice {
+			ft = ft.Elem()
+		}
+		if doc.Description == "" && !isWellKnown(ft) {
+			// fallback to the documentation of the field's type
+			typeDoc, err := g.lookup(ft)
+			if err != nil {
+				return err
+			}
+			// markers of the type do not apply to the field
+			doc.Description = typeDoc.Description
+		}
+		fieldProps.Description = doc.Description
+		if def, found := doc.Marker(MarkerDefault); found {
+			if !json.Valid([]byte(def)) {
+				return errors.Errorf(
+					"Invalid default %q: Field %s.%s: Want json value",
+					def,
+					t,
+					field.Name,
+				)
+			}
+			fieldProps.Default = &apiextv1.JSON{Raw: []byte(def)}
+		}
+		props.Properties[name] = *fieldProps
+	}
+	return nil
+}
+
+// isWellKnown returns true if the provided type has a fixed schema
+//
+// NOTE:
+//	Documentation of these types is not used since it describes the
+// Go type rather than the field. Moreover, metadata can't have a
+// description in a structural schema.
+func isWellKnown(t reflect.Type) bool {
+	switch t {
+	case typeTime, typeDuration, typeObjectMeta, typeUnstructured, typeIntOrString:
+		return true
+	default:
+		return false
+	}
+}
+
+// jsonName returns the name of the provided field as per its json
+// tag. It also returns whether this field should be inlined or
+// skipped.
+func jsonName(field reflect.StructField) (name string, inline, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+	name = strings.Split(tag, ",")[0]
+	if field.Anonymous && name == "" {
+		// embedded structs without names are inlined similar to
+		// the json encoding
+		return "", true, false
+	}
+	if field.PkgPath != "" {
+		// unexported field
+		return "", false, true
+	}
+	if name == "" {
+		name = field.Name
+	}
+	return name, false, false
+}
+
+// ApplyValueRules sets the provided schema with the enums & required
+// fields from the provided rules
+//
+// NOTE:
+//	This lets the api server enforce the same rules that are used
+// to validate the unstructured instances
+func ApplyValueRules(props *apiextv1.JSONSchemaProps, rules []schema.ValueRule) error {
+	for _, rule := range rules {
+		if len(rule.Enum) == 0 && !rule.Required {
+			continue
+		}
+		err := applyValueRule(props, rule)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyValueRule(root *apiextv1.JSONSchemaProps, rule schema.ValueRule) error {
+	err := applyValueRuleAt(root, strings.Split(rule.Path, "."), rule)
+	if err != nil {
+		return errors.Wrapf(err, "Invalid rule: Path %q", rule.Path)
+	}
+	return nil
+}
+
+// applyValueRuleAt applies the rule at the provided fields relative
+// to the provided schema
+func applyValueRuleAt(
+	props *apiextv1.JSONSchemaProps,
+	fields []string,
+	rule schema.ValueRule,
+) error {
+	field := fields[0]
+	if field == "[*]" {
+		if props.Items == nil || props.Items.Schema == nil {
+			return errors.Errorf("Not a list")
+		}
+		if len(fields) == 1 {
+			return errors.Errorf("Rule can't be set on list items")
+		}
+		return applyValueRuleAt(props.Items.Schema, fields[1:], rule)
+	}
+	child, found := props.Properties[field]
+	if !found {
+		return errors.Errorf("Field %q not found", field)
+	}
+	if len(fields) > 1 {
+		err := applyValueRuleAt(&child, fields[1:], rule)
+		if err != nil {
+			return err
+		}
+	} else {
+		if rule.Required && !contains(props.Required, field) {
+			props.Required = append(props.Required, field)
+		}
+		for _, value := range rule.Enum {
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			child.Enum = append(child.Enum, apiextv1.JSON{Raw: raw})
+		}
+	}
+	// properties are held as values & hence need to be set back
+	props.Properties[field] = child
+	return nil
+}
+
+func contains(list []string, item string) bool {
+	for _, l := range list {
+		if l == item {
+			return true
+		}
+	}
+	return false
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,140 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package event
+
+import (
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/klog/v2"
+	metac "openebs.io/metac/start"
+)
+
+// Component is the source of all the events recorded by
+// d-operators
+const Component = "dope"
+
+// Reasons of the events recorded by d-operators
+const (
+	// ReasonReconcileError is used when a reconciliation
+	// results in an error
+	ReasonReconcileError = "ReconcileError"
+
+	// ReasonTaskFailed is used when a Recipe task fails
+	ReasonTaskFailed = "TaskFailed"
+
+	// ReasonJobCreated is used when a Command's job is created
+	ReasonJobCreated = "JobCreated"
+
+	// ReasonJobCompleted is used when a Command's job completes
+	ReasonJobCompleted = "JobCompleted"
+
+	// ReasonJobFailed is used when a Command's job completes
+	// with errors
+	ReasonJobFailed = "JobFailed"
+)
+
+var (
+	once     sync.Once
+	recorder record.EventRecorder
+)
+
+// noopRecorder discards all the events
+type noopRecorder struct{}
+
+func (noopRecorder) Event(runtime.Object, string, string, string) {}
+
+func (noopRecorder) Eventf(
+	runtime.Object, string, string, string, ...interface{},
+) {
+}
+
+func (noopRecorder) PastEventf(
+	runtime.Object, metav1.Time, string, string, string, ...interface{},
+) {
+}
+
+func (noopRecorder) AnnotatedEventf(
+	runtime.Object, map[string]string, string, string, string, ...interface{},
+) {
+}
+
+// Recorder returns the singleton recorder that records events
+// against the kubernetes cluster managed by metac
+//
+// NOTE:
+//	A recorder that discards all events is returned if kube config
+// is not available e.g. when Recipes are run without being
+// watched as custom resources
+func Recorder() record.EventRecorder {
+	once.Do(func() {
+		recorder = noopRecorder{}
+		if metac.KubeDetails == nil || metac.KubeDetails.Config == nil {
+			return
+		}
+		client, err := kubernetes.NewForConfig(metac.KubeDetails.Config)
+		if err != nil {
+			klog.Errorf("Failed to init event recorder: %+v", err)
+			return
+		}
+		broadcaster := record.NewBroadcaster()
+		broadcaster.StartLogging(klog.V(4).Infof)
+		broadcaster.StartRecordingToSink(
+			&typedcorev1.EventSinkImpl{
+				Interface: client.CoreV1().Events(""),
+			},
+		)
+		recorder = broadcaster.NewRecorder(
+			scheme.Scheme,
+			corev1.EventSource{Component: Component},
+		)
+	})
+	return recorder
+}
+
+// NewReference returns the reference of the provided object that
+// can be used to record events against this object
+func NewReference(
+	typeMeta metav1.TypeMeta,
+	obj metav1.Object,
+) *corev1.ObjectReference {
+	return &corev1.ObjectReference{
+		APIVersion:      typeMeta.APIVersion,
+		Kind:            typeMeta.Kind,
+		Namespace:       obj.GetNamespace(),
+		Name:            obj.GetName(),
+		UID:             obj.GetUID(),
+		ResourceVersion: obj.GetResourceVersion(),
+	}
+}
+
+// TypeForPhase returns the event type i.e. Warning or Normal
+// based on the provided phase
+func TypeForPhase(phase string, warningPhases ...string) string {
+	for _, p := range warningPhases {
+		if p == phase {
+			return corev1.EventTypeWarning
+		}
+	}
+	return corev1.EventTypeNormal
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"fmt"
+	"strings"
+
+	"mayadata.io/d-operators/pkg/schema"
+	types "mayadata.io/d-operators/types/http"
+)
+
+// httpValueRules validate the values of individual HTTP fields
+var httpValueRules = []schema.ValueRule{
+	{
+		Path: "spec.enabled.when",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.Always),
+			string(types.Never),
+			string(types.Once),
+		},
+	},
+	{Path: "spec.secretName", Type: schema.ValueTypeString},
+	{Path: "spec.url", Type: schema.ValueTypeString, Required: true},
+	{Path: "spec.method", Type: schema.ValueTypeString},
+	{Path: "spec.headers", Type: schema.ValueTypeMap},
+	{Path: "spec.queryParams", Type: schema.ValueTypeMap},
+	{Path: "spec.pathParams", Type: schema.ValueTypeMap},
+	{Path: "spec.body", Type: schema.ValueTypeString},
+}
+
+// validateMethod verifies if the http method is supported
+//
+// NOTE:
+//	Method is matched in a case insensitive manner
+func validateMethod(path string, spec map[string]interface{}) []schema.ErrorMessage {
+	method, ok := spec["method"].(string)
+	if !ok && spec["method"] != nil {
+		// type is validated separately
+		return nil
+	}
+	switch strings.ToUpper(method) {
+	case types.GET, types.POST:
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid value %q: Path %q",
+				method,
+				path+".method",
+			),
+			Remedy: fmt.Sprintf(
+				"Supported values: %s, %s",
+				types.GET,
+				types.POST,
+			),
+		},
+	}
+}
+
+// httpObjectRules validate the relations between HTTP fields
+var httpObjectRules = []schema.ObjectRule{
+	{Path: "spec", Validate: validateMethod},
+}
+
+// ValueRules returns the rules that validate the values of
+// individual HTTP fields
+func ValueRules() []schema.ValueRule {
+	return httpValueRules
+}
+
+// ValidateSchemaValues validates the types, enums & required fields
+// of the provided HTTP resource
+func ValidateSchemaValues(http map[string]interface{}) *schema.FieldPathValidationResult {
+	v := &schema.ValueValidation{
+		Target:      http,
+		ValueRules:  httpValueRules,
+		ObjectRules: httpObjectRules,
+	}
+	return v.Validate()
+}
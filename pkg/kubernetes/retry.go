@@ -20,6 +20,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/klog/v2"
 )
 
@@ -34,6 +36,19 @@ func (rt *RetryTimeout) Error() string {
 	return rt.Err
 }
 
+// IsNotRetryable returns true if the provided error can't be
+// resolved by retrying
+//
+// NOTE:
+//	Retrying neither grants the missing permissions nor fixes an
+// invalid request
+func IsNotRetryable(err error) bool {
+	cause := errors.Cause(err)
+	return apierrors.IsForbidden(cause) ||
+		apierrors.IsInvalid(cause) ||
+		apierrors.IsBadRequest(cause)
+}
+
 // Retryable helps executing user provided functions as
 // conditions in a repeated manner till this condition succeeds
 type Retryable struct {
@@ -123,6 +138,14 @@ func (r *Retryable) Waitf(
 			)
 			return err
 		}
+		if err != nil && IsNotRetryable(err) {
+			klog.V(3).Infof(
+				"Retryable condition has non retryable error: Will not retry: %s: %s",
+				context,
+				err,
+			)
+			return err
+		}
 		if time.Since(start) > r.WaitTimeout {
 			var errmsg = "No errors found"
 			if err != nil {
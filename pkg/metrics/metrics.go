@@ -0,0 +1,226 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"k8s.io/klog/v2"
+)
+
+// namespace used as the prefix of all the metrics exposed
+// by d-operators
+const metricsNamespace = "dope"
+
+// label names common to all the metrics
+const (
+	labelNamespace = "namespace"
+	labelName      = "name"
+)
+
+// durationBuckets are the histogram buckets (in seconds) suitable
+// to observe executions that range from milliseconds to hours
+var durationBuckets = prometheus.ExponentialBuckets(0.05, 2, 18)
+
+var (
+	// Registry holds all the metrics exposed by d-operators
+	//
+	// NOTE:
+	//	A dedicated registry is used to avoid any conflicts with
+	// metrics registered by dependencies
+	Registry = prometheus.NewRegistry()
+
+	recipeExecutions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "recipe",
+			Name:      "executions_total",
+			Help:      "Number of Recipe reconciliations by resulting phase",
+		},
+		[]string{labelNamespace, labelName, "phase"},
+	)
+
+	recipeExecutionDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "recipe",
+			Name:      "execution_duration_seconds",
+			Help:      "Time taken by a Recipe to run all its tasks",
+			Buckets:   durationBuckets,
+		},
+		[]string{labelNamespace, labelName, "phase"},
+	)
+
+	recipeTaskDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "recipe",
+			Name:      "task_duration_seconds",
+			Help:      "Time taken by a Recipe task by its action type",
+			Buckets:   durationBuckets,
+		},
+		[]string{labelNamespace, labelName, "action", "phase"},
+	)
+
+	lockContentions = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "lock_contentions_total",
+			Help:      "Number of reconciliations skipped since the resource was locked",
+		},
+		[]string{"kind", labelNamespace, labelName},
+	)
+
+	commandJobDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "command",
+			Name:      "job_duration_seconds",
+			Help:      "Time taken by a Command's job to execute all its commands",
+			Buckets:   durationBuckets,
+		},
+		[]string{labelNamespace, labelName, "phase"},
+	)
+
+	commandExitCodes = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "command",
+			Name:      "exit_codes_total",
+			Help:      "Number of commands executed by a Command's job by exit code",
+		},
+		[]string{labelNamespace, labelName, "exit_code"},
+	)
+
+	httpResponses = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "http",
+			Name:      "responses_total",
+			Help:      "Number of HTTP invocations by response code",
+		},
+		[]string{labelNamespace, labelName, "code"},
+	)
+
+	httpRequestDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Latency of HTTP invocations",
+			Buckets:   prometheus.DefBuckets,
+		},
+		[]string{labelNamespace, labelName},
+	)
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		recipeExecutions,
+		recipeExecutionDuration,
+		recipeTaskDuration,
+		lockContentions,
+		commandJobDuration,
+		commandExitCodes,
+		httpResponses,
+		httpRequestDuration,
+	)
+}
+
+// ObserveRecipeExecution records a Recipe reconciliation. Duration
+// is recorded only when the Recipe's tasks were run i.e. when the
+// provided duration is non nil.
+func ObserveRecipeExecution(
+	namespace, name, phase string,
+	duration *time.Duration,
+) {
+	recipeExecutions.WithLabelValues(namespace, name, phase).Inc()
+	if duration != nil {
+		recipeExecutionDuration.
+			WithLabelValues(namespace, name, phase).
+			Observe(duration.Seconds())
+	}
+}
+
+// ObserveRecipeTask records the execution of a single Recipe task
+func ObserveRecipeTask(
+	namespace, name, action, phase string,
+	duration time.Duration,
+) {
+	recipeTaskDuration.
+		WithLabelValues(namespace, name, action, phase).
+		Observe(duration.Seconds())
+}
+
+// IncLockContention records a reconciliation that was skipped since
+// the resource was locked by another execution
+func IncLockContention(kind, namespace, name string) {
+	lockContentions.WithLabelValues(kind, namespace, name).Inc()
+}
+
+// ObserveCommandJob records a completed Command job along with the
+// exit codes of all the commands it executed
+func ObserveCommandJob(
+	namespace, name, phase string,
+	durationInSeconds float64,
+	exitCodes []int,
+) {
+	commandJobDuration.
+		WithLabelValues(namespace, name, phase).
+		Observe(durationInSeconds)
+	for _, code := range exitCodes {
+		commandExitCodes.
+			WithLabelValues(namespace, name, strconv.Itoa(code)).
+			Inc()
+	}
+}
+
+// ObserveHTTPResponse records a HTTP invocation. A code of 0
+// implies the invocation did not get any response.
+func ObserveHTTPResponse(
+	namespace, name string,
+	code int,
+	duration time.Duration,
+) {
+	httpResponses.
+		WithLabelValues(namespace, name, strconv.Itoa(code)).
+		Inc()
+	httpRequestDuration.
+		WithLabelValues(namespace, name).
+		Observe(duration.Seconds())
+}
+
+// Handler returns the http handler that serves the registered
+// metrics in prometheus format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// Serve exposes the registered metrics at /metrics path of the
+// provided address. This is a blocking call.
+func Serve(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	klog.Infof("Serving metrics at %q", addr)
+	return http.ListenAndServe(addr, mux)
+}
@@ -0,0 +1,135 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveRecipeExecution(t *testing.T) {
+	var duration = 2 * time.Second
+	var tests = map[string]struct {
+		namespace string
+		name      string
+		phase     string
+		duration  *time.Duration
+		times     int
+	}{
+		"completed recipe": {
+			namespace: "ns-1",
+			name:      "recipe-1",
+			phase:     "Completed",
+			duration:  &duration,
+			times:     2,
+		},
+		"locked recipe": {
+			namespace: "ns-1",
+			name:      "recipe-1",
+			phase:     "Locked",
+			times:     1,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < mock.times; i++ {
+				ObserveRecipeExecution(
+					mock.namespace,
+					mock.name,
+					mock.phase,
+					mock.duration,
+				)
+			}
+			got := testutil.ToFloat64(
+				recipeExecutions.WithLabelValues(
+					mock.namespace,
+					mock.name,
+					mock.phase,
+				),
+			)
+			if got != float64(mock.times) {
+				t.Fatalf("Expected executions %d got %f", mock.times, got)
+			}
+		})
+	}
+}
+
+func TestObserveCommandJob(t *testing.T) {
+	ObserveCommandJob("ns-1", "cmd-1", "Completed", 10, []int{0, 1, 0})
+	var tests = map[string]struct {
+		exitCode string
+		expect   float64
+	}{
+		"success exit code": {
+			exitCode: "0",
+			expect:   2,
+		},
+		"failure exit code": {
+			exitCode: "1",
+			expect:   1,
+		},
+		"unobserved exit code": {
+			exitCode: "2",
+			expect:   0,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := testutil.ToFloat64(
+				commandExitCodes.WithLabelValues("ns-1", "cmd-1", mock.exitCode),
+			)
+			if got != mock.expect {
+				t.Fatalf("Expected exit codes %f got %f", mock.expect, got)
+			}
+		})
+	}
+}
+
+func TestHandler(t *testing.T) {
+	IncLockContention("Recipe", "ns-1", "recipe-1")
+	ObserveHTTPResponse("ns-1", "http-1", 200, time.Second)
+	ObserveRecipeTask("ns-1", "recipe-1", "apply", "Passed", time.Second)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := ioutil.ReadAll(rec.Body)
+	if err != nil {
+		t.Fatalf("Expected no error got %s", err.Error())
+	}
+	var expected = []string{
+		`dope_lock_contentions_total{kind="Recipe",name="recipe-1",namespace="ns-1"} 1`,
+		`dope_http_responses_total{code="200",name="http-1",namespace="ns-1"} 1`,
+		`dope_recipe_task_duration_seconds_count{action="apply",name="recipe-1",namespace="ns-1",phase="Passed"} 1`,
+		`go_goroutines`,
+	}
+	for _, e := range expected {
+		if !strings.Contains(string(body), e) {
+			t.Fatalf("Expected metric %q in:\n%s", e, body)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// invalidAnnotationNameChars matches the characters of a task name
+// that are not allowed in the name of an annotation key e.g. tasks
+// expanded from forEach or included from a RecipeTemplate
+var invalidAnnotationNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]`)
+
+// ApprovalAnnotationKey returns the annotation key that approves or
+// rejects the provided task
+func ApprovalAnnotationKey(task string) string {
+	return types.AnnotationKeyPrefixApprove +
+		invalidAnnotationNameChars.ReplaceAllString(task, "-")
+}
+
+// ApproverAnnotationKey returns the annotation key that has the
+// approver of the provided task
+func ApproverAnnotationKey(task string) string {
+	return types.AnnotationKeyPrefixApprover +
+		invalidAnnotationNameChars.ReplaceAllString(task, "-")
+}
+
+// approvalDecision is the decision made on an approval task
+type approvalDecision struct {
+	// Value is either "true", "false" or empty if no decision
+	// was made
+	Value string
+
+	// Approver who made this decision
+	Approver string
+}
+
+// hasApprovalTask returns true if any of the provided tasks is an
+// approval task
+func hasApprovalTask(tasks []types.Task) bool {
+	for _, task := range tasks {
+		if task.Approval != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getApprovalDecision returns the decision made on the provided
+// approval task. Annotations of this Recipe take precedence over the
+// ConfigMap.
+func (r *Runner) getApprovalDecision(task types.Task) (approvalDecision, error) {
+	var annotations = r.Recipe.GetAnnotations()
+	var key = ApprovalAnnotationKey(task.Name)
+	if value := annotations[key]; value == "true" || value == "false" {
+		approver := annotations[ApproverAnnotationKey(task.Name)]
+		if approver == "" {
+			approver = fmt.Sprintf("annotation %s", key)
+		}
+		return approvalDecision{Value: value, Approver: approver}, nil
+	}
+	if task.Approval.ConfigMap == nil {
+		return approvalDecision{}, nil
+	}
+	var ref = task.Approval.ConfigMap
+	client, err := r.fixture.GetClientForAPIVersionAndKind("v1", "ConfigMap")
+	if err != nil {
+		return approvalDecision{}, err
+	}
+	cm, err := client.
+		Namespace(r.Recipe.GetNamespace()).
+		Get(ref.Name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			// ConfigMap may be created later
+			return approvalDecision{}, nil
+		}
+		return approvalDecision{}, err
+	}
+	data, _, err := unstructured.NestedStringMap(cm.Object, "data")
+	if err != nil {
+		return approvalDecision{}, err
+	}
+	if value := data[ref.Key]; value != "true" && value != "false" {
+		return approvalDecision{}, nil
+	}
+	approver := data[ref.ApproverKey]
+	if ref.ApproverKey == "" || approver == "" {
+		approver = fmt.Sprintf(
+			"ConfigMap %s/%s key %s",
+			r.Recipe.GetNamespace(),
+			ref.Name,
+			ref.Key,
+		)
+	}
+	return approvalDecision{Value: data[ref.Key], Approver: approver}, nil
+}
+
+// runApproval verifies if the provided approval task was approved
+// & returns its result
+//
+// NOTE:
+//	Result is set to WaitingForApproval if the task was neither
+// approved nor rejected. Time since this task waits for approval is
+// carried forward from the observed status.
+func (r *Runner) runApproval(idx int, task types.Task) (types.TaskResult, error) {
+	var now = metav1.Now()
+	var status = types.ApprovalStatus{
+		Phase:       types.ApprovalPhaseWaiting,
+		RequestTime: &now,
+	}
+	observed := r.Recipe.Status.Approvals[task.Name]
+	if observed.Phase == types.ApprovalPhaseWaiting && observed.RequestTime != nil {
+		status.RequestTime = observed.RequestTime
+	}
+	decision, err := r.getApprovalDecision(task)
+	if err != nil {
+		return types.TaskResult{}, errors.Wrapf(
+			err,
+			"Approval failed: Index %d: Name %q: Recipe %q / %q",
+			idx+1,
+			task.Name,
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+		)
+	}
+	var result = types.TaskResult{
+		Step: idx + 1,
+	}
+	var timeout = task.Approval.TimeoutInSeconds
+	switch {
+	case decision.Value == "true":
+		status.Phase = types.ApprovalPhaseApproved
+		status.Approver = decision.Approver
+		status.DecisionTime = &now
+		result.Phase = types.TaskStatusPassed
+		result.Message = fmt.Sprintf("Approved by %s", decision.Approver)
+	case decision.Value == "false":
+		status.Phase = types.ApprovalPhaseRejected
+		status.Approver = decision.Approver
+		status.DecisionTime = &now
+		result.Phase = types.TaskStatusFailed
+		result.Message = fmt.Sprintf("Rejected by %s", decision.Approver)
+	case timeout != nil &&
+		now.Sub(status.RequestTime.Time) >= time.Duration(*timeout)*time.Second:
+		status.Phase = types.ApprovalPhaseTimedOut
+		status.DecisionTime = &now
+		result.Phase = types.TaskStatusFailed
+		result.Message = fmt.Sprintf(
+			"Approval timed out after %ds",
+			*timeout,
+		)
+	default:
+		result.Phase = types.TaskStatusWaitingForApproval
+		result.Message = fmt.Sprintf(
+			"Waiting for approval: Set annotation %q to true or false",
+			ApprovalAnnotationKey(task.Name),
+		)
+	}
+	if r.RecipeStatus.Approvals == nil {
+		r.RecipeStatus.Approvals = map[string]types.ApprovalStatus{}
+	}
+	r.RecipeStatus.Approvals[task.Name] = status
+	return result, nil
+}
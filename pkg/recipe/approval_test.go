@@ -0,0 +1,525 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"openebs.io/metac/dynamic/clientset"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestApprovalAnnotationKey(t *testing.T) {
+	var tests = map[string]struct {
+		task     string
+		expected string
+	}{
+		"task name": {
+			task:     "expand-pool",
+			expected: "approve.dope.mayadata.io/expand-pool",
+		},
+		"task expanded from forEach": {
+			task:     "expand-pool[0]",
+			expected: "approve.dope.mayadata.io/expand-pool-0-",
+		},
+		"task included from template": {
+			task:     "upgrade/approve",
+			expected: "approve.dope.mayadata.io/upgrade-approve",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := ApprovalAnnotationKey(mock.task)
+			if got != mock.expected {
+				t.Fatalf("Expected key %q got %q", mock.expected, got)
+			}
+		})
+	}
+}
+
+func TestRunnerRunApproval(t *testing.T) {
+	// fixture is loaded with a ConfigMap that has the approvals
+	fixture := &BaseFixture{
+		getClientForAPIVersionAndKindFn: func(
+			apiversion string,
+			kind string,
+		) (*clientset.ResourceClient, error) {
+			di := dynamicfake.NewSimpleDynamicClient(
+				runtime.NewScheme(),
+				&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name":      "approvals",
+							"namespace": "ns",
+						},
+						"data": map[string]interface{}{
+							"expand":    "true",
+							"expand-by": "alice",
+							"shrink":    "false",
+						},
+					},
+				},
+			)
+			nri := di.Resource(schema.GroupVersionResource{
+				Version:  "v1",
+				Resource: "configmaps",
+			})
+			// noop api resource ignores the namespace
+			ri := nri.Namespace("ns")
+			return &clientset.ResourceClient{
+				ResourceInterface: ri,
+				APIResource:       &dynamicdiscovery.APIResource{},
+			}, nil
+		},
+	}
+	var oneMinute int64 = 60
+	var anHourAgo = metav1.NewTime(time.Now().Add(-1 * time.Hour))
+	var tests = map[string]struct {
+		approval         types.Approval
+		annotations      map[string]string
+		observed         map[string]types.ApprovalStatus
+		expectedPhase    types.TaskStatusPhase
+		expectedApproval types.ApprovalPhase
+		expectedApprover string
+		expectedRequest  *metav1.Time
+	}{
+		"waiting for approval": {
+			expectedPhase:    types.TaskStatusWaitingForApproval,
+			expectedApproval: types.ApprovalPhaseWaiting,
+		},
+		"waiting for approval since previous run": {
+			approval: types.Approval{
+				TimeoutInSeconds: &oneMinute,
+			},
+			observed: map[string]types.ApprovalStatus{
+				"task": {
+					Phase:       types.ApprovalPhaseWaiting,
+					RequestTime: &anHourAgo,
+				},
+			},
+			expectedPhase:    types.TaskStatusFailed,
+			expectedApproval: types.ApprovalPhaseTimedOut,
+			expectedRequest:  &anHourAgo,
+		},
+		"approved via annotation": {
+			annotations: map[string]string{
+				"approve.dope.mayadata.io/task":  "true",
+				"approver.dope.mayadata.io/task": "bob",
+			},
+			expectedPhase:    types.TaskStatusPassed,
+			expectedApproval: types.ApprovalPhaseApproved,
+			expectedApprover: "bob",
+		},
+		"rejected via annotation": {
+			annotations: map[string]string{
+				"approve.dope.mayadata.io/task": "false",
+			},
+			expectedPhase:    types.TaskStatusFailed,
+			expectedApproval: types.ApprovalPhaseRejected,
+			expectedApprover: "annotation approve.dope.mayadata.io/task",
+		},
+		"approved via configmap": {
+			approval: types.Approval{
+				ConfigMap: &types.ApprovalConfigMap{
+					Name:        "approvals",
+					Key:         "expand",
+					ApproverKey: "expand-by",
+				},
+			},
+			expectedPhase:    types.TaskStatusPassed,
+			expectedApproval: types.ApprovalPhaseApproved,
+			expectedApprover: "alice",
+		},
+		"rejected via configmap": {
+			approval: types.Approval{
+				ConfigMap: &types.ApprovalConfigMap{
+					Name: "approvals",
+					Key:  "shrink",
+				},
+			},
+			expectedPhase:    types.TaskStatusFailed,
+			expectedApproval: types.ApprovalPhaseRejected,
+			expectedApprover: "ConfigMap ns/approvals key shrink",
+		},
+		"annotation takes precedence over configmap": {
+			approval: types.Approval{
+				ConfigMap: &types.ApprovalConfigMap{
+					Name: "approvals",
+					Key:  "expand",
+				},
+			},
+			annotations: map[string]string{
+				"approve.dope.mayadata.io/task": "false",
+			},
+			expectedPhase:    types.TaskStatusFailed,
+			expectedApproval: types.ApprovalPhaseRejected,
+			expectedApprover: "annotation approve.dope.mayadata.io/task",
+		},
+		"configmap key is not set": {
+			approval: types.Approval{
+				ConfigMap: &types.ApprovalConfigMap{
+					Name: "approvals",
+					Key:  "junk",
+				},
+			},
+			expectedPhase:    types.TaskStatusWaitingForApproval,
+			expectedApproval: types.ApprovalPhaseWaiting,
+		},
+		"configmap is not found": {
+			approval: types.Approval{
+				ConfigMap: &types.ApprovalConfigMap{
+					Name: "junk",
+					Key:  "expand",
+				},
+			},
+			expectedPhase:    types.TaskStatusWaitingForApproval,
+			expectedApproval: types.ApprovalPhaseWaiting,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			approval := mock.approval
+			r := &Runner{
+				Recipe: types.Recipe{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:        "recipe",
+						Namespace:   "ns",
+						Annotations: mock.annotations,
+					},
+					Status: types.RecipeStatus{
+						Approvals: mock.observed,
+					},
+				},
+				RecipeStatus: &types.RecipeStatus{},
+				fixture: &Fixture{
+					BaseFixture: fixture,
+				},
+			}
+			got, err := r.runApproval(0, types.Task{
+				Name:     "task",
+				Approval: &approval,
+			})
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if got.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected task phase %q got %q: %s",
+					mock.expectedPhase,
+					got.Phase,
+					got.Message,
+				)
+			}
+			status := r.RecipeStatus.Approvals["task"]
+			if status.Phase != mock.expectedApproval {
+				t.Fatalf(
+					"Expected approval phase %q got %q",
+					mock.expectedApproval,
+					status.Phase,
+				)
+			}
+			if status.Approver != mock.expectedApprover {
+				t.Fatalf(
+					"Expected approver %q got %q",
+					mock.expectedApprover,
+					status.Approver,
+				)
+			}
+			if mock.expectedRequest != nil &&
+				!status.RequestTime.Equal(mock.expectedRequest) {
+				t.Fatalf(
+					"Expected request time %s got %s",
+					mock.expectedRequest,
+					status.RequestTime,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerRunAllTasksWithApproval(t *testing.T) {
+	// newCreateTask returns a task that creates a config map
+	newCreateTask := func(name string) types.Task {
+		return types.Task{
+			Name: name,
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": name,
+						},
+					},
+				},
+			},
+		}
+	}
+	var tasks = []types.Task{
+		newCreateTask("plan"),
+		{
+			Name:     "approve",
+			Approval: &types.Approval{},
+		},
+		newCreateTask("execute"),
+	}
+	// paused is the observed status of a Recipe that was paused
+	// at its approval task
+	var paused = types.RecipeStatus{
+		Phase: types.RecipeStatusWaitingForApproval,
+		TaskResults: map[string]types.TaskResult{
+			"plan": {
+				Phase:   types.TaskStatusPassed,
+				Message: "previous run",
+			},
+		},
+		Checkpoint: &types.CheckpointStatus{
+			Markers: map[string]string{
+				"plan": TaskMarker(tasks[0]),
+			},
+		},
+	}
+	var tests = map[string]struct {
+		annotations       map[string]string
+		observed          types.RecipeStatus
+		expectedPhase     types.RecipeStatusPhase
+		expectedTasks     map[string]types.TaskStatusPhase
+		expectedPlan      string
+		expectedOnFailure bool
+		expectedFinally   bool
+	}{
+		"paused for approval": {
+			expectedPhase: types.RecipeStatusWaitingForApproval,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"plan":    types.TaskStatusPassed,
+				"approve": types.TaskStatusWaitingForApproval,
+			},
+		},
+		"resumed after approval": {
+			annotations: map[string]string{
+				"approve.dope.mayadata.io/approve": "true",
+			},
+			observed:      paused,
+			expectedPhase: types.RecipeStatusCompleted,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"plan":    types.TaskStatusPassed,
+				"approve": types.TaskStatusPassed,
+				"execute": types.TaskStatusPassed,
+			},
+			expectedPlan:    "previous run",
+			expectedFinally: true,
+		},
+		"resumed after rejection": {
+			annotations: map[string]string{
+				"approve.dope.mayadata.io/approve": "false",
+			},
+			observed:      paused,
+			expectedPhase: types.RecipeStatusFailed,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"plan":    types.TaskStatusPassed,
+				"approve": types.TaskStatusFailed,
+				"execute": types.TaskStatusPassed,
+			},
+			expectedPlan:      "previous run",
+			expectedOnFailure: true,
+			expectedFinally:   true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			r := &Runner{
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Recipe: types.Recipe{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: mock.annotations,
+					},
+					Spec: types.RecipeSpec{
+						Tasks: tasks,
+						OnFailure: []types.Task{
+							newCreateTask("collect"),
+						},
+						Finally: []types.Task{
+							newCreateTask("cleanup"),
+						},
+					},
+					Status: mock.observed,
+				},
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: make(map[string]types.TaskResult),
+				},
+				fixture: &Fixture{
+					BaseFixture: NoopFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			}
+			r.initEnabled()        // init to avoid nil pointers
+			err := r.runAllTasks() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if r.RecipeStatus.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected status.phase %q got %q",
+					mock.expectedPhase,
+					r.RecipeStatus.Phase,
+				)
+			}
+			if len(r.RecipeStatus.TaskResults) != len(mock.expectedTasks) {
+				t.Fatalf(
+					"Expected %d task results got %d: %v",
+					len(mock.expectedTasks),
+					len(r.RecipeStatus.TaskResults),
+					r.RecipeStatus.TaskResults,
+				)
+			}
+			for task, phase := range mock.expectedTasks {
+				got := r.RecipeStatus.TaskResults[task]
+				if got.Phase != phase {
+					t.Fatalf(
+						"Expected task %q with phase %q got %q: %s",
+						task,
+						phase,
+						got.Phase,
+						got.Message,
+					)
+				}
+			}
+			if mock.expectedPlan != "" &&
+				r.RecipeStatus.TaskResults["plan"].Message != mock.expectedPlan {
+				t.Fatalf(
+					"Expected plan task to be skipped got %q",
+					r.RecipeStatus.TaskResults["plan"].Message,
+				)
+			}
+			if (len(r.RecipeStatus.OnFailureTaskResults) != 0) != mock.expectedOnFailure {
+				t.Fatalf(
+					"Expected onFailure tasks to run %t got %v",
+					mock.expectedOnFailure,
+					r.RecipeStatus.OnFailureTaskResults,
+				)
+			}
+			if (len(r.RecipeStatus.FinallyTaskResults) != 0) != mock.expectedFinally {
+				t.Fatalf(
+					"Expected finally tasks to run %t got %v",
+					mock.expectedFinally,
+					r.RecipeStatus.FinallyTaskResults,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerRunWithoutLockingWithApproval(t *testing.T) {
+	var tasks = []types.Task{
+		{
+			Name: "plan",
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": "plan",
+						},
+					},
+				},
+			},
+		},
+		{
+			Name:     "approve",
+			Approval: &types.Approval{},
+		},
+	}
+	var tests = map[string]struct {
+		annotations   map[string]string
+		expectedPhase types.RecipeStatusPhase
+	}{
+		"paused for approval": {
+			expectedPhase: types.RecipeStatusWaitingForApproval,
+		},
+		"approved": {
+			annotations: map[string]string{
+				"approve.dope.mayadata.io/approve": "true",
+			},
+			expectedPhase: types.RecipeStatusCompleted,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			recipe := types.Recipe{
+				Spec: types.RecipeSpec{
+					Tasks: tasks,
+				},
+			}
+			recipe.SetName("approval")
+			recipe.SetAnnotations(mock.annotations)
+			r := NewRunner(RunnerConfig{
+				Recipe: recipe,
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Fixture: &Fixture{
+					BaseFixture: NoopFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			})
+			got, err := r.RunWithoutLocking() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if got.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected phase %q got %q: %s",
+					mock.expectedPhase,
+					got.Phase,
+					got.Reason,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	"k8s.io/klog/v2"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// resumablePhases are the phases of the previous run from which the
+// current run can resume
+var resumablePhases = map[types.RecipeStatusPhase]bool{
+	types.RecipeStatusFailed:    true,
+	types.RecipeStatusTimedOut:  true,
+	types.RecipeStatusCancelled: true,
+	types.RecipeStatusRunning:   true,
+	types.RecipeStatusError:     true,
+}
+
+// TaskMarker returns the idempotency marker of the provided task.
+// Marker changes whenever this task is modified.
+func TaskMarker(task types.Task) string {
+	raw, err := json.Marshal(task)
+	if err != nil {
+		// a task without a marker is never skipped
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(raw))
+}
+
+// isCheckpointEnabled returns true if the task results of this
+// Recipe should be persisted after every task
+func (r *Runner) isCheckpointEnabled() bool {
+	return r.Recipe.Spec.Checkpoint != nil &&
+		r.Recipe.Spec.Checkpoint.Enabled
+}
+
+// isResumable returns true if the current run should resume from
+// the previous run
+func (r *Runner) isResumable() bool {
+	if r.Recipe.Status.Checkpoint == nil {
+		return false
+	}
+	if r.Recipe.Status.Phase == types.RecipeStatusWaitingForApproval {
+		// Recipe was paused at its approval task
+		return true
+	}
+	return r.isCheckpointEnabled() && resumablePhases[r.Recipe.Status.Phase]
+}
+
+// initCheckpoint returns the index of the task from which the provided
+// tasks should be run. Results of the tasks prior to this index are
+// carried forward from the previous run.
+//
+// NOTE:
+//	Tasks are resumed from the first task that did not pass or was
+// modified since it passed in the previous run
+//
+// NOTE:
+//	Idempotency markers are recorded even if checkpoint is disabled
+// if any of the tasks is an approval task. This lets the Recipe to
+// resume from the approval task once it is approved.
+func (r *Runner) initCheckpoint(tasks []types.Task) int {
+	if !r.isCheckpointEnabled() && !hasApprovalTask(tasks) {
+		return 0
+	}
+	r.RecipeStatus.Checkpoint = &types.CheckpointStatus{
+		Markers: map[string]string{},
+	}
+	var observed = r.Recipe.Status.Checkpoint
+	if !r.isResumable() {
+		// run all the tasks
+		return 0
+	}
+	var resumeFrom int
+	for resumeFrom < len(tasks) {
+		task := tasks[resumeFrom]
+		marker := observed.Markers[task.Name]
+		if r.Recipe.Status.TaskResults[task.Name].Phase != types.TaskStatusPassed ||
+			marker == "" ||
+			marker != TaskMarker(task) {
+			break
+		}
+		// carry forward the result of the passed task
+		r.RecipeStatus.TaskResults[task.Name] =
+			r.Recipe.Status.TaskResults[task.Name]
+		r.RecipeStatus.Checkpoint.Markers[task.Name] = marker
+		if approval, found := r.Recipe.Status.Approvals[task.Name]; found {
+			if r.RecipeStatus.Approvals == nil {
+				r.RecipeStatus.Approvals = map[string]types.ApprovalStatus{}
+			}
+			r.RecipeStatus.Approvals[task.Name] = approval
+		}
+		resumeFrom++
+	}
+	if resumeFrom > 0 && resumeFrom < len(tasks) {
+		r.RecipeStatus.Checkpoint.ResumedFrom = tasks[resumeFrom].Name
+		klog.V(2).Infof(
+			"Will resume from task %q: Recipe %q / %q",
+			tasks[resumeFrom].Name,
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+		)
+	}
+	return resumeFrom
+}
+
+// checkpoint records the idempotency marker of the provided task &
+// persists its result if checkpoint is enabled
+//
+// NOTE:
+//	Phase of this Recipe is persisted as Running. Errors are logged
+// since the results are persisted again once all the tasks are run.
+func (r *Runner) checkpoint(task types.Task, got types.TaskResult) {
+	if r.RecipeStatus.Checkpoint == nil {
+		return
+	}
+	if got.Phase == types.TaskStatusPassed {
+		r.RecipeStatus.Checkpoint.Markers[task.Name] = TaskMarker(task)
+	}
+	if !r.isCheckpointEnabled() {
+		return
+	}
+	r.RecipeStatus.Phase = types.RecipeStatusRunning
+	err := r.persistRecipeWithRetries()
+	if err != nil {
+		klog.Errorf(
+			"Checkpoint failed: Task %q: Recipe %q / %q: %s",
+			task.Name,
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+			err.Error(),
+		)
+	}
+}
@@ -0,0 +1,250 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestRunnerRunAllTasksWithCheckpoint(t *testing.T) {
+	// newCreateTask returns a task that creates a config map
+	newCreateTask := func(name string) types.Task {
+		return types.Task{
+			Name: name,
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": name,
+						},
+					},
+				},
+			},
+		}
+	}
+	var tasks = []types.Task{
+		newCreateTask("one"),
+		newCreateTask("two"),
+		newCreateTask("three"),
+	}
+	// previous is the result of a task from the previous run
+	var previous = func(phase types.TaskStatusPhase) types.TaskResult {
+		return types.TaskResult{
+			Phase:   phase,
+			Message: "previous run",
+		}
+	}
+	var tests = map[string]struct {
+		isDisabled          bool
+		observed            types.RecipeStatus
+		expectedResumedFrom string
+		expectedSkipped     []string
+		expectedPersisted   int
+	}{
+		"checkpoint is disabled": {
+			isDisabled: true,
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+			},
+			expectedPersisted: 1,
+		},
+		"first run": {
+			expectedPersisted: 4,
+		},
+		"resume from the failed task": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+				TaskResults: map[string]types.TaskResult{
+					"one": previous(types.TaskStatusPassed),
+					"two": previous(types.TaskStatusFailed),
+				},
+				Checkpoint: &types.CheckpointStatus{
+					Markers: map[string]string{
+						"one": TaskMarker(tasks[0]),
+					},
+				},
+			},
+			expectedResumedFrom: "two",
+			expectedSkipped:     []string{"one"},
+			expectedPersisted:   3,
+		},
+		"resume after error": {
+			observed: types.RecipeStatus{
+				Phase: "Error",
+				TaskResults: map[string]types.TaskResult{
+					"one": previous(types.TaskStatusPassed),
+					"two": previous(types.TaskStatusPassed),
+				},
+				Checkpoint: &types.CheckpointStatus{
+					Markers: map[string]string{
+						"one": TaskMarker(tasks[0]),
+						"two": TaskMarker(tasks[1]),
+					},
+				},
+			},
+			expectedResumedFrom: "three",
+			expectedSkipped:     []string{"one", "two"},
+			expectedPersisted:   2,
+		},
+		"modified task is not skipped": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+				TaskResults: map[string]types.TaskResult{
+					"one": previous(types.TaskStatusPassed),
+					"two": previous(types.TaskStatusFailed),
+				},
+				Checkpoint: &types.CheckpointStatus{
+					Markers: map[string]string{
+						"one": "modified",
+					},
+				},
+			},
+			expectedPersisted: 4,
+		},
+		"previous run completed": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+				TaskResults: map[string]types.TaskResult{
+					"one": previous(types.TaskStatusPassed),
+				},
+				Checkpoint: &types.CheckpointStatus{
+					Markers: map[string]string{
+						"one": TaskMarker(tasks[0]),
+					},
+				},
+			},
+			expectedPersisted: 4,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			var persisted int
+			r := &Runner{
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						Checkpoint: &types.Checkpoint{
+							Enabled: !mock.isDisabled,
+						},
+						Tasks: tasks,
+					},
+					Status: mock.observed,
+				},
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: make(map[string]types.TaskResult),
+				},
+				fixture: &Fixture{
+					BaseFixture: NoopFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					persisted++
+					return nil
+				},
+			}
+			r.initEnabled()        // init to avoid nil pointers
+			err := r.runAllTasks() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if r.RecipeStatus.Phase != types.RecipeStatusCompleted {
+				t.Fatalf(
+					"Expected status.phase %q got %q",
+					types.RecipeStatusCompleted,
+					r.RecipeStatus.Phase,
+				)
+			}
+			if persisted != mock.expectedPersisted {
+				t.Fatalf(
+					"Expected %d updates got %d",
+					mock.expectedPersisted,
+					persisted,
+				)
+			}
+			var skipped []string
+			for _, task := range tasks {
+				got := r.RecipeStatus.TaskResults[task.Name]
+				if got.Phase != types.TaskStatusPassed {
+					t.Fatalf(
+						"Expected task %q with phase %q got %q",
+						task.Name,
+						types.TaskStatusPassed,
+						got.Phase,
+					)
+				}
+				if got.Message == "previous run" {
+					skipped = append(skipped, task.Name)
+				}
+			}
+			if len(skipped) != len(mock.expectedSkipped) {
+				t.Fatalf(
+					"Expected skipped tasks %v got %v",
+					mock.expectedSkipped,
+					skipped,
+				)
+			}
+			for idx := range skipped {
+				if skipped[idx] != mock.expectedSkipped[idx] {
+					t.Fatalf(
+						"Expected skipped tasks %v got %v",
+						mock.expectedSkipped,
+						skipped,
+					)
+				}
+			}
+			if mock.isDisabled {
+				if r.RecipeStatus.Checkpoint != nil {
+					t.Fatalf("Expected no checkpoint got %v", r.RecipeStatus.Checkpoint)
+				}
+				return
+			}
+			if r.RecipeStatus.Checkpoint.ResumedFrom != mock.expectedResumedFrom {
+				t.Fatalf(
+					"Expected resumed from %q got %q",
+					mock.expectedResumedFrom,
+					r.RecipeStatus.Checkpoint.ResumedFrom,
+				)
+			}
+			for _, task := range tasks {
+				marker := r.RecipeStatus.Checkpoint.Markers[task.Name]
+				if marker != TaskMarker(task) {
+					t.Fatalf(
+						"Expected marker %q for task %q got %q",
+						TaskMarker(task),
+						task.Name,
+						marker,
+					)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"mayadata.io/d-operators/common/unstruct"
+	"mayadata.io/d-operators/types/gvk"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// DependencyResolver resolves the dependencies of a Recipe against
+// the Recipes found in its namespace
+type DependencyResolver struct {
+	// Recipes found in the namespace of the dependent Recipe
+	Recipes []types.Recipe
+
+	// Invalid has the errors of the Recipes that could not be
+	// converted to their typed form. These Recipes are set in
+	// Recipes with their identity only.
+	Invalid map[string]error
+}
+
+// selectDependencies returns the Recipes that match the provided
+// dependency. Dependent Recipe is never selected.
+func (d DependencyResolver) selectDependencies(
+	dependent string,
+	dependency types.Dependency,
+) ([]types.Recipe, error) {
+	var selector labels.Selector
+	if dependency.LabelSelector != nil {
+		var err error
+		selector, err = metav1.LabelSelectorAsSelector(dependency.LabelSelector)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var selected []types.Recipe
+	for _, recipe := range d.Recipes {
+		if recipe.GetName() == dependent {
+			continue
+		}
+		if dependency.Name != "" && dependency.Name != recipe.GetName() {
+			continue
+		}
+		if selector != nil && !selector.Matches(labels.Set(recipe.GetLabels())) {
+			continue
+		}
+		selected = append(selected, recipe)
+	}
+	return selected, nil
+}
+
+// resolve returns the status of every dependency of the provided
+// Recipe along with their own dependencies. Visiting has the Recipes
+// that form the chain till the provided Recipe & is used to detect
+// cycles.
+func (d DependencyResolver) resolve(
+	recipe types.Recipe,
+	visiting []string,
+) ([]types.DependencyStatus, bool, error) {
+	var statuses []types.DependencyStatus
+	var met = true
+	visiting = append(visiting, recipe.GetName())
+	for _, dependency := range recipe.Spec.DependsOn {
+		want := dependency.Phase
+		if want == "" {
+			want = types.RecipeStatusCompleted
+		}
+		var selector string
+		if dependency.LabelSelector != nil {
+			selector = metav1.FormatLabelSelector(dependency.LabelSelector)
+		}
+		selected, err := d.selectDependencies(recipe.GetName(), dependency)
+		if err != nil {
+			return nil, false, errors.Wrapf(
+				err,
+				"Invalid dependency: Recipe %q",
+				recipe.GetName(),
+			)
+		}
+		if len(selected) == 0 {
+			met = false
+			statuses = append(statuses, types.DependencyStatus{
+				Name:     dependency.Name,
+				Selector: selector,
+				Want:     want,
+				Message:  "Recipe not found",
+			})
+			continue
+		}
+		for _, s := range selected {
+			if invalid := d.Invalid[s.GetName()]; invalid != nil {
+				// only this dependency is affected by an invalid
+				// Recipe
+				met = false
+				statuses = append(statuses, types.DependencyStatus{
+					Name:     s.GetName(),
+					Selector: selector,
+					Want:     want,
+					Message:  fmt.Sprintf("Invalid recipe: %s", invalid.Error()),
+				})
+				continue
+			}
+			for _, v := range visiting {
+				if v == s.GetName() {
+					return nil, false, &DependencyCycleError{
+						Chain: append(
+							append([]string{}, visiting...),
+							s.GetName(),
+						),
+					}
+				}
+			}
+			nested, _, err := d.resolve(s, visiting)
+			if err != nil {
+				return nil, false, err
+			}
+			status := types.DependencyStatus{
+				Name:      s.GetName(),
+				Selector:  selector,
+				Phase:     s.Status.Phase,
+				Want:      want,
+				Met:       s.Status.Phase == want,
+				DependsOn: nested,
+			}
+			if !status.Met {
+				met = false
+				status.Message = fmt.Sprintf(
+					"Waiting for phase %q",
+					want,
+				)
+			}
+			statuses = append(statuses, status)
+		}
+	}
+	return statuses, met, nil
+}
+
+// Resolve returns the status of every dependency of the provided
+// Recipe. It returns true if all its dependencies are met.
+//
+// NOTE:
+//	Only the direct dependencies decide if dependencies are met.
+// Dependencies of these dependencies are resolved to show the chain
+// & to detect cycles.
+func (d DependencyResolver) Resolve(
+	recipe types.Recipe,
+) ([]types.DependencyStatus, bool, error) {
+	return d.resolve(recipe, nil)
+}
+
+// isDependencyMet returns true if all the Recipes that this Recipe
+// depends on have reached their phases. Status is set with the
+// observed dependencies.
+//
+// NOTE:
+//	Recipes are read with the identity of this operator since these
+// are found in the cluster that runs this operator
+//
+// NOTE:
+//	A Recipe that can't be converted to its typed form e.g. a Recipe
+// with invalid schema affects only the dependencies that select it
+func (r *Runner) isDependencyMet() (bool, error) {
+	if len(r.Recipe.Spec.DependsOn) == 0 {
+		return true, nil
+	}
+	client, err := r.fixture.GetClientForAPIVersionAndKind(
+		gvk.APIVersionRecipe,
+		gvk.KindRecipe,
+	)
+	if err != nil {
+		return false, err
+	}
+	list, err := client.
+		Namespace(r.Recipe.GetNamespace()).
+		List(metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+	var resolver = DependencyResolver{
+		Invalid: map[string]error{},
+	}
+	for idx := range list.Items {
+		var item = &list.Items[idx]
+		var recipe types.Recipe
+		err := unstruct.ToTyped(item, &recipe)
+		if err != nil {
+			// Recipe is set with its identity only so that it can
+			// be selected
+			recipe = types.Recipe{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: item.GetNamespace(),
+					Name:      item.GetName(),
+					Labels:    item.GetLabels(),
+				},
+			}
+			resolver.Invalid[item.GetName()] = err
+		}
+		resolver.Recipes = append(resolver.Recipes, recipe)
+	}
+	statuses, met, err := resolver.Resolve(r.Recipe)
+	r.RecipeStatus.Dependencies = statuses
+	return met, err
+}
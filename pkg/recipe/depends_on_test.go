@@ -0,0 +1,433 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"openebs.io/metac/dynamic/clientset"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+
+	"mayadata.io/d-operators/pkg/kubernetes"
+	"mayadata.io/d-operators/types/gvk"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestDependencyResolverResolve(t *testing.T) {
+	newRecipe := func(
+		name string,
+		lbls map[string]string,
+		phase types.RecipeStatusPhase,
+		deps ...types.Dependency,
+	) types.Recipe {
+		return types.Recipe{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: lbls,
+			},
+			Spec: types.RecipeSpec{
+				DependsOn: deps,
+			},
+			Status: types.RecipeStatus{
+				Phase: phase,
+			},
+		}
+	}
+	var tests = map[string]struct {
+		recipe   types.Recipe
+		recipes  []types.Recipe
+		invalid  map[string]error
+		expected []types.DependencyStatus
+		isMet    bool
+		isErr    bool
+	}{
+		"no dependencies": {
+			recipe: newRecipe("app", nil, ""),
+			isMet:  true,
+		},
+		"dependency is met": {
+			recipe: newRecipe("app", nil, "", types.Dependency{Name: "setup"}),
+			recipes: []types.Recipe{
+				newRecipe("setup", nil, types.RecipeStatusCompleted),
+			},
+			expected: []types.DependencyStatus{
+				{
+					Name:  "setup",
+					Phase: types.RecipeStatusCompleted,
+					Want:  types.RecipeStatusCompleted,
+					Met:   true,
+				},
+			},
+			isMet: true,
+		},
+		"dependency is not found": {
+			recipe: newRecipe("app", nil, "", types.Dependency{Name: "setup"}),
+			expected: []types.DependencyStatus{
+				{
+					Name:    "setup",
+					Want:    types.RecipeStatusCompleted,
+					Message: "Recipe not found",
+				},
+			},
+		},
+		"dependency is in a different phase": {
+			recipe: newRecipe("app", nil, "", types.Dependency{
+				Name:  "setup",
+				Phase: types.RecipeStatusFailed,
+			}),
+			recipes: []types.Recipe{
+				newRecipe("setup", nil, types.RecipeStatusCompleted),
+			},
+			expected: []types.DependencyStatus{
+				{
+					Name:    "setup",
+					Phase:   types.RecipeStatusCompleted,
+					Want:    types.RecipeStatusFailed,
+					Message: `Waiting for phase "Failed"`,
+				},
+			},
+		},
+		"dependencies by label selector excludes self": {
+			recipe: newRecipe(
+				"app",
+				map[string]string{"tier": "setup"},
+				"",
+				types.Dependency{
+					LabelSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"tier": "setup"},
+					},
+				},
+			),
+			recipes: []types.Recipe{
+				newRecipe("app", map[string]string{"tier": "setup"}, ""),
+				newRecipe("crd", map[string]string{"tier": "setup"}, types.RecipeStatusCompleted),
+				newRecipe("rbac", map[string]string{"tier": "setup"}, types.RecipeStatusFailed),
+				newRecipe("other", nil, types.RecipeStatusCompleted),
+			},
+			expected: []types.DependencyStatus{
+				{
+					Name:     "crd",
+					Selector: "tier=setup",
+					Phase:    types.RecipeStatusCompleted,
+					Want:     types.RecipeStatusCompleted,
+					Met:      true,
+				},
+				{
+					Name:     "rbac",
+					Selector: "tier=setup",
+					Phase:    types.RecipeStatusFailed,
+					Want:     types.RecipeStatusCompleted,
+					Message:  `Waiting for phase "Completed"`,
+				},
+			},
+		},
+		"dependencies by label selector without matches": {
+			recipe: newRecipe("app", nil, "", types.Dependency{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "setup"},
+				},
+			}),
+			recipes: []types.Recipe{
+				newRecipe("other", nil, types.RecipeStatusCompleted),
+			},
+			expected: []types.DependencyStatus{
+				{
+					Selector: "tier=setup",
+					Want:     types.RecipeStatusCompleted,
+					Message:  "Recipe not found",
+				},
+			},
+		},
+		"dependency chain": {
+			recipe: newRecipe("app", nil, "", types.Dependency{Name: "setup"}),
+			recipes: []types.Recipe{
+				newRecipe(
+					"setup",
+					nil,
+					types.RecipeStatusCompleted,
+					types.Dependency{Name: "crd"},
+				),
+				newRecipe("crd", nil, types.RecipeStatusFailed),
+			},
+			expected: []types.DependencyStatus{
+				{
+					Name:  "setup",
+					Phase: types.RecipeStatusCompleted,
+					Want:  types.RecipeStatusCompleted,
+					Met:   true,
+					DependsOn: []types.DependencyStatus{
+						{
+							Name:    "crd",
+							Phase:   types.RecipeStatusFailed,
+							Want:    types.RecipeStatusCompleted,
+							Message: `Waiting for phase "Completed"`,
+						},
+					},
+				},
+			},
+			isMet: true,
+		},
+		"invalid dependency": {
+			recipe: newRecipe("app", nil, "", types.Dependency{
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"tier": "setup"},
+				},
+			}),
+			recipes: []types.Recipe{
+				newRecipe("crd", map[string]string{"tier": "setup"}, types.RecipeStatusCompleted),
+				newRecipe("rbac", map[string]string{"tier": "setup"}, ""),
+			},
+			invalid: map[string]error{
+				"rbac": errors.New("bad spec"),
+			},
+			expected: []types.DependencyStatus{
+				{
+					Name:     "crd",
+					Selector: "tier=setup",
+					Phase:    types.RecipeStatusCompleted,
+					Want:     types.RecipeStatusCompleted,
+					Met:      true,
+				},
+				{
+					Name:     "rbac",
+					Selector: "tier=setup",
+					Want:     types.RecipeStatusCompleted,
+					Message:  "Invalid recipe: bad spec",
+				},
+			},
+		},
+		"dependency cycle": {
+			recipe: newRecipe("app", nil, "", types.Dependency{Name: "setup"}),
+			recipes: []types.Recipe{
+				newRecipe("app", nil, "", types.Dependency{Name: "setup"}),
+				newRecipe(
+					"setup",
+					nil,
+					types.RecipeStatusCompleted,
+					types.Dependency{Name: "crd"},
+				),
+				newRecipe(
+					"crd",
+					nil,
+					types.RecipeStatusCompleted,
+					types.Dependency{Name: "app"},
+				),
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, isMet, err := DependencyResolver{
+				Recipes: mock.recipes,
+				Invalid: mock.invalid,
+			}.Resolve(mock.recipe)
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if _, isCycle := err.(*DependencyCycleError); mock.isErr && !isCycle {
+				t.Fatalf("Expected dependency cycle error got %+v", err)
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if mock.isErr {
+				return
+			}
+			if isMet != mock.isMet {
+				t.Fatalf("Expected met %t got %t", mock.isMet, isMet)
+			}
+			if diff := cmp.Diff(mock.expected, got); diff != "" {
+				t.Fatalf("Expected no diff got\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunnerRunAllTasksWithDependencies(t *testing.T) {
+	newRecipe := func(
+		name string,
+		spec map[string]interface{},
+		phase types.RecipeStatusPhase,
+	) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       gvk.KindRecipe,
+				"apiVersion": gvk.APIVersionRecipe,
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "ns",
+				},
+				"spec": spec,
+				"status": map[string]interface{}{
+					"phase": string(phase),
+				},
+			},
+		}
+	}
+	// fixture is loaded with Recipes where one of them can't be
+	// converted to its typed form
+	fixture := &BaseFixture{
+		getClientForAPIVersionAndKindFn: func(
+			apiversion string,
+			kind string,
+		) (*clientset.ResourceClient, error) {
+			di := dynamicfake.NewSimpleDynamicClient(
+				runtime.NewScheme(),
+				newRecipe("setup", nil, types.RecipeStatusCompleted),
+				newRecipe(
+					"broken",
+					map[string]interface{}{"tasks": "not-a-list"},
+					types.RecipeStatusInvalidSchema,
+				),
+				newRecipe(
+					"cycle-a",
+					map[string]interface{}{
+						"dependsOn": []interface{}{
+							map[string]interface{}{"name": "cycle-b"},
+						},
+					},
+					"",
+				),
+				newRecipe(
+					"cycle-b",
+					map[string]interface{}{
+						"dependsOn": []interface{}{
+							map[string]interface{}{"name": "cycle-a"},
+						},
+					},
+					"",
+				),
+			)
+			nri := di.Resource(schema.GroupVersionResource{
+				Group:    "dope.mayadata.io",
+				Version:  "v1",
+				Resource: "recipes",
+			})
+			return &clientset.ResourceClient{
+				ResourceInterface: nri,
+				APIResource:       &dynamicdiscovery.APIResource{},
+			}, nil
+		},
+	}
+	var tests = map[string]struct {
+		name            string
+		dependsOn       []types.Dependency
+		expectedPhase   types.RecipeStatusPhase
+		expectedReason  string
+		expectedMessage string
+	}{
+		"invalid recipe that is not a dependency": {
+			name:          "app",
+			dependsOn:     []types.Dependency{{Name: "setup"}},
+			expectedPhase: types.RecipeStatusCompleted,
+		},
+		"invalid recipe that is a dependency": {
+			name: "app",
+			dependsOn: []types.Dependency{
+				{Name: "setup"},
+				{Name: "broken"},
+			},
+			expectedPhase:   types.RecipeStatusNotEligible,
+			expectedReason:  "Did not meet dependencies",
+			expectedMessage: "Invalid recipe:",
+		},
+		"dependency cycle": {
+			name:           "cycle-a",
+			dependsOn:      []types.Dependency{{Name: "cycle-b"}},
+			expectedPhase:  types.RecipeStatusNotEligible,
+			expectedReason: "Dependency cycle: cycle-a -> cycle-b -> cycle-a",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			recipe := types.Recipe{
+				Spec: types.RecipeSpec{
+					DependsOn: mock.dependsOn,
+				},
+			}
+			recipe.SetName(mock.name)
+			recipe.SetNamespace("ns")
+			r := &Runner{
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Recipe: recipe,
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: make(map[string]types.TaskResult),
+				},
+				fixture: &Fixture{
+					BaseFixture: fixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			}
+			r.initEnabled()        // init to avoid nil pointers
+			err := r.runAllTasks() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if r.RecipeStatus.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected phase %q got %q: %s",
+					mock.expectedPhase,
+					r.RecipeStatus.Phase,
+					r.RecipeStatus.Reason,
+				)
+			}
+			if r.RecipeStatus.Reason != mock.expectedReason &&
+				mock.expectedReason != "" {
+				t.Fatalf(
+					"Expected reason %q got %q",
+					mock.expectedReason,
+					r.RecipeStatus.Reason,
+				)
+			}
+			if mock.expectedMessage == "" {
+				return
+			}
+			for _, dependency := range r.RecipeStatus.Dependencies {
+				if strings.HasPrefix(dependency.Message, mock.expectedMessage) {
+					return
+				}
+			}
+			t.Fatalf(
+				"Expected dependency with message %q got %+v",
+				mock.expectedMessage,
+				r.RecipeStatus.Dependencies,
+			)
+		})
+	}
+}
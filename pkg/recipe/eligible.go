@@ -96,7 +96,12 @@ type Eligibility struct {
 
 	RecipeName string
 
+	// ConditionResult holds the evaluation of Eligible.Condition
+	// if the condition was set
+	ConditionResult *types.EligibleConditionResult
+
 	eligibles   map[string]types.EligibleItem
+	checkIDs    map[string]string
 	observed    map[string][]unstructured.Unstructured
 	selected    map[string][]NamespaceName
 	grants      map[string]bool
@@ -158,6 +163,18 @@ func (e *Eligibility) initAndValidate() error {
 			)
 			continue
 		}
+		for _, pathCheck := range eligibleitem.PathChecks {
+			if pathCheck.Path == "" {
+				errs = append(
+					errs,
+					fmt.Sprintf(
+						"Invalid Eligible check %q: Missing path in path check: RecipeName %q",
+						when,
+						e.RecipeName,
+					),
+				)
+			}
+		}
 		key := NewDefaultingEligibleItemKey(eligibleitem)
 		if duplicates[key.String()] {
 			errs = append(
@@ -172,6 +189,24 @@ func (e *Eligibility) initAndValidate() error {
 		}
 		duplicates[key.String()] = true
 		e.eligibles[key.String()] = eligibleitem
+		if eligibleitem.ID == "" {
+			continue
+		}
+		if _, found := e.checkIDs[eligibleitem.ID]; found {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"Duplicate Eligible check id %q: RecipeName %q",
+					eligibleitem.ID,
+					e.RecipeName,
+				),
+			)
+			continue
+		}
+		e.checkIDs[eligibleitem.ID] = key.String()
+	}
+	if e.Eligible.Condition != nil {
+		errs = append(errs, e.validateCondition(*e.Eligible.Condition)...)
 	}
 	if len(errs) != 0 {
 		return errors.Errorf(
@@ -183,6 +218,75 @@ func (e *Eligibility) initAndValidate() error {
 	return nil
 }
 
+// validateCondition validates the provided condition & its nested
+// conditions
+func (e *Eligibility) validateCondition(cond types.EligibleCondition) []string {
+	var errs []string
+	if cond.CheckID != "" {
+		if len(cond.Conditions) != 0 || cond.Operator != "" {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"Invalid Eligible condition %q: Check id can't be used with operator or conditions: RecipeName %q",
+					cond.CheckID,
+					e.RecipeName,
+				),
+			)
+		}
+		if _, found := e.checkIDs[cond.CheckID]; !found {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"Invalid Eligible condition %q: Check id not found: RecipeName %q",
+					cond.CheckID,
+					e.RecipeName,
+				),
+			)
+		}
+		return errs
+	}
+	switch cond.Operator {
+	case "",
+		types.EligibleConditionOperatorAND,
+		types.EligibleConditionOperatorOR:
+		if len(cond.Conditions) == 0 {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"Invalid Eligible condition %q: Missing conditions: RecipeName %q",
+					cond.Operator,
+					e.RecipeName,
+				),
+			)
+		}
+	case types.EligibleConditionOperatorNOT:
+		if len(cond.Conditions) != 1 {
+			errs = append(
+				errs,
+				fmt.Sprintf(
+					"Invalid Eligible condition %q: Want 1 condition got %d: RecipeName %q",
+					cond.Operator,
+					len(cond.Conditions),
+					e.RecipeName,
+				),
+			)
+		}
+	default:
+		errs = append(
+			errs,
+			fmt.Sprintf(
+				"Unsupported Eligible condition operator %q: RecipeName %q",
+				cond.Operator,
+				e.RecipeName,
+			),
+		)
+	}
+	for _, nested := range cond.Conditions {
+		errs = append(errs, e.validateCondition(nested)...)
+	}
+	return errs
+}
+
 // NewEligibility returns a new instance of Eligibility
 func NewEligibility(config EligibilityConfig) (*Eligibility, error) {
 	e := &Eligibility{
@@ -191,6 +295,7 @@ func NewEligibility(config EligibilityConfig) (*Eligibility, error) {
 		Eligible:   config.Eligible,
 		Retry:      config.Retry,
 		eligibles:  make(map[string]types.EligibleItem),
+		checkIDs:   make(map[string]string),
 		observed:   make(map[string][]unstructured.Unstructured),
 		selected:   make(map[string][]NamespaceName),
 		grants:     make(map[string]bool),
@@ -204,16 +309,60 @@ func NewEligibility(config EligibilityConfig) (*Eligibility, error) {
 
 // EligibilityLog helps in debugging
 type EligibilityLog struct {
-	RecipeName        string                        `json:"recipeName"`
-	IsEligible        bool                          `json:"isEligible"`
-	Attempts          int                           `json:"attempts"`
-	IsTimeout         bool                          `json:"isTimeout"`
-	EligibileCriteria map[string]types.EligibleItem `json:"eligibileCriteria"`
-	Observed          map[string][]NamespaceName    `json:"observed"`
-	Selected          map[string][]NamespaceName    `json:"selected"`
-	Grants            map[string]bool               `json:"grants"`
-	EvalMessage       string                        `json:"evalMessage"`
-	Error             string                        `json:"error"`
+	RecipeName        string                         `json:"recipeName"`
+	IsEligible        bool                           `json:"isEligible"`
+	Attempts          int                            `json:"attempts"`
+	IsTimeout         bool                           `json:"isTimeout"`
+	EligibileCriteria map[string]types.EligibleItem  `json:"eligibileCriteria"`
+	Observed          map[string][]NamespaceName     `json:"observed"`
+	Selected          map[string][]NamespaceName     `json:"selected"`
+	Grants            map[string]bool                `json:"grants"`
+	Condition         *types.EligibleConditionResult `json:"condition,omitempty"`
+	EvalMessage       string                         `json:"evalMessage"`
+	Error             string                         `json:"error"`
+}
+
+// evalCondition evaluates the provided condition & its nested
+// conditions against the grants
+func (e *Eligibility) evalCondition(
+	cond types.EligibleCondition,
+) types.EligibleConditionResult {
+	if cond.CheckID != "" {
+		passed := e.grants[e.checkIDs[cond.CheckID]]
+		return types.EligibleConditionResult{
+			CheckID: cond.CheckID,
+			Passed:  passed,
+		}
+	}
+	var op = cond.Operator
+	if op == "" {
+		op = types.EligibleConditionOperatorOR
+	}
+	var result = types.EligibleConditionResult{
+		Operator: op,
+	}
+	var passedCount int
+	for _, nested := range cond.Conditions {
+		got := e.evalCondition(nested)
+		if got.Passed {
+			passedCount++
+		}
+		result.Conditions = append(result.Conditions, got)
+	}
+	switch op {
+	case types.EligibleConditionOperatorAND:
+		result.Passed = passedCount == len(cond.Conditions)
+	case types.EligibleConditionOperatorNOT:
+		result.Passed = passedCount == 0
+	default:
+		result.Passed = passedCount > 0
+	}
+	result.Message = fmt.Sprintf(
+		"Passed %d of %d condition(s)",
+		passedCount,
+		len(cond.Conditions),
+	)
+	return result
 }
 
 func (e *Eligibility) isEligibleCheck() (result bool) {
@@ -228,6 +377,13 @@ func (e *Eligibility) isEligibleCheck() (result bool) {
 		return
 	}
 
+	if e.Eligible.Condition != nil {
+		got := e.evalCondition(*e.Eligible.Condition)
+		e.ConditionResult = &got
+		result = got.Passed
+		return
+	}
+
 	when := e.Eligible.When
 	switch when {
 	case types.EligibleRuleAnyCheckPass:
@@ -326,16 +482,56 @@ func (e *Eligibility) isResourceLabelMatch(
 	return eval.RunMatch()
 }
 
+func (e *Eligibility) isResourcePathMatch(
+	target unstructured.Unstructured,
+	pathChecks []types.PathCheck,
+) (bool, error) {
+	for _, pathCheck := range pathChecks {
+		chk := NewPathChecker(
+			PathCheckingConfig{
+				BaseRunner: BaseRunner{
+					Fixture:  e.Fixture,
+					TaskName: e.RecipeName,
+					Retry:    e.Retry,
+				},
+				State:     &target,
+				PathCheck: pathCheck,
+			},
+		)
+		ismatch, err := chk.IsMatch(&target)
+		if err != nil || !ismatch {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (e *Eligibility) isResourceSelected(
+	target unstructured.Unstructured,
+	eligibleitem types.EligibleItem,
+) (bool, error) {
+	if target.Object == nil {
+		return false, nil
+	}
+	if eligibleitem.Name != "" && eligibleitem.Name != target.GetName() {
+		return false, nil
+	}
+	if len(eligibleitem.LabelSelector.MatchExpressions) != 0 ||
+		len(eligibleitem.LabelSelector.MatchLabels) != 0 {
+		ismatch, err := e.isResourceLabelMatch(
+			target,
+			eligibleitem.LabelSelector,
+		)
+		if err != nil || !ismatch {
+			return false, err
+		}
+	}
+	return e.isResourcePathMatch(target, eligibleitem.PathChecks)
+}
+
 func (e *Eligibility) setSelectedFromObservedResources() error {
 	for key, observedInstances := range e.observed {
 		eligibleitem := e.eligibles[key]
-		if len(eligibleitem.LabelSelector.MatchExpressions) == 0 &&
-			len(eligibleitem.LabelSelector.MatchLabels) == 0 {
-			// if there are no selections then all observed instance
-			// are considered selected
-			e.selected[key] = NewNamespaceNameList(observedInstances)
-			continue
-		}
 		// initialise this key with empty list
 		//
 		// NOTE:
@@ -343,17 +539,14 @@ func (e *Eligibility) setSelectedFromObservedResources() error {
 		// the final eligibility result
 		e.selected[key] = []NamespaceName{}
 		for _, instance := range observedInstances {
-			if instance.Object == nil {
-				continue
-			}
-			ismatch, err := e.isResourceLabelMatch(
+			isselected, err := e.isResourceSelected(
 				instance,
-				eligibleitem.LabelSelector,
+				eligibleitem,
 			)
 			if err != nil {
 				return err
 			}
-			if ismatch {
+			if isselected {
 				e.selected[key] = append(
 					e.selected[key],
 					NewNamespaceName(instance),
@@ -374,7 +567,9 @@ func (e *Eligibility) setObservedResources() error {
 		if err != nil {
 			return &DiscoveryError{err.Error()}
 		}
-		list, err := client.List(v1.ListOptions{})
+		list, err := client.
+			Namespace(eligibleitem.Namespace).
+			List(v1.ListOptions{})
 		if err != nil {
 			return err
 		}
@@ -413,6 +608,7 @@ func (e *Eligibility) IsEligible() (ok bool, err error) {
 				Observed:          ResourceMappedNamespaceNames(e.observed),
 				Selected:          e.selected,
 				Grants:            e.grants,
+				Condition:         e.ConditionResult,
 				EvalMessage:       e.evalMessage,
 				Error:             errmsg,
 			}
@@ -21,6 +21,7 @@ package recipe
 import (
 	"testing"
 
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"mayadata.io/d-operators/common/pointer"
 	types "mayadata.io/d-operators/types/recipe"
 )
@@ -64,6 +65,90 @@ func TestEligibilityInitAndValidate(t *testing.T) {
 			},
 			isErr: true,
 		},
+		"eligible check item with invalid path check": {
+			eligible: &types.Eligible{
+				Checks: []types.EligibleItem{
+					{
+						PathChecks: []types.PathCheck{
+							{
+								Operator: types.PathCheckOperatorExists,
+							},
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+		"eligible condition with unknown check id": {
+			eligible: &types.Eligible{
+				Checks: []types.EligibleItem{
+					{
+						ID: "pods",
+					},
+				},
+				Condition: &types.EligibleCondition{
+					CheckID: "junk",
+				},
+			},
+			isErr: true,
+		},
+		"eligible condition NOT with more than one condition": {
+			eligible: &types.Eligible{
+				Checks: []types.EligibleItem{
+					{
+						ID:   "pods",
+						Kind: "Pod",
+					},
+					{
+						ID:   "services",
+						Kind: "Service",
+					},
+				},
+				Condition: &types.EligibleCondition{
+					Operator: types.EligibleConditionOperatorNOT,
+					Conditions: []types.EligibleCondition{
+						{
+							CheckID: "pods",
+						},
+						{
+							CheckID: "services",
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+		"valid eligible condition": {
+			eligible: &types.Eligible{
+				Checks: []types.EligibleItem{
+					{
+						ID:   "pods",
+						Kind: "Pod",
+					},
+					{
+						ID:   "services",
+						Kind: "Service",
+					},
+				},
+				Condition: &types.EligibleCondition{
+					Operator: types.EligibleConditionOperatorAND,
+					Conditions: []types.EligibleCondition{
+						{
+							CheckID: "pods",
+						},
+						{
+							Operator: types.EligibleConditionOperatorNOT,
+							Conditions: []types.EligibleCondition{
+								{
+									CheckID: "services",
+								},
+							},
+						},
+					},
+				},
+			},
+			isErr: false,
+		},
 		"valid eligible check item": {
 			eligible: &types.Eligible{
 				Checks: []types.EligibleItem{
@@ -83,6 +168,7 @@ func TestEligibilityInitAndValidate(t *testing.T) {
 			e := &Eligibility{
 				Eligible:  mock.eligible,
 				eligibles: make(map[string]types.EligibleItem),
+				checkIDs:  make(map[string]string),
 			}
 			err := e.initAndValidate()
 			if mock.isErr && err == nil {
@@ -246,3 +332,272 @@ func TestEligibilitySetGrants(t *testing.T) {
 		})
 	}
 }
+
+func TestEligibilitySetSelectedFromObservedResources(t *testing.T) {
+	newDeployment := func(name string, readyReplicas int64) unstructured.Unstructured {
+		return unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "Deployment",
+				"apiVersion": "apps/v1",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": "default",
+				},
+				"status": map[string]interface{}{
+					"readyReplicas": readyReplicas,
+				},
+			},
+		}
+	}
+	var tests = map[string]struct {
+		observed         map[string][]unstructured.Unstructured
+		eligibles        map[string]types.EligibleItem
+		expectedSelected map[string]int
+		isErr            bool
+	}{
+		"no selectors": {
+			observed: map[string][]unstructured.Unstructured{
+				"apps/v1-Deployment": {
+					newDeployment("one", 1),
+					newDeployment("two", 3),
+				},
+			},
+			eligibles: map[string]types.EligibleItem{
+				"apps/v1-Deployment": {},
+			},
+			expectedSelected: map[string]int{
+				"apps/v1-Deployment": 2,
+			},
+		},
+		"select by name": {
+			observed: map[string][]unstructured.Unstructured{
+				"apps/v1-Deployment": {
+					newDeployment("one", 1),
+					newDeployment("two", 3),
+				},
+			},
+			eligibles: map[string]types.EligibleItem{
+				"apps/v1-Deployment": {
+					Name: "two",
+				},
+			},
+			expectedSelected: map[string]int{
+				"apps/v1-Deployment": 1,
+			},
+		},
+		"select by name & ready replicas GTE 3": {
+			observed: map[string][]unstructured.Unstructured{
+				"apps/v1-Deployment": {
+					newDeployment("one", 1),
+					newDeployment("two", 3),
+				},
+			},
+			eligibles: map[string]types.EligibleItem{
+				"apps/v1-Deployment": {
+					Name: "one",
+					PathChecks: []types.PathCheck{
+						{
+							Path:     "status.readyReplicas",
+							Operator: types.PathCheckOperatorGTE,
+							Value:    int64(3),
+							DataType: types.PathValueDataTypeInt64,
+						},
+					},
+				},
+			},
+			expectedSelected: map[string]int{
+				"apps/v1-Deployment": 0,
+			},
+		},
+		"select by ready replicas GTE 3": {
+			observed: map[string][]unstructured.Unstructured{
+				"apps/v1-Deployment": {
+					newDeployment("one", 1),
+					newDeployment("two", 3),
+					newDeployment("three", 4),
+				},
+			},
+			eligibles: map[string]types.EligibleItem{
+				"apps/v1-Deployment": {
+					PathChecks: []types.PathCheck{
+						{
+							Path:     "status.readyReplicas",
+							Operator: types.PathCheckOperatorGTE,
+							Value:    int64(3),
+							DataType: types.PathValueDataTypeInt64,
+						},
+					},
+				},
+			},
+			expectedSelected: map[string]int{
+				"apps/v1-Deployment": 2,
+			},
+		},
+		"select by missing path": {
+			observed: map[string][]unstructured.Unstructured{
+				"apps/v1-Deployment": {
+					newDeployment("one", 1),
+				},
+			},
+			eligibles: map[string]types.EligibleItem{
+				"apps/v1-Deployment": {
+					PathChecks: []types.PathCheck{
+						{
+							Path:     "status.junk",
+							Operator: types.PathCheckOperatorEquals,
+							Value:    int64(1),
+							DataType: types.PathValueDataTypeInt64,
+						},
+					},
+				},
+			},
+			expectedSelected: map[string]int{
+				"apps/v1-Deployment": 0,
+			},
+		},
+		"select by path with invalid value type": {
+			observed: map[string][]unstructured.Unstructured{
+				"apps/v1-Deployment": {
+					newDeployment("one", 1),
+				},
+			},
+			eligibles: map[string]types.EligibleItem{
+				"apps/v1-Deployment": {
+					PathChecks: []types.PathCheck{
+						{
+							Path:     "status.readyReplicas",
+							Operator: types.PathCheckOperatorEquals,
+							Value:    "one",
+							DataType: types.PathValueDataTypeInt64,
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			e := &Eligibility{
+				observed:  mock.observed,
+				eligibles: mock.eligibles,
+				selected:  make(map[string][]NamespaceName),
+			}
+			err := e.setSelectedFromObservedResources()
+			if mock.isErr && err == nil {
+				t.Fatal("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if mock.isErr {
+				return
+			}
+			for key, count := range mock.expectedSelected {
+				if len(e.selected[key]) != count {
+					t.Fatalf(
+						"Expected selected count %d got %d: Key %s",
+						count,
+						len(e.selected[key]),
+						key,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestEligibilityEvalCondition(t *testing.T) {
+	// (A and B) or not C
+	var condition = types.EligibleCondition{
+		Operator: types.EligibleConditionOperatorOR,
+		Conditions: []types.EligibleCondition{
+			{
+				Operator: types.EligibleConditionOperatorAND,
+				Conditions: []types.EligibleCondition{
+					{
+						CheckID: "A",
+					},
+					{
+						CheckID: "B",
+					},
+				},
+			},
+			{
+				Operator: types.EligibleConditionOperatorNOT,
+				Conditions: []types.EligibleCondition{
+					{
+						CheckID: "C",
+					},
+				},
+			},
+		},
+	}
+	var checkIDs = map[string]string{
+		"A": "v1-Pod-A",
+		"B": "v1-Service-B",
+		"C": "v1-Secret-C",
+	}
+	var tests = map[string]struct {
+		grants     map[string]bool
+		isEligible bool
+		isANDPass  bool
+	}{
+		"A & B pass & C pass": {
+			grants: map[string]bool{
+				"v1-Pod-A":     true,
+				"v1-Service-B": true,
+				"v1-Secret-C":  true,
+			},
+			isEligible: true,
+			isANDPass:  true,
+		},
+		"A fails & C pass": {
+			grants: map[string]bool{
+				"v1-Pod-A":     false,
+				"v1-Service-B": true,
+				"v1-Secret-C":  true,
+			},
+			isEligible: false,
+			isANDPass:  false,
+		},
+		"A fails & C fails": {
+			grants: map[string]bool{
+				"v1-Pod-A":     false,
+				"v1-Service-B": true,
+				"v1-Secret-C":  false,
+			},
+			isEligible: true,
+			isANDPass:  false,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			e := &Eligibility{
+				Eligible: &types.Eligible{
+					Condition: &condition,
+				},
+				checkIDs: checkIDs,
+				grants:   mock.grants,
+			}
+			got := e.isEligibleCheck()
+			if got != mock.isEligible {
+				t.Fatalf("Expected eligible %t got %t", mock.isEligible, got)
+			}
+			if e.ConditionResult == nil {
+				t.Fatalf("Expected condition result got nil")
+			}
+			if e.ConditionResult.Conditions[0].Passed != mock.isANDPass {
+				t.Fatalf(
+					"Expected AND branch passed %t got %t",
+					mock.isANDPass,
+					e.ConditionResult.Conditions[0].Passed,
+				)
+			}
+		})
+	}
+}
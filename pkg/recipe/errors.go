@@ -16,6 +16,11 @@ limitations under the License.
 
 package recipe
 
+import (
+	"fmt"
+	"strings"
+)
+
 // DiscoveryError defines a discovery error
 type DiscoveryError struct {
 	Err string
@@ -25,3 +30,15 @@ type DiscoveryError struct {
 func (e *DiscoveryError) Error() string {
 	return e.Err
 }
+
+// DependencyCycleError defines a cycle in the dependencies of
+// Recipes
+type DependencyCycleError struct {
+	// Chain has the names of the Recipes that form this cycle
+	Chain []string
+}
+
+// Error implements error interface
+func (e *DependencyCycleError) Error() string {
+	return fmt.Sprintf("Dependency cycle: %s", strings.Join(e.Chain, " -> "))
+}
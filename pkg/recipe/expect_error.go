@@ -0,0 +1,130 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// MatchExpectedError verifies if the provided api error matches
+// the expected error. It returns the mismatches if any.
+func MatchExpectedError(expect types.ExpectError, status metav1.Status) []string {
+	var mismatches []string
+	if expect.Code != nil && *expect.Code != status.Code {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"Want code %d got %d",
+			*expect.Code,
+			status.Code,
+		))
+	}
+	if expect.Reason != "" && metav1.StatusReason(expect.Reason) != status.Reason {
+		mismatches = append(mismatches, fmt.Sprintf(
+			"Want reason %q got %q",
+			expect.Reason,
+			status.Reason,
+		))
+	}
+	if expect.MessagePattern != "" {
+		matched, err := regexp.MatchString(expect.MessagePattern, status.Message)
+		if err != nil {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"Invalid messagePattern %q: %s",
+				expect.MessagePattern,
+				err.Error(),
+			))
+		} else if !matched {
+			mismatches = append(mismatches, fmt.Sprintf(
+				"Want message matching %q got %q",
+				expect.MessagePattern,
+				status.Message,
+			))
+		}
+	}
+	return mismatches
+}
+
+// expectedError returns the error that the action of this task
+// is expected to result in
+func (r *TaskRunner) expectedError() *types.ExpectError {
+	switch {
+	case r.Task.Create != nil:
+		return r.Task.Create.ExpectError
+	case r.Task.Apply != nil:
+		return r.Task.Apply.ExpectError
+	case r.Task.Delete != nil:
+		return r.Task.Delete.ExpectError
+	default:
+		return nil
+	}
+}
+
+// resultFromExpectedError returns the result of this task based
+// on the expected error & the actual error
+//
+// NOTE:
+//	Errors other than api errors are handled similar to the tasks
+// that do not expect any error. These errors are not the result of
+// the api server processing this action.
+func (r *TaskRunner) resultFromExpectedError(
+	expect types.ExpectError,
+	got *types.TaskResult,
+	err error,
+) (types.TaskResult, error) {
+	if err == nil {
+		var message = "Expected error: Action succeeded"
+		if got != nil && got.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, got.Message)
+		}
+		return types.TaskResult{
+			Step:    r.TaskIndex,
+			Phase:   types.TaskStatusFailed,
+			Message: message,
+		}, nil
+	}
+	apiStatus, isAPIError := errors.Cause(err).(apierrors.APIStatus)
+	if !isAPIError {
+		return r.resultFromError(err)
+	}
+	status := apiStatus.Status()
+	mismatches := MatchExpectedError(expect, status)
+	if len(mismatches) != 0 {
+		return types.TaskResult{
+			Step:    r.TaskIndex,
+			Phase:   types.TaskStatusFailed,
+			Message: fmt.Sprintf("Unexpected error: %s", strings.Join(mismatches, ": ")),
+			Verbose: err.Error(),
+		}, nil
+	}
+	return types.TaskResult{
+		Step:  r.TaskIndex,
+		Phase: types.TaskStatusPassed,
+		Message: fmt.Sprintf(
+			"Failed as expected: Code %d: Reason %s: %s",
+			status.Code,
+			status.Reason,
+			status.Message,
+		),
+	}, nil
+}
@@ -0,0 +1,240 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+
+	"mayadata.io/d-operators/common/pointer"
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestTaskRunnerRunExpectError(t *testing.T) {
+	invalid := apierrors.NewInvalid(
+		schema.GroupKind{Kind: "ConfigMap"},
+		"cm",
+		field.ErrorList{field.Invalid(field.NewPath("data"), "x", "denied by webhook")},
+	)
+	var tests = map[string]struct {
+		fixture       *BaseFixture
+		expectError   *types.ExpectError
+		ignoreError   types.IgnoreErrorRule
+		expectedPhase types.TaskStatusPhase
+		isErr         bool
+	}{
+		"error without expectation": {
+			fixture: NewErrorFixture(invalid),
+			isErr:   true,
+		},
+		"matching code reason & message": {
+			fixture: NewErrorFixture(invalid),
+			expectError: &types.ExpectError{
+				Code:           pointer.Int32(422),
+				Reason:         "Invalid",
+				MessagePattern: "denied by (webhook|policy)",
+			},
+			expectedPhase: types.TaskStatusPassed,
+		},
+		"any api error": {
+			fixture:       NewErrorFixture(invalid),
+			expectError:   &types.ExpectError{},
+			expectedPhase: types.TaskStatusPassed,
+		},
+		"mismatching code": {
+			fixture: NewErrorFixture(invalid),
+			expectError: &types.ExpectError{
+				Code: pointer.Int32(409),
+			},
+			expectedPhase: types.TaskStatusFailed,
+		},
+		"mismatching message": {
+			fixture: NewErrorFixture(invalid),
+			expectError: &types.ExpectError{
+				MessagePattern: "^quota exceeded",
+			},
+			expectedPhase: types.TaskStatusFailed,
+		},
+		"expected error takes precedence over ignore error": {
+			fixture: NewErrorFixture(invalid),
+			expectError: &types.ExpectError{
+				Reason: "AlreadyExists",
+			},
+			ignoreError:   types.IgnoreErrorAsWarning,
+			expectedPhase: types.TaskStatusFailed,
+		},
+		"action succeeds": {
+			fixture: NoopFixture,
+			expectError: &types.ExpectError{
+				Reason: "Invalid",
+			},
+			expectedPhase: types.TaskStatusFailed,
+		},
+		"non api error": {
+			fixture: NewErrorFixture(errors.New("connection refused")),
+			expectError: &types.ExpectError{
+				Reason: "Invalid",
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			tr := &TaskRunner{
+				BaseRunner: BaseRunner{
+					Fixture:   &Fixture{BaseFixture: mock.fixture},
+					Retry:     kubernetes.NewRetry(kubernetes.RetryConfig{RunOnce: true}),
+					TaskIndex: 1,
+					TaskName:  "create-config-map",
+				},
+				Task: types.Task{
+					Name:            "create-config-map",
+					IgnoreErrorRule: mock.ignoreError,
+					Create: &types.Create{
+						State: &unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"kind":       "ConfigMap",
+								"apiVersion": "v1",
+								"metadata": map[string]interface{}{
+									"name": "cm",
+								},
+							},
+						},
+						ExpectError: mock.expectError,
+					},
+				},
+			}
+			got, err := tr.Run()
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if got.Phase != mock.expectedPhase {
+				t.Fatalf("Expected phase %q got %q: %s", mock.expectedPhase, got.Phase, got)
+			}
+		})
+	}
+}
+
+func TestTaskRunnerRunExpectErrorIsNotRetried(t *testing.T) {
+	configMap := func() *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "ConfigMap",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name": "cm",
+				},
+			},
+		}
+	}
+	alreadyExists := apierrors.NewAlreadyExists(
+		schema.GroupResource{Resource: "configmaps"},
+		"cm",
+	)
+	conflict := apierrors.NewConflict(
+		schema.GroupResource{Resource: "configmaps"},
+		"cm",
+		errors.New("object has been modified"),
+	)
+	var tests = map[string]struct {
+		err  error
+		task types.Task
+	}{
+		"create results in already exists": {
+			err: alreadyExists,
+			task: types.Task{
+				Create: &types.Create{
+					State: configMap(),
+					ExpectError: &types.ExpectError{
+						Reason: "AlreadyExists",
+					},
+				},
+			},
+		},
+		"apply results in already exists": {
+			err: alreadyExists,
+			task: types.Task{
+				Apply: &types.Apply{
+					State: configMap(),
+					ExpectError: &types.ExpectError{
+						Reason: "AlreadyExists",
+					},
+				},
+			},
+		},
+		"apply results in conflict": {
+			err: conflict,
+			task: types.Task{
+				Apply: &types.Apply{
+					State: configMap(),
+					ExpectError: &types.ExpectError{
+						Code: pointer.Int32(409),
+					},
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			// expected error should not be retried till this timeout
+			timeout := 30 * time.Second
+			interval := 10 * time.Millisecond
+			tr := &TaskRunner{
+				BaseRunner: BaseRunner{
+					Fixture: &Fixture{BaseFixture: NewErrorFixture(mock.err)},
+					Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+						WaitTimeout:  &timeout,
+						WaitInterval: &interval,
+					}),
+					TaskIndex: 1,
+					TaskName:  "config-map",
+				},
+				Task: mock.task,
+			}
+			start := time.Now()
+			got, err := tr.Run()
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if got.Phase != types.TaskStatusPassed {
+				t.Fatalf("Expected phase %q got %q: %s", types.TaskStatusPassed, got.Phase, got)
+			}
+			if time.Since(start) > 5*time.Second {
+				t.Fatalf("Expected action to run once got %s", time.Since(start))
+			}
+		})
+	}
+}
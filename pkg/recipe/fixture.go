@@ -54,6 +54,9 @@ type Fixture struct {
 
 	apiDiscovery *dynamicdiscovery.APIResourceDiscovery
 
+	// config used to build the clients of this fixture
+	kubeConfig *rest.Config
+
 	// dynamic client to invoke kubernetes operations
 	// against kubernetes native as well as custom resources
 	dynamicClientset *dynamicclientset.Clientset
@@ -74,6 +77,9 @@ type Fixture struct {
 	tearDown      bool
 	teardownFuncs []func() error
 
+	// teardowns are added to this fixture if set
+	teardownTo *Fixture
+
 	// error as value
 	err error
 }
@@ -86,6 +92,10 @@ func (f *Fixture) setAPIDiscovery(config FixtureConfig) {
 	f.apiDiscovery = config.APIDiscovery
 }
 
+func (f *Fixture) setKubeConfig(config FixtureConfig) {
+	f.kubeConfig = config.KubeConfig
+}
+
 func (f *Fixture) setCRDClient(config FixtureConfig) {
 	if config.KubeConfig == nil {
 		f.err = errors.Errorf(
@@ -131,6 +141,7 @@ func NewFixture(config FixtureConfig) (*Fixture, error) {
 	var setters = []func(FixtureConfig){
 		f.setTearDown,
 		f.setAPIDiscovery,
+		f.setKubeConfig,
 		f.setCRDClient,
 		f.setCRDClientV1,
 		f.setDynamicClientset,
@@ -145,6 +156,38 @@ func NewFixture(config FixtureConfig) (*Fixture, error) {
 	return f, nil
 }
 
+// Impersonate returns a new instance of Fixture whose clients act
+// as the provided identity. The new instance uses the same api
+// discovery & teardown settings as this fixture.
+//
+// NOTE:
+//	A fixture that impersonates an identity can't impersonate again.
+// Replacing the impersonated identity lets the credentials of this
+// operator act as any identity & hence escalate privileges.
+func (f *Fixture) Impersonate(identity rest.ImpersonationConfig) (*Fixture, error) {
+	if f.kubeConfig == nil {
+		return nil, errors.Errorf(
+			"Failed to impersonate %q: Nil kube config",
+			identity.UserName,
+		)
+	}
+	if f.kubeConfig.Impersonate.UserName != "" ||
+		len(f.kubeConfig.Impersonate.Groups) != 0 {
+		return nil, errors.Errorf(
+			"Failed to impersonate %q: Already impersonating %q",
+			identity.UserName,
+			f.kubeConfig.Impersonate.UserName,
+		)
+	}
+	config := rest.CopyConfig(f.kubeConfig)
+	config.Impersonate = identity
+	return NewFixture(FixtureConfig{
+		KubeConfig:   config,
+		APIDiscovery: f.apiDiscovery,
+		IsTearDown:   f.tearDown,
+	})
+}
+
 // TearDown cleans up resources created through this instance
 // of the test fixture.
 func (f *Fixture) TearDown() {
@@ -185,6 +228,10 @@ func (f *Fixture) AddToTeardown(teardown func() error) {
 	if !f.tearDown {
 		return
 	}
+	if f.teardownTo != nil {
+		f.teardownTo.AddToTeardown(teardown)
+		return
+	}
 	f.teardownFuncs = append(f.teardownFuncs, teardown)
 }
 
@@ -0,0 +1,253 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"mayadata.io/d-operators/common/unstruct"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// forEachRefRegex matches the references to an item e.g. $(item)
+// or $(item.metadata.name) & to its position i.e. $(index)
+var forEachRefRegex = regexp.MustCompile(`\$\((item|index)(\.[^)]+)?\)`)
+
+// ForEachItem is an item that a task is run for
+type ForEachItem struct {
+	Index int
+	Value interface{}
+}
+
+// resolve returns the value referred to by the provided reference
+func (i ForEachItem) resolve(ref string) (interface{}, error) {
+	match := forEachRefRegex.FindStringSubmatch(ref)
+	if match[1] == "index" {
+		if match[2] != "" {
+			return nil, errors.Errorf("Invalid reference %q: Index has no fields", ref)
+		}
+		// index is a string even if it is the entire string value
+		// since the value may be a name e.g. metadata.name
+		return strconv.Itoa(i.Index), nil
+	}
+	if match[2] == "" {
+		return i.Value, nil
+	}
+	obj, ok := i.Value.(map[string]interface{})
+	if !ok {
+		return nil, errors.Errorf(
+			"Invalid reference %q: Want item of type map got %T",
+			ref,
+			i.Value,
+		)
+	}
+	fields := strings.Split(strings.TrimPrefix(match[2], "."), ".")
+	val, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid reference %q", ref)
+	}
+	if !found {
+		return nil, errors.Errorf("Invalid reference %q: Field not found", ref)
+	}
+	return val, nil
+}
+
+// Substitute returns a copy of the provided value with the references
+// to this item replaced by their values
+func (i ForEachItem) Substitute(given interface{}) (interface{}, error) {
+	return substitute(given, forEachRefRegex, i.resolve)
+}
+
+// substitute returns a copy of the provided value with the references
+// matched by the provided regex replaced by their resolved values
+//
+// NOTE:
+//	A string that is a single reference is replaced by the referred
+// value as is. References within a string are replaced by their
+// string forms.
+func substitute(
+	given interface{},
+	refRegex *regexp.Regexp,
+	resolve func(ref string) (interface{}, error),
+) (interface{}, error) {
+	switch val := given.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, v := range val {
+			got, err := substitute(v, refRegex, resolve)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = got
+		}
+		return out, nil
+	case []interface{}:
+		out := make([]interface{}, 0, len(val))
+		for _, v := range val {
+			got, err := substitute(v, refRegex, resolve)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, got)
+		}
+		return out, nil
+	case string:
+		if refRegex.FindString(val) == val && val != "" {
+			return resolve(val)
+		}
+		var err error
+		out := refRegex.ReplaceAllStringFunc(val, func(ref string) string {
+			if err != nil {
+				return ref
+			}
+			var got interface{}
+			got, err = resolve(ref)
+			if err != nil {
+				return ref
+			}
+			if str, ok := got.(string); ok {
+				return str
+			}
+			var raw []byte
+			raw, err = json.Marshal(got)
+			return string(raw)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return out, nil
+	default:
+		return given, nil
+	}
+}
+
+// forEachItems returns the items that the provided task needs to
+// be run for
+func (r *Runner) forEachItems(task types.Task) ([]interface{}, error) {
+	forEach := task.ForEach
+	switch {
+	case forEach.Param != "":
+		param, found := r.Recipe.Spec.Params[forEach.Param]
+		if !found {
+			return nil, errors.Errorf(
+				"Invalid forEach: Param %q not found",
+				forEach.Param,
+			)
+		}
+		items, ok := param.([]interface{})
+		if !ok {
+			return nil, errors.Errorf(
+				"Invalid forEach: Param %q: Want list got %T",
+				forEach.Param,
+				param,
+			)
+		}
+		return items, nil
+	case forEach.List != nil:
+		if forEach.List.State == nil {
+			return nil, errors.Errorf("Invalid forEach: Missing list state")
+		}
+		fixture, err := r.buildTaskFixture(task)
+		if err != nil {
+			return nil, err
+		}
+		got, err := NewLister(ListableConfig{
+			BaseRunner: BaseRunner{
+				Fixture:  fixture,
+				TaskName: task.Name,
+				Retry:    r.Retry,
+			},
+			List: forEach.List,
+		}).Run()
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid forEach: List failed")
+		}
+		var items []interface{}
+		for _, item := range got.Items.Items {
+			items = append(items, item.Object)
+		}
+		return items, nil
+	default:
+		return forEach.Items, nil
+	}
+}
+
+// expandTask returns one task per forEach item of the provided
+// task. The provided task is returned as is if it has no forEach.
+func (r *Runner) expandTask(task types.Task) ([]types.Task, error) {
+	if task.ForEach == nil {
+		return []types.Task{task}, nil
+	}
+	items, err := r.forEachItems(task)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Expand failed: Task %q", task.Name)
+	}
+	// forEach is not needed in the expanded tasks
+	template := task
+	template.ForEach = nil
+	var obj map[string]interface{}
+	err = unstruct.MarshalThenUnmarshal(template, &obj)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Expand failed: Task %q", task.Name)
+	}
+	var tasks []types.Task
+	for idx, value := range items {
+		got, err := ForEachItem{Index: idx, Value: value}.Substitute(obj)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Expand failed: Task %q: Item %d",
+				task.Name,
+				idx,
+			)
+		}
+		var expanded types.Task
+		err = unstruct.MarshalThenUnmarshal(got, &expanded)
+		if err != nil {
+			return nil, errors.Wrapf(
+				err,
+				"Expand failed: Task %q: Item %d",
+				task.Name,
+				idx,
+			)
+		}
+		expanded.Name = fmt.Sprintf("%s[%d]", task.Name, idx)
+		tasks = append(tasks, expanded)
+	}
+	return tasks, nil
+}
+
+// expandTasks returns the provided tasks with every forEach task
+// replaced by its expanded tasks
+func (r *Runner) expandTasks(tasks []types.Task) ([]types.Task, error) {
+	var out []types.Task
+	for _, task := range tasks {
+		expanded, err := r.expandTask(task)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, expanded...)
+	}
+	return out, nil
+}
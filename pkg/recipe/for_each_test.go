@@ -0,0 +1,268 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestForEachItemSubstitute(t *testing.T) {
+	var tests = map[string]struct {
+		item     ForEachItem
+		given    interface{}
+		expected interface{}
+		isErr    bool
+	}{
+		"no references": {
+			item:     ForEachItem{Value: "ns-1"},
+			given:    map[string]interface{}{"name": "cm", "count": int64(1)},
+			expected: map[string]interface{}{"name": "cm", "count": int64(1)},
+		},
+		"item within a string": {
+			item:     ForEachItem{Index: 2, Value: "ns-1"},
+			given:    []interface{}{"cm-$(item)-$(index)"},
+			expected: []interface{}{"cm-ns-1-2"},
+		},
+		"index as is": {
+			item:     ForEachItem{Index: 1, Value: "ns-1"},
+			given:    map[string]interface{}{"name": "$(index)"},
+			expected: map[string]interface{}{"name": "1"},
+		},
+		"item as is": {
+			item: ForEachItem{Value: map[string]interface{}{"app": "web"}},
+			given: map[string]interface{}{
+				"labels": "$(item)",
+			},
+			expected: map[string]interface{}{
+				"labels": map[string]interface{}{"app": "web"},
+			},
+		},
+		"field of an item": {
+			item: ForEachItem{Value: map[string]interface{}{
+				"metadata": map[string]interface{}{"name": "node-1"},
+			}},
+			given:    "$(item.metadata.name)",
+			expected: "node-1",
+		},
+		"non string field within a string": {
+			item:     ForEachItem{Value: map[string]interface{}{"size": 3.0}},
+			given:    "size-$(item.size)",
+			expected: "size-3",
+		},
+		"missing field": {
+			item:  ForEachItem{Value: map[string]interface{}{}},
+			given: "$(item.metadata.name)",
+			isErr: true,
+		},
+		"field of a scalar item": {
+			item:  ForEachItem{Value: "ns-1"},
+			given: "cm-$(item.name)",
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := mock.item.Substitute(mock.given)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if diff := cmp.Diff(mock.expected, got); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunnerExpandTasks(t *testing.T) {
+	// newTask returns a task that creates a config map in the
+	// namespace referred to by $(item)
+	newTask := func(forEach *types.ForEach) types.Task {
+		return types.Task{
+			Name:    "create",
+			ForEach: forEach,
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name":      "cm",
+							"namespace": "$(item)",
+						},
+					},
+				},
+			},
+		}
+	}
+	var tests = map[string]struct {
+		tasks              []types.Task
+		params             map[string]interface{}
+		baseFixture        *BaseFixture
+		expectedNames      []string
+		expectedNamespaces []string
+		isErr              bool
+	}{
+		"no forEach": {
+			tasks:              []types.Task{newTask(nil)},
+			expectedNames:      []string{"create"},
+			expectedNamespaces: []string{"$(item)"},
+		},
+		"static items": {
+			tasks: []types.Task{
+				newTask(&types.ForEach{Items: []interface{}{"ns-1", "ns-2"}}),
+			},
+			expectedNames:      []string{"create[0]", "create[1]"},
+			expectedNamespaces: []string{"ns-1", "ns-2"},
+		},
+		"no items": {
+			tasks: []types.Task{
+				newTask(&types.ForEach{Items: []interface{}{}}),
+			},
+		},
+		"param": {
+			tasks: []types.Task{
+				newTask(&types.ForEach{Param: "namespaces"}),
+			},
+			params: map[string]interface{}{
+				"namespaces": []interface{}{"ns-1"},
+			},
+			expectedNames:      []string{"create[0]"},
+			expectedNamespaces: []string{"ns-1"},
+		},
+		"missing param": {
+			tasks: []types.Task{
+				newTask(&types.ForEach{Param: "namespaces"}),
+			},
+			isErr: true,
+		},
+		"param is not a list": {
+			tasks: []types.Task{
+				newTask(&types.ForEach{Param: "namespaces"}),
+			},
+			params: map[string]interface{}{
+				"namespaces": "ns-1",
+			},
+			isErr: true,
+		},
+		"list query": {
+			tasks: []types.Task{
+				{
+					Name: "label",
+					ForEach: &types.ForEach{
+						List: &types.List{
+							State: &unstructured.Unstructured{
+								Object: map[string]interface{}{
+									"kind":       "ConfigMap",
+									"apiVersion": "v1",
+								},
+							},
+						},
+					},
+					Apply: &types.Apply{
+						State: &unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"kind":       "ConfigMap",
+								"apiVersion": "v1",
+								"metadata": map[string]interface{}{
+									"name":      "$(item.metadata.name)",
+									"namespace": "ns-$(index)",
+								},
+							},
+						},
+					},
+				},
+			},
+			baseFixture:        NoopConfigMapFixture,
+			expectedNames:      []string{"label[0]"},
+			expectedNamespaces: []string{"ns-0"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			f := &Fixture{
+				BaseFixture: NoopFixture,
+			}
+			if mock.baseFixture != nil {
+				f.BaseFixture = mock.baseFixture
+			}
+			timeout := 1 * time.Second // unit test don't need to retry
+			r := &Runner{
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						Params: mock.params,
+					},
+				},
+				fixture: f,
+			}
+			got, err := r.expandTasks(mock.tasks) // method under test
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			var names, namespaces []string
+			for _, task := range got {
+				if task.ForEach != nil {
+					t.Fatalf("Expected no forEach in expanded task %q", task.Name)
+				}
+				names = append(names, task.Name)
+				var state *unstructured.Unstructured
+				if task.Create != nil {
+					state = task.Create.State
+				} else {
+					state = task.Apply.State
+					if state.GetName() != "cm-1" {
+						t.Fatalf("Expected name %q got %q", "cm-1", state.GetName())
+					}
+				}
+				namespaces = append(namespaces, state.GetNamespace())
+			}
+			if diff := cmp.Diff(mock.expectedNames, names); diff != "" {
+				t.Fatalf("Expected no diff in names got:\n%s", diff)
+			}
+			if diff := cmp.Diff(mock.expectedNamespaces, namespaces); diff != "" {
+				t.Fatalf("Expected no diff in namespaces got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"k8s.io/client-go/rest"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// ServiceAccountIdentity returns the identity that gets
+// authenticated for the provided service account
+//
+// NOTE:
+//	Groups are set similar to the ones set for a service account's
+// token so that role bindings against these groups are honoured
+func ServiceAccountIdentity(namespace, name string) rest.ImpersonationConfig {
+	return rest.ImpersonationConfig{
+		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", namespace, name),
+		Groups: []string{
+			"system:serviceaccounts",
+			fmt.Sprintf("system:serviceaccounts:%s", namespace),
+		},
+	}
+}
+
+// initServiceAccount lets the eligibility checks & tasks of this
+// Recipe be run as the service account set in its spec
+//
+// NOTE:
+//	This lets the RBAC of the service account be enforced instead
+// of the permissions of this operator
+func (r *Runner) initServiceAccount() {
+	name := r.Recipe.Spec.ServiceAccountName
+	if name == "" {
+		// no further action required
+		return
+	}
+	f, err := r.getTasksFixture().Impersonate(
+		ServiceAccountIdentity(r.Recipe.GetNamespace(), name),
+	)
+	if err != nil {
+		r.err = errors.Wrapf(
+			err,
+			"Init service account failed: Recipe %q / %q",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		return
+	}
+	r.tasksFixture = f
+}
+
+// TaskIdentity returns the identity that is impersonated to run a
+// task. Namespace is used if the service account to be impersonated
+// does not set its namespace.
+func TaskIdentity(impersonate types.Impersonate, namespace string) rest.ImpersonationConfig {
+	if impersonate.ServiceAccount == nil {
+		return rest.ImpersonationConfig{
+			UserName: impersonate.User,
+			Groups:   impersonate.Groups,
+		}
+	}
+	if impersonate.ServiceAccount.Namespace != "" {
+		namespace = impersonate.ServiceAccount.Namespace
+	}
+	identity := ServiceAccountIdentity(namespace, impersonate.ServiceAccount.Name)
+	identity.Groups = append(identity.Groups, impersonate.Groups...)
+	return identity
+}
+
+// buildTaskFixture returns the fixture that is used to run the
+// provided task
+//
+// NOTE:
+//	Teardowns of an impersonated task are added to the fixture of
+// the Recipe so that these get executed along with the Recipe's
+// teardowns
+//
+// NOTE:
+//	A task can't impersonate if the Recipe is run as a service
+// account since the task would otherwise be run with the privileges
+// of this operator
+func (r *Runner) buildTaskFixture(task types.Task) (*Fixture, error) {
+	f := r.getTasksFixture()
+	if task.Impersonate == nil {
+		return f, nil
+	}
+	if r.Recipe.Spec.ServiceAccountName != "" {
+		return nil, errors.Errorf(
+			"Init task identity failed: Task %q: Can't impersonate when Recipe is run as service account %q",
+			task.Name,
+			r.Recipe.Spec.ServiceAccountName,
+		)
+	}
+	identity := TaskIdentity(*task.Impersonate, r.Recipe.GetNamespace())
+	impersonated, err := f.Impersonate(identity)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Init task identity failed: Task %q",
+			task.Name,
+		)
+	}
+	impersonated.teardownTo = f
+	return impersonated, nil
+}
@@ -0,0 +1,392 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestFixtureImpersonate(t *testing.T) {
+	var tests = map[string]struct {
+		kubeConfig *rest.Config
+		isErr      bool
+	}{
+		"nil kubeconfig": {
+			isErr: true,
+		},
+		"kubeconfig": {
+			kubeConfig: &rest.Config{Host: "https://10.0.0.1:6443"},
+		},
+		"impersonated kubeconfig": {
+			kubeConfig: &rest.Config{
+				Host: "https://10.0.0.1:6443",
+				Impersonate: rest.ImpersonationConfig{
+					UserName: "system:serviceaccount:ns:restricted",
+				},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			f := &Fixture{
+				BaseFixture: &BaseFixture{},
+				kubeConfig:  mock.kubeConfig,
+				tearDown:    true,
+			}
+			identity := ServiceAccountIdentity("ns", "tester")
+			got, err := f.Impersonate(identity)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if got.kubeConfig.Impersonate.UserName != "system:serviceaccount:ns:tester" {
+				t.Fatalf(
+					"Expected impersonated user got %q",
+					got.kubeConfig.Impersonate.UserName,
+				)
+			}
+			if len(got.kubeConfig.Impersonate.Groups) != 2 {
+				t.Fatalf(
+					"Expected 2 impersonated groups got %v",
+					got.kubeConfig.Impersonate.Groups,
+				)
+			}
+			if !got.tearDown {
+				t.Fatalf("Expected teardown to be retained")
+			}
+			if f.kubeConfig.Impersonate.UserName != "" {
+				t.Fatalf("Expected original kubeconfig to be unchanged")
+			}
+		})
+	}
+}
+
+func TestRunnerInitServiceAccount(t *testing.T) {
+	var tests = map[string]struct {
+		serviceAccountName string
+		isImpersonated     bool
+	}{
+		"no service account": {},
+		"service account": {
+			serviceAccountName: "tester",
+			isImpersonated:     true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						ServiceAccountName: mock.serviceAccountName,
+					},
+				},
+				fixture: &Fixture{
+					BaseFixture: &BaseFixture{},
+					kubeConfig:  &rest.Config{},
+				},
+			}
+			r.Recipe.SetNamespace("ns")
+			r.initServiceAccount()
+			if r.err != nil {
+				t.Fatalf("Expected no error got %+v", r.err)
+			}
+			user := r.getTasksFixture().kubeConfig.Impersonate.UserName
+			if mock.isImpersonated && user != "system:serviceaccount:ns:tester" {
+				t.Fatalf("Expected impersonated service account got %q", user)
+			}
+			if !mock.isImpersonated && user != "" {
+				t.Fatalf("Expected no impersonation got %q", user)
+			}
+		})
+	}
+}
+
+func TestTaskRunnerRunForbidden(t *testing.T) {
+	var tests = map[string]struct {
+		ignoreError   types.IgnoreErrorRule
+		expectedPhase types.TaskStatusPhase
+	}{
+		"forbidden fails the task": {
+			expectedPhase: types.TaskStatusFailed,
+		},
+		"forbidden as warning": {
+			ignoreError:   types.IgnoreErrorAsWarning,
+			expectedPhase: types.TaskStatusWarning,
+		},
+		"forbidden as passed": {
+			ignoreError:   types.IgnoreErrorForbiddenAsPassed,
+			expectedPhase: types.TaskStatusPassed,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			// forbidden should not be retried till this timeout
+			timeout := 30 * time.Second
+			tr := &TaskRunner{
+				BaseRunner: BaseRunner{
+					Fixture: &Fixture{BaseFixture: ForbiddenFixture},
+					Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+						WaitTimeout: &timeout,
+					}),
+					TaskIndex: 1,
+					TaskName:  "apply-config-map",
+				},
+				Task: types.Task{
+					Name:            "apply-config-map",
+					IgnoreErrorRule: mock.ignoreError,
+					Apply: &types.Apply{
+						State: &unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"kind":       "ConfigMap",
+								"apiVersion": "v1",
+								"metadata": map[string]interface{}{
+									"name": "cm",
+								},
+							},
+						},
+					},
+				},
+			}
+			start := time.Now()
+			got, err := tr.Run()
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if time.Since(start) > timeout/2 {
+				t.Fatalf("Expected forbidden not to be retried")
+			}
+			if got.Phase != mock.expectedPhase {
+				t.Fatalf("Expected phase %q got %q", mock.expectedPhase, got.Phase)
+			}
+			if !strings.Contains(got.Message+got.Warning, "forbidden") {
+				t.Fatalf("Expected forbidden reason got %s", got)
+			}
+		})
+	}
+}
+
+func TestTaskRunnerRunAllowedButExpectedForbidden(t *testing.T) {
+	tr := &TaskRunner{
+		BaseRunner: BaseRunner{
+			Fixture:   &Fixture{BaseFixture: NoopFixture},
+			Retry:     kubernetes.NewRetry(kubernetes.RetryConfig{RunOnce: true}),
+			TaskIndex: 1,
+			TaskName:  "create-config-map",
+		},
+		Task: types.Task{
+			Name:            "create-config-map",
+			IgnoreErrorRule: types.IgnoreErrorForbiddenAsPassed,
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": "cm",
+						},
+					},
+				},
+			},
+		},
+	}
+	got, err := tr.Run()
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if got.Phase != types.TaskStatusFailed {
+		t.Fatalf("Expected phase %q got %q", types.TaskStatusFailed, got.Phase)
+	}
+}
+
+func TestTaskIdentity(t *testing.T) {
+	var tests = map[string]struct {
+		impersonate types.Impersonate
+		expected    rest.ImpersonationConfig
+	}{
+		"user & groups": {
+			impersonate: types.Impersonate{
+				User:   "alice",
+				Groups: []string{"dev"},
+			},
+			expected: rest.ImpersonationConfig{
+				UserName: "alice",
+				Groups:   []string{"dev"},
+			},
+		},
+		"service account in recipe namespace": {
+			impersonate: types.Impersonate{
+				ServiceAccount: &types.ServiceAccountReference{Name: "tester"},
+			},
+			expected: rest.ImpersonationConfig{
+				UserName: "system:serviceaccount:ns:tester",
+				Groups: []string{
+					"system:serviceaccounts",
+					"system:serviceaccounts:ns",
+				},
+			},
+		},
+		"service account in another namespace with groups": {
+			impersonate: types.Impersonate{
+				Groups: []string{"dev"},
+				ServiceAccount: &types.ServiceAccountReference{
+					Name:      "tester",
+					Namespace: "ns-x",
+				},
+			},
+			expected: rest.ImpersonationConfig{
+				UserName: "system:serviceaccount:ns-x:tester",
+				Groups: []string{
+					"system:serviceaccounts",
+					"system:serviceaccounts:ns-x",
+					"dev",
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := TaskIdentity(mock.impersonate, "ns")
+			if diff := cmp.Diff(mock.expected, got); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRunnerBuildTaskFixture(t *testing.T) {
+	recipeFixture := &Fixture{
+		BaseFixture: &BaseFixture{},
+		kubeConfig:  &rest.Config{},
+		tearDown:    true,
+	}
+	r := &Runner{fixture: recipeFixture}
+	got, err := r.buildTaskFixture(types.Task{
+		Name:        "as-alice",
+		Impersonate: &types.Impersonate{User: "alice"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if got == recipeFixture {
+		t.Fatalf("Expected impersonated fixture got recipe fixture")
+	}
+	if got.kubeConfig.Impersonate.UserName != "alice" {
+		t.Fatalf("Expected user alice got %q", got.kubeConfig.Impersonate.UserName)
+	}
+	got.AddToTeardown(func() error { return nil })
+	if len(recipeFixture.teardownFuncs) != 1 || len(got.teardownFuncs) != 0 {
+		t.Fatalf("Expected teardown to be added to the recipe fixture")
+	}
+
+	got, err = r.buildTaskFixture(types.Task{Name: "as-recipe"})
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	if got != recipeFixture {
+		t.Fatalf("Expected recipe fixture for a task without impersonation")
+	}
+}
+
+func TestRunnerBuildTaskFixtureWithServiceAccount(t *testing.T) {
+	var tests = map[string]struct {
+		impersonate types.Impersonate
+	}{
+		"escalate to cluster admin via groups": {
+			impersonate: types.Impersonate{
+				User:   "x",
+				Groups: []string{"system:masters"},
+			},
+		},
+		"escalate via another service account": {
+			impersonate: types.Impersonate{
+				ServiceAccount: &types.ServiceAccountReference{
+					Name:      "admin",
+					Namespace: "kube-system",
+				},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						ServiceAccountName: "restricted",
+					},
+				},
+				fixture: &Fixture{
+					BaseFixture: &BaseFixture{},
+					kubeConfig:  &rest.Config{},
+				},
+			}
+			r.Recipe.SetNamespace("ns")
+			r.initServiceAccount()
+			if r.err != nil {
+				t.Fatalf("Expected no error got %+v", r.err)
+			}
+			got, err := r.buildTaskFixture(types.Task{
+				Name:        "escalate",
+				Impersonate: &mock.impersonate,
+			})
+			if err == nil {
+				t.Fatalf(
+					"Expected error got fixture impersonating %+v",
+					got.kubeConfig.Impersonate,
+				)
+			}
+			// fixture of the service account can't impersonate
+			// either
+			got, err = r.getTasksFixture().Impersonate(
+				TaskIdentity(mock.impersonate, "ns"),
+			)
+			if err == nil {
+				t.Fatalf(
+					"Expected error got fixture impersonating %+v",
+					got.kubeConfig.Impersonate,
+				)
+			}
+		})
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/labels"
 	types "mayadata.io/d-operators/types/recipe"
 	"openebs.io/metac/dynamic/clientset"
@@ -77,45 +78,77 @@ func (l *Labeling) verboseMessage() string {
 	)
 }
 
+// mergeStringMaps returns a new map that holds the current pairs
+// updated with the pairs to be applied & without the keys to be
+// removed
+func mergeStringMaps(
+	current map[string]string,
+	apply map[string]string,
+	remove []string,
+) map[string]string {
+	var merged = map[string]string{}
+	for key, val := range current {
+		merged[key] = val
+	}
+	for key, val := range apply {
+		merged[key] = val
+	}
+	for _, key := range remove {
+		delete(merged, key)
+	}
+	return merged
+}
+
+// isStringMapSubset returns true if all the pairs of the given
+// subset are found in the given superset
+func isStringMapSubset(superset, subset map[string]string) bool {
+	for key, val := range subset {
+		got, found := superset[key]
+		if !found || got != val {
+			return false
+		}
+	}
+	return true
+}
+
+// stringMapKeys returns the keys of the given map
+func stringMapKeys(given map[string]string) []string {
+	var keys []string
+	for key := range given {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
 func (l *Labeling) unset(
 	client *clientset.ResourceClient,
 	obj *unstructured.Unstructured,
 ) error {
-	var currentLbls = obj.GetLabels()
-	if len(currentLbls) == 0 ||
-		len(currentLbls) < len(l.Label.ApplyLabels) {
-		// given object is not eligible to be
-		// unset, since all the desired labels
-		// are not present
+	if len(l.Label.ApplyLabels) == 0 && len(l.Label.ApplyAnnotations) == 0 {
+		// nothing was applied earlier & hence nothing to unset
 		return nil
 	}
-	for key, val := range l.Label.ApplyLabels {
-		if currentLbls[key] != val {
-			// given object is not eligible to be
-			// unset, since it does not match the desired labels
-			return nil
-		}
-	}
-	var newLbls = map[string]string{}
-	var isUnset bool
-	for key, val := range currentLbls {
-		isUnset = false
-		for applyKey := range l.Label.ApplyLabels {
-			if key == applyKey {
-				// do not add this key & value
-				// In other words unset this label
-				isUnset = true
-				break
-			}
-		}
-		if !isUnset {
-			// add existing key value pair since
-			// this is not to be unset
-			newLbls[key] = val
-		}
+	if !isStringMapSubset(obj.GetLabels(), l.Label.ApplyLabels) ||
+		!isStringMapSubset(obj.GetAnnotations(), l.Label.ApplyAnnotations) {
+		// given object is not eligible to be unset, since it
+		// does not match the desired labels & annotations
+		return nil
 	}
-	// update the resource by removing desired labels
-	obj.SetLabels(newLbls)
+	// update the resource by removing desired labels & annotations
+	obj.SetLabels(
+		mergeStringMaps(
+			obj.GetLabels(),
+			nil,
+			stringMapKeys(l.Label.ApplyLabels),
+		),
+	)
+	obj.SetAnnotations(
+		mergeStringMaps(
+			obj.GetAnnotations(),
+			nil,
+			stringMapKeys(l.Label.ApplyAnnotations),
+		),
+	)
 	// update the object against the cluster
 	_, err := client.
 		Namespace(obj.GetNamespace()).
@@ -133,17 +166,22 @@ func (l *Labeling) label(
 	client *clientset.ResourceClient,
 	obj *unstructured.Unstructured,
 ) error {
-	var newLbls = map[string]string{}
-	// fill with existing labels
-	for key, val := range obj.GetLabels() {
-		newLbls[key] = val
-	}
-	// add / update desired labels to existing labels
-	for nkey, nval := range l.Label.ApplyLabels {
-		newLbls[nkey] = nval
-	}
-	// update the resource with new labels
-	obj.SetLabels(newLbls)
+	// add / update desired labels & remove the undesired ones
+	obj.SetLabels(
+		mergeStringMaps(
+			obj.GetLabels(),
+			l.Label.ApplyLabels,
+			l.Label.RemoveLabels,
+		),
+	)
+	// add / update desired annotations & remove the undesired ones
+	obj.SetAnnotations(
+		mergeStringMaps(
+			obj.GetAnnotations(),
+			l.Label.ApplyAnnotations,
+			l.Label.RemoveAnnotations,
+		),
+	)
 	// update the object against the cluster
 	_, err := client.
 		Namespace(obj.GetNamespace()).
@@ -182,6 +220,78 @@ func (l *Labeling) labelOrUnset(
 	return nil
 }
 
+// buildListOptions returns the list options that select the
+// resources to be labeled
+func (l *Labeling) buildListOptions() (metav1.ListOptions, error) {
+	var selector = labels.SelectorFromSet(l.Label.State.GetLabels())
+	if l.Label.LabelSelector != nil {
+		extra, err := metav1.LabelSelectorAsSelector(l.Label.LabelSelector)
+		if err != nil {
+			return metav1.ListOptions{}, errors.Wrapf(
+				err,
+				"Invalid label selector",
+			)
+		}
+		reqs, _ := extra.Requirements()
+		selector = selector.Add(reqs...)
+	}
+	if l.Label.FieldSelector != "" {
+		_, err := fields.ParseSelector(l.Label.FieldSelector)
+		if err != nil {
+			return metav1.ListOptions{}, errors.Wrapf(
+				err,
+				"Invalid field selector",
+			)
+		}
+	}
+	return metav1.ListOptions{
+		LabelSelector: selector.String(),
+		FieldSelector: l.Label.FieldSelector,
+	}, nil
+}
+
+// listNamespaces returns the namespaces to select the resources
+// from
+//
+// NOTE:
+//	An empty namespace implies all namespaces or cluster scoped
+// resources
+func (l *Labeling) listNamespaces() ([]string, error) {
+	if l.Label.NamespaceSelector == nil {
+		return []string{l.Label.State.GetNamespace()}, nil
+	}
+	selector, err := metav1.LabelSelectorAsSelector(
+		l.Label.NamespaceSelector,
+	)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Invalid namespace selector",
+		)
+	}
+	client, err := l.GetClientForAPIVersionAndKind("v1", "Namespace")
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Failed to get namespace client",
+		)
+	}
+	items, err := client.List(metav1.ListOptions{
+		LabelSelector: selector.String(),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Failed to list namespaces",
+		)
+	}
+	var namespaces []string
+	for _, ns := range items.Items {
+		namespaces = append(namespaces, ns.GetName())
+	}
+	return namespaces, nil
+}
+
 func (l *Labeling) labelAll() (*types.LabelResult, error) {
 	var message = fmt.Sprintf(
 		"Label resource %s %s: GVK %s",
@@ -189,7 +299,11 @@ func (l *Labeling) labelAll() (*types.LabelResult, error) {
 		l.Label.State.GetName(),
 		l.Label.State.GroupVersionKind(),
 	)
-	err := l.Retry.Waitf(
+	listOptions, err := l.buildListOptions()
+	if err != nil {
+		return nil, err
+	}
+	err = l.Retry.Waitf(
 		func() (bool, error) {
 			// get appropriate dynamic client
 			client, err := l.GetClientForAPIVersionAndKind(
@@ -202,25 +316,32 @@ func (l *Labeling) labelAll() (*types.LabelResult, error) {
 					"Failed to get resource client",
 				)
 			}
-			// list all resources
-			items, err := client.
-				Namespace(l.Label.State.GetNamespace()).
-				List(metav1.ListOptions{
-					LabelSelector: labels.Set(
-						l.Label.State.GetLabels(),
-					).String(),
-				})
+			namespaces, err := l.listNamespaces()
 			if err != nil {
-				return false, errors.Wrapf(
-					err,
-					"Failed to list resources",
-				)
+				return false, err
 			}
-			l.totalFoundCount = len(items.Items)
-			for _, obj := range items.Items {
-				err := l.labelOrUnset(client, &obj)
+			// reset the counts since this might be a retry
+			l.totalFoundCount = 0
+			l.labeledCount = 0
+			l.unLabeledCount = 0
+			for _, namespace := range namespaces {
+				// list all resources
+				items, err := client.
+					Namespace(namespace).
+					List(listOptions)
 				if err != nil {
-					return false, err
+					return false, errors.Wrapf(
+						err,
+						"Failed to list resources",
+					)
+				}
+				l.totalFoundCount += len(items.Items)
+				for _, obj := range items.Items {
+					obj := obj
+					err := l.labelOrUnset(client, &obj)
+					if err != nil {
+						return false, err
+					}
 				}
 			}
 			return true, nil
@@ -237,12 +358,19 @@ func (l *Labeling) labelAll() (*types.LabelResult, error) {
 	}, nil
 }
 
-// Run applyies the desired labels or unsets them
+// Run applies the desired labels & annotations or unsets them
 // against the resource(s)
 func (l *Labeling) Run() (*types.LabelResult, error) {
-	if len(l.Label.ApplyLabels) == 0 {
+	if !l.Label.HasAnyOperation() {
+		return nil, errors.Errorf(
+			"Invalid label operation: Missing ApplyLabels, ApplyAnnotations, RemoveLabels or RemoveAnnotations",
+		)
+	}
+	if l.Label.NamespaceSelector != nil &&
+		l.Label.State.GetNamespace() != "" {
 		return nil, errors.Errorf(
-			"Invalid label operation: Missing ApplyLabels",
+			"Invalid label operation: NamespaceSelector can't be used with state namespace %q",
+			l.Label.State.GetNamespace(),
 		)
 	}
 	return l.labelAll()
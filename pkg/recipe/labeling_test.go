@@ -0,0 +1,358 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+	"openebs.io/metac/dynamic/clientset"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+)
+
+func TestMergeStringMaps(t *testing.T) {
+	var tests = map[string]struct {
+		current  map[string]string
+		apply    map[string]string
+		remove   []string
+		expected map[string]string
+	}{
+		"all nil": {
+			expected: map[string]string{},
+		},
+		"apply to nil current": {
+			apply:    map[string]string{"app": "dope"},
+			expected: map[string]string{"app": "dope"},
+		},
+		"apply & remove": {
+			current:  map[string]string{"app": "old", "stale": "true"},
+			apply:    map[string]string{"app": "dope"},
+			remove:   []string{"stale"},
+			expected: map[string]string{"app": "dope"},
+		},
+		"remove non existing key": {
+			current:  map[string]string{"app": "dope"},
+			remove:   []string{"junk"},
+			expected: map[string]string{"app": "dope"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := mergeStringMaps(mock.current, mock.apply, mock.remove)
+			if diff := cmp.Diff(mock.expected, got); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLabelingBuildListOptions(t *testing.T) {
+	var tests = map[string]struct {
+		label         *types.Label
+		labelSelector string
+		isErr         bool
+	}{
+		"state labels only": {
+			label: &types.Label{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{
+								"app": "dope",
+							},
+						},
+					},
+				},
+			},
+			labelSelector: "app=dope",
+		},
+		"state labels with match expressions": {
+			label: &types.Label{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"labels": map[string]interface{}{
+								"app": "dope",
+							},
+						},
+					},
+				},
+				LabelSelector: &metav1.LabelSelector{
+					MatchExpressions: []metav1.LabelSelectorRequirement{
+						{
+							Key:      "tier",
+							Operator: metav1.LabelSelectorOpExists,
+						},
+					},
+				},
+			},
+			labelSelector: "app=dope,tier",
+		},
+		"invalid field selector": {
+			label: &types.Label{
+				State:         &unstructured.Unstructured{},
+				FieldSelector: "metadata.name",
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			l := &Labeling{
+				Label: mock.label,
+			}
+			got, err := l.buildListOptions()
+			if mock.isErr && err == nil {
+				t.Fatal("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if mock.isErr {
+				return
+			}
+			if got.LabelSelector != mock.labelSelector {
+				t.Fatalf(
+					"Expected label selector %q got %q",
+					mock.labelSelector,
+					got.LabelSelector,
+				)
+			}
+		})
+	}
+}
+
+func TestLabelingRun(t *testing.T) {
+	newConfigMap := func(ns, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "ConfigMap",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name":      name,
+					"namespace": ns,
+					"labels": map[string]interface{}{
+						"app": "dope",
+					},
+					"annotations": map[string]interface{}{
+						"stale": "true",
+					},
+				},
+			},
+		}
+	}
+	di := dynamicfake.NewSimpleDynamicClient(
+		runtime.NewScheme(),
+		newConfigMap("", "cm-one"),
+		newConfigMap("", "cm-two"),
+	)
+	fixture := &Fixture{
+		BaseFixture: &BaseFixture{
+			getClientForAPIVersionAndKindFn: func(
+				apiversion string,
+				kind string,
+			) (*clientset.ResourceClient, error) {
+				return &clientset.ResourceClient{
+					ResourceInterface: di.Resource(
+						schema.GroupVersionResource{
+							Version:  "v1",
+							Resource: "configmaps",
+						},
+					),
+					APIResource: &dynamicdiscovery.APIResource{},
+				}, nil
+			},
+		},
+	}
+	var tests = map[string]struct {
+		label                *types.Label
+		expectedLabeledCount int
+		isErr                bool
+	}{
+		"missing operation": {
+			label: &types.Label{
+				State: newConfigMap("", ""),
+			},
+			isErr: true,
+		},
+		"namespace selector with state namespace": {
+			label: &types.Label{
+				State:             newConfigMap("ns-one", ""),
+				NamespaceSelector: &metav1.LabelSelector{},
+				ApplyLabels:       map[string]string{"new": "true"},
+			},
+			isErr: true,
+		},
+		"apply annotations": {
+			label: &types.Label{
+				State:            newConfigMap("", ""),
+				ApplyAnnotations: map[string]string{"new": "true"},
+			},
+			expectedLabeledCount: 2,
+		},
+		"remove annotations with label selector": {
+			label: &types.Label{
+				State: newConfigMap("", ""),
+				LabelSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"app": "junk"},
+				},
+				RemoveAnnotations: []string{"stale"},
+			},
+			expectedLabeledCount: 0,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			l := NewLabeler(LabelingConfig{
+				BaseRunner: BaseRunner{
+					Fixture: fixture,
+					Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+						WaitTimeout: &timeout,
+					}),
+				},
+				Label: mock.label,
+			})
+			_, err := l.Run()
+			if mock.isErr && err == nil {
+				t.Fatal("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if mock.isErr {
+				return
+			}
+			if l.labeledCount != mock.expectedLabeledCount {
+				t.Fatalf(
+					"Expected labeled count %d got %d",
+					mock.expectedLabeledCount,
+					l.labeledCount,
+				)
+			}
+		})
+	}
+}
+
+func TestLabelingListNamespaces(t *testing.T) {
+	di := dynamicfake.NewSimpleDynamicClient(
+		runtime.NewScheme(),
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "Namespace",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name": "ns-one",
+					"labels": map[string]interface{}{
+						"env": "test",
+					},
+				},
+			},
+		},
+		&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"kind":       "Namespace",
+				"apiVersion": "v1",
+				"metadata": map[string]interface{}{
+					"name": "ns-two",
+					"labels": map[string]interface{}{
+						"env": "prod",
+					},
+				},
+			},
+		},
+	)
+	fixture := &Fixture{
+		BaseFixture: &BaseFixture{
+			getClientForAPIVersionAndKindFn: func(
+				apiversion string,
+				kind string,
+			) (*clientset.ResourceClient, error) {
+				return &clientset.ResourceClient{
+					ResourceInterface: di.Resource(
+						schema.GroupVersionResource{
+							Version:  "v1",
+							Resource: "namespaces",
+						},
+					),
+					APIResource: &dynamicdiscovery.APIResource{},
+				}, nil
+			},
+		},
+	}
+	var tests = map[string]struct {
+		label    *types.Label
+		expected []string
+	}{
+		"without namespace selector": {
+			label: &types.Label{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"metadata": map[string]interface{}{
+							"namespace": "my-ns",
+						},
+					},
+				},
+			},
+			expected: []string{"my-ns"},
+		},
+		"with namespace selector": {
+			label: &types.Label{
+				State: &unstructured.Unstructured{},
+				NamespaceSelector: &metav1.LabelSelector{
+					MatchLabels: map[string]string{"env": "test"},
+				},
+			},
+			expected: []string{"ns-one"},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			l := &Labeling{
+				BaseRunner: BaseRunner{
+					Fixture: fixture,
+				},
+				Label: mock.label,
+			}
+			got, err := l.listNamespaces()
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if diff := cmp.Diff(mock.expected, got); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -18,15 +18,40 @@ package recipe
 
 import (
 	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	"k8s.io/klog/v2"
 	"mayadata.io/d-operators/common/pointer"
 	types "mayadata.io/d-operators/types/recipe"
 	"openebs.io/metac/dynamic/clientset"
 )
 
+// defaultLockLeaseDuration is the duration for which a lock
+// remains valid without being renewed
+const defaultLockLeaseDuration = 60 * time.Second
+
+// LockHolderIdentity identifies this operator instance as the
+// holder of Recipe locks
+var LockHolderIdentity = newLockHolderIdentity()
+
+func newLockHolderIdentity() string {
+	// hostname is the pod name when run inside Kubernetes
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	// suffix makes the identity unique across restarts
+	return hostname + "_" + string(uuid.NewUUID())
+}
+
 // LockRunner executes a lock task
 type LockRunner struct {
 	BaseRunner
@@ -35,15 +60,30 @@ type LockRunner struct {
 
 	// Number of tasks that are scoped in this lock
 	ProtectedTaskCount int
+
+	// HolderIdentity identifies the holder of this lock
+	HolderIdentity string
+
+	// LeaseDuration is the duration for which this lock remains
+	// valid without being renewed
+	LeaseDuration time.Duration
+
+	// Revision of the Recipe's spec that is executed with this
+	// lock. This is recorded in the lock if set.
+	Revision *types.SpecRevision
+
+	// Status has the details of the lock that was either
+	// acquired or observed by this runner
+	Status *types.LockStatus
+
+	// closing this channel stops lease renewals
+	stopRenewal chan struct{}
+	// this channel gets closed once lease renewals are stopped
+	renewalDone chan struct{}
+	renewalMu   sync.Mutex
 }
 
-func (r *LockRunner) delete() (types.TaskResult, error) {
-	var message = fmt.Sprintf(
-		"Delete: Lock %s %s: GVK %s",
-		r.Task.Apply.State.GetNamespace(),
-		r.Task.Apply.State.GetName(),
-		r.Task.Apply.State.GroupVersionKind(),
-	)
+func (r *LockRunner) getClient(message string) (*clientset.ResourceClient, error) {
 	var client *clientset.ResourceClient
 	var err error
 	err = r.Retry.Waitf(
@@ -59,6 +99,19 @@ func (r *LockRunner) delete() (types.TaskResult, error) {
 		},
 		message,
 	)
+	return client, err
+}
+
+func (r *LockRunner) delete() (types.TaskResult, error) {
+	// lease should not be renewed once the lock is deleted
+	r.stopLeaseRenewal()
+	var message = fmt.Sprintf(
+		"Delete: Lock %s %s: GVK %s",
+		r.Task.Apply.State.GetNamespace(),
+		r.Task.Apply.State.GetName(),
+		r.Task.Apply.State.GroupVersionKind(),
+	)
+	client, err := r.getClient(message)
 	if err != nil {
 		return types.TaskResult{}, err
 	}
@@ -85,6 +138,13 @@ func (r *LockRunner) delete() (types.TaskResult, error) {
 	}, nil
 }
 
+func (r *LockRunner) leaseDuration() time.Duration {
+	if r.LeaseDuration <= 0 {
+		return defaultLockLeaseDuration
+	}
+	return r.LeaseDuration
+}
+
 func (r *LockRunner) create() (types.TaskResult, error) {
 	var message = fmt.Sprintf(
 		"Create: Lock %s %s: GVK %s",
@@ -92,37 +152,48 @@ func (r *LockRunner) create() (types.TaskResult, error) {
 		r.Task.Apply.State.GetName(),
 		r.Task.Apply.State.GroupVersionKind(),
 	)
-	var client *clientset.ResourceClient
-	var err error
-	err = r.Retry.Waitf(
-		func() (bool, error) {
-			client, err = r.GetClientForAPIVersionAndKind(
-				r.Task.Apply.State.GetAPIVersion(),
-				r.Task.Apply.State.GetKind(),
-			)
-			if err != nil {
-				return r.IsFailFastOnDiscoveryError(), err
-			}
-			return true, nil
-		},
-		message,
-	)
+	client, err := r.getClient(message)
+	if err != nil {
+		return types.TaskResult{}, err
+	}
+	// lock is created with the lease details
+	var now = time.Now()
+	var leaseSeconds = int64(r.leaseDuration().Seconds())
+	lock := r.Task.Apply.State.DeepCopy()
+	data := map[string]string{
+		types.LockDataKeyHolderIdentity:       r.HolderIdentity,
+		types.LockDataKeyAcquireTime:          now.UTC().Format(time.RFC3339),
+		types.LockDataKeyRenewTime:            now.UTC().Format(time.RFC3339),
+		types.LockDataKeyLeaseDurationSeconds: strconv.FormatInt(leaseSeconds, 10),
+	}
+	if r.Revision != nil {
+		data[types.LockDataKeySpecHash] = r.Revision.SpecHash
+		data[types.LockDataKeyGeneration] = strconv.FormatInt(r.Revision.Generation, 10)
+	}
+	err = unstructured.SetNestedStringMap(lock.Object, data, "data")
 	if err != nil {
 		return types.TaskResult{}, err
 	}
 	_, err = client.
 		Namespace(r.Task.Apply.State.GetNamespace()).
 		Create(
-			r.Task.Apply.State,
+			lock,
 			metav1.CreateOptions{},
 		)
 	if err != nil {
 		return types.TaskResult{}, err
 	}
+	r.Status = &types.LockStatus{
+		HolderIdentity:       r.HolderIdentity,
+		AcquireTime:          &metav1.Time{Time: now},
+		LeaseDurationSeconds: pointer.Int64(leaseSeconds),
+		SpecHash:             data[types.LockDataKeySpecHash],
+	}
 	klog.V(3).Infof(
-		"Lock created successfully: Name %q %q",
+		"Lock created successfully: Name %q %q: Holder %q",
 		r.Task.Apply.State.GetNamespace(),
 		r.Task.Apply.State.GetName(),
+		r.HolderIdentity,
 	)
 	return types.TaskResult{
 		Step:     0, // 0 is reserved for lock
@@ -132,6 +203,114 @@ func (r *LockRunner) create() (types.TaskResult, error) {
 	}, nil
 }
 
+// updateLease updates the lease details of the lock held by
+// this runner
+//
+// NOTE:
+//	Lease is renewed if renew is true. Otherwise the lease is
+// removed to make the lock last forever.
+func (r *LockRunner) updateLease(renew bool) error {
+	var message = fmt.Sprintf(
+		"Update lease: Lock %s %s: GVK %s",
+		r.Task.Apply.State.GetNamespace(),
+		r.Task.Apply.State.GetName(),
+		r.Task.Apply.State.GroupVersionKind(),
+	)
+	client, err := r.getClient(message)
+	if err != nil {
+		return err
+	}
+	got, err := client.
+		Namespace(r.Task.Apply.State.GetNamespace()).
+		Get(
+			r.Task.Apply.State.GetName(),
+			metav1.GetOptions{},
+		)
+	if err != nil {
+		return err
+	}
+	data, _, err := unstructured.NestedStringMap(got.Object, "data")
+	if err != nil {
+		return err
+	}
+	if data[types.LockDataKeyHolderIdentity] != r.HolderIdentity {
+		// lock was reclaimed by some other holder
+		return errors.Errorf(
+			"%s: Lock is held by %q",
+			message,
+			data[types.LockDataKeyHolderIdentity],
+		)
+	}
+	if renew {
+		data[types.LockDataKeyRenewTime] = time.Now().UTC().Format(time.RFC3339)
+	} else {
+		delete(data, types.LockDataKeyRenewTime)
+		delete(data, types.LockDataKeyLeaseDurationSeconds)
+	}
+	err = unstructured.SetNestedStringMap(got.Object, data, "data")
+	if err != nil {
+		return err
+	}
+	_, err = client.
+		Namespace(r.Task.Apply.State.GetNamespace()).
+		Update(
+			got,
+			metav1.UpdateOptions{},
+		)
+	return err
+}
+
+// startLeaseRenewal renews the lease periodically till it
+// is stopped
+func (r *LockRunner) startLeaseRenewal() {
+	r.renewalMu.Lock()
+	defer r.renewalMu.Unlock()
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	r.stopRenewal = stop
+	r.renewalDone = done
+	go func() {
+		defer close(done)
+		// lease is renewed well before it expires
+		ticker := time.NewTicker(r.leaseDuration() / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				err := r.updateLease(true)
+				if err != nil {
+					// swallow the error by logging since next
+					// renewal might succeed
+					klog.Errorf(
+						"Lease renewal failed: Lock %q %q: %s",
+						r.Task.Apply.State.GetNamespace(),
+						r.Task.Apply.State.GetName(),
+						err.Error(),
+					)
+				}
+			}
+		}
+	}()
+}
+
+// stopLeaseRenewal stops renewing the lease if it was started
+//
+// NOTE:
+//	This waits for any ongoing renewal to complete
+func (r *LockRunner) stopLeaseRenewal() {
+	r.renewalMu.Lock()
+	defer r.renewalMu.Unlock()
+	if r.stopRenewal == nil {
+		return
+	}
+	close(r.stopRenewal)
+	<-r.renewalDone
+	r.stopRenewal = nil
+	r.renewalDone = nil
+}
+
 // Lock acquires the lock and returns unlock
 func (r *LockRunner) Lock() (
 	types.TaskResult,
@@ -142,12 +321,19 @@ func (r *LockRunner) Lock() (
 	if err != nil {
 		return types.TaskResult{}, nil, err
 	}
+	// lease is renewed as long as the lock is held
+	r.startLeaseRenewal()
 	// build the unlock logic
 	var unlock func() (types.TaskResult, error)
 	if r.LockForever {
 		unlock = func() (types.TaskResult, error) {
-			// this is a noop if this lock is meant
-			// to be present forever
+			// this lock is meant to be present forever
+			// & hence its lease is removed
+			r.stopLeaseRenewal()
+			err := r.updateLease(false)
+			if err != nil {
+				return types.TaskResult{}, err
+			}
 			return types.TaskResult{
 				// last step is always the unlock
 				Step:     r.ProtectedTaskCount + 1,
@@ -169,7 +355,90 @@ func (r *LockRunner) MustUnlock() (types.TaskResult, error) {
 	return r.delete()
 }
 
+// NewLockStatus returns the lock status from the provided lock
+func NewLockStatus(lock *unstructured.Unstructured) *types.LockStatus {
+	data, _, _ := unstructured.NestedStringMap(lock.Object, "data")
+	status := &types.LockStatus{
+		HolderIdentity: data[types.LockDataKeyHolderIdentity],
+		SpecHash:       data[types.LockDataKeySpecHash],
+	}
+	acquired, err := time.Parse(time.RFC3339, data[types.LockDataKeyAcquireTime])
+	if err == nil {
+		status.AcquireTime = &metav1.Time{Time: acquired}
+	}
+	lease, err := strconv.ParseInt(data[types.LockDataKeyLeaseDurationSeconds], 10, 64)
+	if err == nil {
+		status.LeaseDurationSeconds = pointer.Int64(lease)
+	}
+	return status
+}
+
+// isLeaseExpired returns true if the lease of the provided lock
+// has expired
+//
+// NOTE:
+//	A lock without any lease never expires
+func isLeaseExpired(lock *unstructured.Unstructured, now time.Time) bool {
+	data, _, _ := unstructured.NestedStringMap(lock.Object, "data")
+	leaseStr, found := data[types.LockDataKeyLeaseDurationSeconds]
+	if !found {
+		return false
+	}
+	lease, err := strconv.ParseInt(leaseStr, 10, 64)
+	if err != nil {
+		// an invalid lease can never be renewed
+		return true
+	}
+	renewStr := data[types.LockDataKeyRenewTime]
+	if renewStr == "" {
+		renewStr = data[types.LockDataKeyAcquireTime]
+	}
+	renewed, err := time.Parse(time.RFC3339, renewStr)
+	if err != nil {
+		// an invalid lease can never be renewed
+		return true
+	}
+	return now.After(renewed.Add(time.Duration(lease) * time.Second))
+}
+
+// reclaim deletes the provided stale lock
+func (r *LockRunner) reclaim(lock *unstructured.Unstructured) error {
+	client, err := r.GetClientForAPIVersionAndKind(
+		r.Task.Apply.State.GetAPIVersion(),
+		r.Task.Apply.State.GetKind(),
+	)
+	if err != nil {
+		return err
+	}
+	uid := lock.GetUID()
+	err = client.
+		Namespace(r.Task.Apply.State.GetNamespace()).
+		Delete(
+			r.Task.Apply.State.GetName(),
+			&metav1.DeleteOptions{
+				// delete only the lock that was found to be stale
+				Preconditions: &metav1.Preconditions{
+					UID: &uid,
+				},
+			},
+		)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	klog.Infof(
+		"Reclaimed stale lock %q %q: Holder %q",
+		r.Task.Apply.State.GetNamespace(),
+		r.Task.Apply.State.GetName(),
+		r.Status.HolderIdentity,
+	)
+	return nil
+}
+
 // IsLocked returns true if lock was taken previously
+//
+// NOTE:
+//	A lock whose lease has expired is reclaimed & is not
+// considered to be locked
 func (r *LockRunner) IsLocked() (bool, error) {
 	client, err := r.GetClientForAPIVersionAndKind(
 		r.Task.Apply.State.GetAPIVersion(),
@@ -189,6 +458,16 @@ func (r *LockRunner) IsLocked() (bool, error) {
 			return false, nil
 		}
 	}
+	if got != nil {
+		r.Status = NewLockStatus(got)
+		if isLeaseExpired(got, time.Now()) {
+			err = r.reclaim(got)
+			if err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+	}
 	klog.V(3).Infof(
 		"Lock %q %q: Exists=%t",
 		r.Task.Apply.State.GetNamespace(),
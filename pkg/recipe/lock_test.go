@@ -0,0 +1,277 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+	"openebs.io/metac/dynamic/clientset"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+)
+
+func newLock(data map[string]interface{}) *unstructured.Unstructured {
+	lock := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":       "ConfigMap",
+			"apiVersion": "v1",
+			"metadata": map[string]interface{}{
+				"name": "recipe-lock",
+			},
+		},
+	}
+	if data != nil {
+		lock.Object["data"] = data
+	}
+	return lock
+}
+
+func newLockRunner(objects ...runtime.Object) (*LockRunner, *clientset.ResourceClient) {
+	di := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme(), objects...)
+	client := &clientset.ResourceClient{
+		ResourceInterface: di.Resource(
+			schema.GroupVersionResource{
+				Version:  "v1",
+				Resource: "configmaps",
+			},
+		),
+		APIResource: &dynamicdiscovery.APIResource{},
+	}
+	timeout := 1 * time.Second // unit test don't need to retry
+	return &LockRunner{
+		BaseRunner: BaseRunner{
+			Fixture: &Fixture{
+				BaseFixture: &BaseFixture{
+					getClientForAPIVersionAndKindFn: func(
+						apiversion string,
+						kind string,
+					) (*clientset.ResourceClient, error) {
+						return client, nil
+					},
+				},
+			},
+			Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+				WaitTimeout: &timeout,
+			}),
+		},
+		Task: types.Task{
+			Apply: &types.Apply{
+				State: newLock(nil),
+			},
+		},
+		HolderIdentity: "pod-1_uid",
+		LeaseDuration:  time.Minute,
+	}, client
+}
+
+func TestIsLeaseExpired(t *testing.T) {
+	now := time.Date(2020, 1, 1, 10, 0, 0, 0, time.UTC)
+	var tests = map[string]struct {
+		data      map[string]interface{}
+		isExpired bool
+	}{
+		"lock without data": {
+			isExpired: false,
+		},
+		"lock without lease": {
+			data: map[string]interface{}{
+				types.LockDataKeyHolderIdentity: "pod-1",
+				types.LockDataKeyAcquireTime:    "2020-01-01T09:00:00Z",
+			},
+			isExpired: false,
+		},
+		"lease renewed recently": {
+			data: map[string]interface{}{
+				types.LockDataKeyAcquireTime:          "2020-01-01T09:00:00Z",
+				types.LockDataKeyRenewTime:            "2020-01-01T09:59:30Z",
+				types.LockDataKeyLeaseDurationSeconds: "60",
+			},
+			isExpired: false,
+		},
+		"lease not renewed": {
+			data: map[string]interface{}{
+				types.LockDataKeyAcquireTime:          "2020-01-01T09:00:00Z",
+				types.LockDataKeyRenewTime:            "2020-01-01T09:58:00Z",
+				types.LockDataKeyLeaseDurationSeconds: "60",
+			},
+			isExpired: true,
+		},
+		"lease without renew time": {
+			data: map[string]interface{}{
+				types.LockDataKeyAcquireTime:          "2020-01-01T09:00:00Z",
+				types.LockDataKeyLeaseDurationSeconds: "60",
+			},
+			isExpired: true,
+		},
+		"invalid lease duration": {
+			data: map[string]interface{}{
+				types.LockDataKeyRenewTime:            "2020-01-01T09:59:30Z",
+				types.LockDataKeyLeaseDurationSeconds: "junk",
+			},
+			isExpired: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got := isLeaseExpired(newLock(mock.data), now)
+			if got != mock.isExpired {
+				t.Fatalf("Expected expired %t got %t", mock.isExpired, got)
+			}
+		})
+	}
+}
+
+func TestLockRunnerIsLocked(t *testing.T) {
+	var tests = map[string]struct {
+		lock           *unstructured.Unstructured
+		isLocked       bool
+		expectedHolder string
+		isReclaimed    bool
+	}{
+		"no lock": {
+			isLocked: false,
+		},
+		"lock without lease": {
+			lock: newLock(map[string]interface{}{
+				types.LockDataKeyHolderIdentity: "pod-0",
+			}),
+			isLocked:       true,
+			expectedHolder: "pod-0",
+		},
+		"lock with valid lease": {
+			lock: newLock(map[string]interface{}{
+				types.LockDataKeyHolderIdentity:       "pod-0",
+				types.LockDataKeyRenewTime:            time.Now().UTC().Format(time.RFC3339),
+				types.LockDataKeyLeaseDurationSeconds: "60",
+			}),
+			isLocked:       true,
+			expectedHolder: "pod-0",
+		},
+		"lock with expired lease": {
+			lock: newLock(map[string]interface{}{
+				types.LockDataKeyHolderIdentity:       "pod-0",
+				types.LockDataKeyRenewTime:            "2020-01-01T09:00:00Z",
+				types.LockDataKeyLeaseDurationSeconds: "60",
+			}),
+			isLocked:       false,
+			expectedHolder: "pod-0",
+			isReclaimed:    true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var objects []runtime.Object
+			if mock.lock != nil {
+				objects = append(objects, mock.lock)
+			}
+			r, client := newLockRunner(objects...)
+			got, err := r.IsLocked()
+			if err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+			if got != mock.isLocked {
+				t.Fatalf("Expected locked %t got %t", mock.isLocked, got)
+			}
+			if mock.lock == nil {
+				return
+			}
+			if r.Status == nil || r.Status.HolderIdentity != mock.expectedHolder {
+				t.Fatalf(
+					"Expected holder %q got %+v",
+					mock.expectedHolder,
+					r.Status,
+				)
+			}
+			_, err = client.Get("recipe-lock", metav1.GetOptions{})
+			if mock.isReclaimed && err == nil {
+				t.Fatalf("Expected stale lock to be deleted")
+			}
+			if !mock.isReclaimed && err != nil {
+				t.Fatalf("Expected lock to exist got [%+v]", err)
+			}
+		})
+	}
+}
+
+func TestLockRunnerLockUnlock(t *testing.T) {
+	var tests = map[string]struct {
+		isLockForever bool
+	}{
+		"lock & unlock": {
+			isLockForever: false,
+		},
+		"lock forever": {
+			isLockForever: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r, client := newLockRunner()
+			r.LockForever = mock.isLockForever
+			_, unlock, err := r.Lock()
+			if err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+			if r.Status == nil ||
+				r.Status.HolderIdentity != "pod-1_uid" ||
+				r.Status.AcquireTime == nil ||
+				r.Status.LeaseDurationSeconds == nil ||
+				*r.Status.LeaseDurationSeconds != 60 {
+				t.Fatalf("Expected valid lock status got %+v", r.Status)
+			}
+			got, err := client.Get("recipe-lock", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Expected lock got [%+v]", err)
+			}
+			if isLeaseExpired(got, time.Now()) {
+				t.Fatalf("Expected lease to be valid")
+			}
+			_, err = unlock()
+			if err != nil {
+				t.Fatalf("Expected no unlock error got [%+v]", err)
+			}
+			got, err = client.Get("recipe-lock", metav1.GetOptions{})
+			if !mock.isLockForever {
+				if err == nil {
+					t.Fatalf("Expected lock to be deleted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected lock to exist got [%+v]", err)
+			}
+			if isLeaseExpired(got, time.Now().Add(time.Hour)) {
+				t.Fatalf("Expected lock without lease to never expire")
+			}
+		})
+	}
+}
@@ -19,10 +19,14 @@ limitations under the License.
 package recipe
 
 import (
+	"errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8stesting "k8s.io/client-go/testing"
 	"openebs.io/metac/dynamic/clientset"
 	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
 )
@@ -142,3 +146,44 @@ var NoopConfigMapFixture = &BaseFixture{
 		return &dynamicdiscovery.APIResource{}
 	},
 }
+
+// NewErrorFixture returns a BaseFixture instance useful for unit
+// testing whose clients respond with the provided error to every
+// action
+func NewErrorFixture(err error) *BaseFixture {
+	return &BaseFixture{
+		// get a fake dynamic client that responds with the error
+		getClientForAPIVersionAndKindFn: func(
+			apiversion string,
+			kind string,
+		) (*clientset.ResourceClient, error) {
+			di := dynamicfake.NewSimpleDynamicClient(runtime.NewScheme())
+			di.PrependReactor(
+				"*",
+				"*",
+				func(action k8stesting.Action) (bool, runtime.Object, error) {
+					return true, nil, err
+				},
+			)
+			nri := di.Resource(schema.GroupVersionResource{
+				Version:  "v1",
+				Resource: "configmaps",
+			})
+			ri := nri.Namespace("")
+			return &clientset.ResourceClient{
+				ResourceInterface: ri,
+				APIResource:       &dynamicdiscovery.APIResource{},
+			}, nil
+		},
+	}
+}
+
+// ForbiddenFixture is a BaseFixture instance useful for unit testing
+// whose clients are denied every action
+var ForbiddenFixture = NewErrorFixture(
+	apierrors.NewForbidden(
+		schema.GroupResource{Resource: "configmaps"},
+		"",
+		errors.New("denied by test"),
+	),
+)
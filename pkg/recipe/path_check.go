@@ -444,6 +444,60 @@ func (pc *PathChecking) assert() {
 	}
 }
 
+// IsMatch verifies this path check against the provided resource
+// without any retries
+//
+// NOTE:
+//	This is useful to evaluate resources that are already observed
+// from the cluster
+func (pc *PathChecking) IsMatch(obj *unstructured.Unstructured) (bool, error) {
+	var fns = []func(){
+		pc.init,
+		pc.validate,
+	}
+	for _, fn := range fns {
+		fn()
+		if pc.err != nil {
+			return false, pc.err
+		}
+	}
+	if obj == nil || obj.Object == nil {
+		return false, nil
+	}
+	switch pc.operator {
+	case types.PathCheckOperatorExists:
+		pc.retryIfPathNotExists = true
+	case types.PathCheckOperatorNotExists:
+		pc.retryIfPathExists = true
+	case types.PathCheckOperatorEquals:
+		pc.retryIfValueNotEquals = true
+	case types.PathCheckOperatorNotEquals:
+		pc.retryIfValueEquals = true
+	case types.PathCheckOperatorGTE:
+		pc.retryIfValueNotGTE = true
+	case types.PathCheckOperatorLTE:
+		pc.retryIfValueNotLTE = true
+	default:
+		return false, errors.Errorf(
+			"PathCheck %q failed: Invalid operator %q",
+			pc.TaskName,
+			pc.operator,
+		)
+	}
+	if pc.pathOnlyCheck {
+		return pc.assertPath(obj)
+	}
+	_, found, err := unstructured.NestedFieldNoCopy(
+		obj.UnstructuredContent(),
+		strings.Split(pc.PathCheck.Path, ".")...,
+	)
+	if err != nil || !found {
+		// a resource without this path does not match
+		return false, err
+	}
+	return pc.assertValue(obj)
+}
+
 // Run executes the assertion
 func (pc *PathChecking) Run() (types.PathCheckResult, error) {
 	var fns = []func(){
@@ -18,16 +18,21 @@ package recipe
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 
 	"mayadata.io/d-operators/common/unstruct"
+	"mayadata.io/d-operators/pkg/event"
 	"mayadata.io/d-operators/pkg/kubernetes"
+	"mayadata.io/d-operators/pkg/metrics"
 	"mayadata.io/d-operators/pkg/schema"
 	types "mayadata.io/d-operators/types/recipe"
 	metac "openebs.io/metac/start"
@@ -41,6 +46,8 @@ type RunnerConfig struct {
 	Retry                     *kubernetes.Retryable
 	Fixture                   *Fixture
 	UpdateRecipeWithRetriesFn func() error
+	IsCancelledFn             func() (bool, error)
+	Recorder                  record.EventRecorder
 }
 
 // Runner helps executing a Recipe
@@ -52,10 +59,35 @@ type Runner struct {
 	// Retry                     *Retryable
 	Retry *kubernetes.Retryable
 
+	// Recorder records events against this Recipe. Events are
+	// not recorded if this is nil.
+	Recorder record.EventRecorder
+
 	fixture    *Fixture
 	isTearDown bool
+
+	// fixture to run tasks against the target cluster and / or
+	// as the service account if any
+	//
+	// NOTE:
+	//	Lock & status of this Recipe are always managed via fixture
+	tasksFixture *Fixture
+
 	hasCRDTask bool
 
+	// flags if a re-run was requested via rerun token
+	isRerun bool
+
+	// flags if this Recipe was resumed after its suspension
+	isResumed bool
+
+	// time by which the tasks of this run should complete
+	deadline time.Time
+
+	// phase of the tasks that were interrupted due to timeout
+	// or cancellation
+	interruptedAs types.TaskStatusPhase
+
 	// err as value
 	err error
 
@@ -66,6 +98,11 @@ type Runner struct {
 	// calls instead of being watched as a Kubernetes Custom Resource.
 	// In such a case, one can implement this function as a NOOP.
 	UpdateRecipeWithRetriesFn func() error
+
+	// IsCancelledFn verifies if this Recipe was requested to be
+	// cancelled. Latest annotations of this Recipe are verified if
+	// this is not set.
+	IsCancelledFn func() (bool, error)
 }
 
 // NewRunner returns a new instance of Runner
@@ -80,6 +117,11 @@ func NewRunner(config RunnerConfig) *Runner {
 	if config.Retry != nil {
 		retry = config.Retry
 	}
+	// check event recorder
+	var recorder = config.Recorder
+	if recorder == nil {
+		recorder = event.Recorder()
+	}
 	return &Runner{
 		isTearDown:                isTearDown,
 		Recipe:                    config.Recipe,
@@ -88,8 +130,10 @@ func NewRunner(config RunnerConfig) *Runner {
 			TaskResults: map[string]types.TaskResult{},
 		},
 		Retry:                     retry,
+		Recorder:                  recorder,
 		fixture:                   config.Fixture,
 		UpdateRecipeWithRetriesFn: config.UpdateRecipeWithRetriesFn,
+		IsCancelledFn:             config.IsCancelledFn,
 	}
 }
 
@@ -175,14 +219,72 @@ func (r *Runner) initFixture() {
 	r.fixture = f
 }
 
+// getTasksFixture returns the fixture that is used to check the
+// eligibility & run the tasks of this Recipe
+func (r *Runner) getTasksFixture() *Fixture {
+	if r.tasksFixture != nil {
+		return r.tasksFixture
+	}
+	return r.fixture
+}
+
 func (r *Runner) initEnabled() {
 	if r.Recipe.Spec.Enabled == nil {
+		var when = types.EnabledRuleOnce
+		if r.Recipe.Spec.Schedule != nil {
+			// a scheduled Recipe is meant to be run repeatedly
+			when = types.EnabledRuleAlways
+		}
 		r.Recipe.Spec.Enabled = &types.Enabled{
-			When: types.EnabledRuleOnce,
+			When: when,
+		}
+	}
+	if r.Recipe.Spec.Enabled.When == "" {
+		// enabled may be set with other fields e.g. rerunPolicy
+		r.Recipe.Spec.Enabled.When = types.EnabledRuleOnce
+		if r.Recipe.Spec.Schedule != nil {
+			r.Recipe.Spec.Enabled.When = types.EnabledRuleAlways
 		}
 	}
 }
 
+// initRerun carries forward the rerun details from the observed
+// status & flags if a re-run is requested either via the rerun token
+// or due to a change in spec
+func (r *Runner) initRerun() {
+	r.RecipeStatus.RerunToken = r.Recipe.Status.RerunToken
+	r.RecipeStatus.PreviousRun = r.Recipe.Status.PreviousRun
+	token := r.Recipe.GetAnnotations()[types.AnnotationKeyRerunToken]
+	isNewToken := token != "" && token != r.Recipe.Status.RerunToken
+	if !isNewToken && !r.isSpecChanged() {
+		// no new rerun request
+		return
+	}
+	r.isRerun = true
+	if isNewToken {
+		r.RecipeStatus.RerunToken = token
+	}
+	if r.Recipe.Status.Phase == "" {
+		// nothing to archive since Recipe has not run yet
+		return
+	}
+	// archive the result of the previous run
+	r.RecipeStatus.PreviousRun = &types.RecipeRunSummary{
+		Phase:         r.Recipe.Status.Phase,
+		Reason:        r.Recipe.Status.Reason,
+		Message:       r.Recipe.Status.Message,
+		RerunToken:    r.Recipe.Status.RerunToken,
+		ExecutionTime: r.Recipe.Status.ExecutionTime,
+		TaskCount:     r.Recipe.Status.TaskCount,
+	}
+}
+
+// initHistory carries forward the run history from the observed
+// status
+func (r *Runner) initHistory() {
+	r.RecipeStatus.History = r.Recipe.Status.History
+}
+
 func (r *Runner) waitTillThinkTimeExpires() {
 	if r.Recipe.Spec.ThinkTimeInSeconds == nil {
 		return
@@ -197,7 +299,13 @@ func (r *Runner) waitTillThinkTimeExpires() {
 func (r *Runner) init() error {
 	var fns = []func(){
 		r.initEnabled,
+		r.initResume,
+		r.initRevision,
+		r.initRerun,
+		r.initHistory,
 		r.initFixture,
+		r.initTargetFixture,
+		r.initServiceAccount,
 	}
 	for _, fn := range fns {
 		fn()
@@ -214,14 +322,78 @@ func (r *Runner) isRunEligible() (bool, error) {
 
 	e, err := NewEligibility(EligibilityConfig{
 		RecipeName: fmt.Sprintf("%s %s", r.Recipe.GetNamespace(), r.Recipe.GetName()),
-		Fixture:    r.fixture,
+		Fixture:    r.getTasksFixture(),
 		Eligible:   r.Recipe.Spec.Eligible,
 		Retry:      r.Retry,
 	})
 	if err != nil {
 		return false, err
 	}
-	return e.IsEligible()
+	eligible, err := e.IsEligible()
+	// set the evaluated condition tree to let users know
+	// which branch failed if any
+	r.RecipeStatus.Eligibility = e.ConditionResult
+	return eligible, err
+}
+
+// isScheduleDue returns true if this Recipe is due for a run as
+// per its schedule. Recipe status is set with the schedule details.
+func (r *Runner) isScheduleDue() (bool, error) {
+	if r.Recipe.Spec.Schedule == nil {
+		// Recipe without a schedule is always due
+		return true, nil
+	}
+	if r.Recipe.Spec.Enabled.When == types.EnabledRuleOnce {
+		return false, errors.Errorf(
+			"Invalid schedule: Can't be used with enabled.when %q: Recipe %q / %q",
+			types.EnabledRuleOnce,
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+	}
+	s, err := NewScheduling(SchedulingConfig{
+		RecipeName:   fmt.Sprintf("%s %s", r.Recipe.GetNamespace(), r.Recipe.GetName()),
+		Schedule:     r.Recipe.Spec.Schedule,
+		Status:       r.Recipe.Status.Schedule,
+		CreationTime: r.Recipe.GetCreationTimestamp(),
+	})
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	if r.isResumed {
+		// runs are not caught up for the duration of suspension
+		s.Resume(now)
+	}
+	due := s.IsDue(now)
+	r.RecipeStatus.Schedule = &s.Status
+	return due, nil
+}
+
+// updateStatusWithoutRun updates the Recipe status with the latest
+// schedule & lock details when the Recipe is not run. Status of the
+// previous run is retained.
+func (r *Runner) updateStatusWithoutRun(
+	defaultPhase types.RecipeStatusPhase,
+) (types.RecipeStatus, error) {
+	status := r.Recipe.Status
+	if r.RecipeStatus.Schedule != nil {
+		status.Schedule = r.RecipeStatus.Schedule
+	}
+	if r.RecipeStatus.Lock != nil {
+		status.Lock = r.RecipeStatus.Lock
+	}
+	if r.isRevisionUnknown() {
+		// current revision is recorded to detect the subsequent
+		// changes to the spec
+		status.Revision = r.RecipeStatus.Revision
+	}
+	if status.Phase == "" {
+		// Recipe has not run yet
+		status.Phase = defaultPhase
+	}
+	r.RecipeStatus = &status
+	return status, r.updateRecipeWithRetries()
 }
 
 func (r *Runner) eval(task types.Task) error {
@@ -252,6 +424,10 @@ func (r *Runner) eval(task types.Task) error {
 		action++
 		state = task.Label.State
 	}
+	if task.Approval != nil {
+		// approval does not act on any state
+		action++
+	}
 	if action == 0 {
 		return errors.Errorf(
 			"Invalid task %q: Missing action",
@@ -267,7 +443,7 @@ func (r *Runner) eval(task types.Task) error {
 	// TODO (@amitd)
 	// Below logic may not be required. Its not used.
 	// This can be removed after adding integration & e2e tests
-	if state.GetKind() == "CustomResourceDefinition" {
+	if state != nil && state.GetKind() == "CustomResourceDefinition" {
 		r.hasCRDTask = true
 	}
 	return nil
@@ -299,14 +475,24 @@ func (r *Runner) buildLockRunner() *LockRunner {
 			},
 		},
 	}
+	var leaseDuration = defaultLockLeaseDuration
+	if r.Recipe.Spec.Lock != nil &&
+		r.Recipe.Spec.Lock.LeaseDurationSeconds != nil &&
+		*r.Recipe.Spec.Lock.LeaseDurationSeconds > 0 {
+		leaseDuration =
+			time.Duration(*r.Recipe.Spec.Lock.LeaseDurationSeconds) * time.Second
+	}
 	return &LockRunner{
 		BaseRunner: BaseRunner{
 			Fixture:      r.fixture,
 			Retry:        r.Retry,
 			FailFastRule: lock.FailFast.When,
 		},
-		Task:        lock,
-		LockForever: isLockForever,
+		Task:           lock,
+		LockForever:    isLockForever,
+		HolderIdentity: LockHolderIdentity,
+		LeaseDuration:  leaseDuration,
+		Revision:       r.RecipeStatus.Revision,
 
 		// no of tasks that are considered (read protected)
 		// by this lock
@@ -338,12 +524,18 @@ func (r *Runner) validateSchema() {
 	}
 }
 
-// evalAll evaluates all tasks
+// evalAll evaluates all tasks including onFailure & finally tasks
 func (r *Runner) evalAllTasks() error {
-	for _, task := range r.Recipe.Spec.Tasks {
-		err := r.eval(task)
-		if err != nil {
-			return err
+	for _, tasks := range [][]types.Task{
+		r.Recipe.Spec.Tasks,
+		r.Recipe.Spec.OnFailure,
+		r.Recipe.Spec.Finally,
+	} {
+		for _, task := range tasks {
+			err := r.eval(task)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -356,9 +548,137 @@ func (r *Runner) mayBePassedOrCompletedStatus() types.RecipeStatusPhase {
 	return types.RecipeStatusPassed
 }
 
+// defaultRunHistoryLimit is the number of executions that are
+// recorded in Recipe's status by default
+const defaultRunHistoryLimit = 10
+
+// recordRunHistory adds the current execution to the run history
+// of the Recipe. Oldest records are removed to honour the history
+// limit.
+func (r *Runner) recordRunHistory(start time.Time, end time.Time) {
+	var limit = defaultRunHistoryLimit
+	if r.Recipe.Spec.RunHistoryLimit != nil {
+		limit = *r.Recipe.Spec.RunHistoryLimit
+	}
+	if limit <= 0 {
+		// history is disabled
+		r.RecipeStatus.History = nil
+		return
+	}
+	// results are used instead of spec.tasks since these include
+	// the tasks expanded from forEach & includes
+	var failedTasks []string
+	for name, result := range r.RecipeStatus.TaskResults {
+		if result.Phase == types.TaskStatusFailed {
+			failedTasks = append(failedTasks, name)
+		}
+	}
+	// failed tasks are listed in their order of execution
+	sort.Slice(failedTasks, func(i, j int) bool {
+		var (
+			stepi = r.RecipeStatus.TaskResults[failedTasks[i]].Step
+			stepj = r.RecipeStatus.TaskResults[failedTasks[j]].Step
+		)
+		if stepi != stepj {
+			return stepi < stepj
+		}
+		return failedTasks[i] < failedTasks[j]
+	})
+	history := append(
+		// copy to avoid modifying the observed status
+		append([]types.RecipeRunSummary{}, r.RecipeStatus.History...),
+		types.RecipeRunSummary{
+			Phase:          r.RecipeStatus.Phase,
+			Reason:         r.RecipeStatus.Reason,
+			Message:        r.RecipeStatus.Message,
+			RerunToken:     r.RecipeStatus.RerunToken,
+			StartTime:      &metav1.Time{Time: start},
+			CompletionTime: &metav1.Time{Time: end},
+			ExecutionTime:  r.RecipeStatus.ExecutionTime,
+			TaskCount:      r.RecipeStatus.TaskCount,
+			FailedTasks:    failedTasks,
+		},
+	)
+	if len(history) > limit {
+		// oldest records are at the beginning
+		history = history[len(history)-limit:]
+	}
+	r.RecipeStatus.History = history
+}
+
+// recordEvent records an event against this Recipe
+func (r *Runner) recordEvent(
+	eventtype, reason, messageFmt string,
+	args ...interface{},
+) {
+	if r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(
+		event.NewReference(r.Recipe.TypeMeta, &r.Recipe),
+		eventtype,
+		reason,
+		messageFmt,
+		args...,
+	)
+}
+
+// recordPhaseTransition records an event if the phase of this
+// Recipe has changed from its observed phase
+//
+// NOTE:
+//	Comparing against the observed phase avoids recording the same
+// event during every resync
+func (r *Runner) recordPhaseTransition() {
+	var (
+		observed = r.Recipe.Status.Phase
+		current  = r.RecipeStatus.Phase
+	)
+	if current == "" || current == observed {
+		return
+	}
+	message := fmt.Sprintf("Phase changed to %s", current)
+	if observed != "" {
+		message = fmt.Sprintf("Phase changed from %s to %s", observed, current)
+	}
+	if r.RecipeStatus.Reason != "" {
+		message = fmt.Sprintf("%s: %s", message, r.RecipeStatus.Reason)
+	} else if r.RecipeStatus.TaskCount != nil &&
+		r.RecipeStatus.TaskCount.Failed > 0 {
+		message = fmt.Sprintf(
+			"%s: %d of %d task(s) failed",
+			message,
+			r.RecipeStatus.TaskCount.Failed,
+			r.RecipeStatus.TaskCount.Total,
+		)
+	}
+	r.recordEvent(
+		event.TypeForPhase(
+			string(current),
+			string(types.RecipeStatusFailed),
+			string(types.RecipeStatusInvalidSchema),
+			string(types.RecipeStatusWarning),
+			string(types.RecipeStatusTimedOut),
+		),
+		string(current),
+		message,
+	)
+}
+
 // updateRecipeWithRetries updates the kubernetes cluster with
 // desired recipe
 func (r *Runner) updateRecipeWithRetries() error {
+	err := r.persistRecipeWithRetries()
+	if err == nil {
+		// record only those transitions that were persisted
+		r.recordPhaseTransition()
+	}
+	return err
+}
+
+// persistRecipeWithRetries updates the status & labels of this
+// recipe in the kubernetes cluster
+func (r *Runner) persistRecipeWithRetries() error {
 	if r.UpdateRecipeWithRetriesFn != nil {
 		return r.UpdateRecipeWithRetriesFn()
 	}
@@ -494,10 +814,202 @@ func (r *Runner) updateRecipeWithRetries() error {
 	return retryErr
 }
 
+// runTask runs the provided task with the provided retry options
+func (r *Runner) runTask(
+	idx int,
+	task types.Task,
+	retry *kubernetes.Retryable,
+) (types.TaskResult, error) {
+	var failFastRule types.FailFastRule
+	if task.FailFast != nil {
+		failFastRule = task.FailFast.When
+	}
+	fixture, err := r.buildTaskFixture(task)
+	if err != nil {
+		return types.TaskResult{}, errors.Wrapf(
+			err,
+			"Task failed: Index %d: Name %q: Recipe %q / %q",
+			idx+1,
+			task.Name,
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+		)
+	}
+	tr := &TaskRunner{
+		BaseRunner: BaseRunner{
+			Fixture:      fixture,
+			TaskIndex:    idx + 1,
+			TaskName:     task.Name,
+			Retry:        retry,
+			FailFastRule: failFastRule,
+		},
+		Task: task,
+	}
+	taskStart := time.Now()
+	got, err := tr.Run()
+	taskPhase := string(got.Phase)
+	if err != nil {
+		taskPhase = "Error"
+	}
+	metrics.ObserveRecipeTask(
+		r.Recipe.Namespace,
+		r.Recipe.Name,
+		tr.action,
+		taskPhase,
+		time.Since(taskStart),
+	)
+	if err != nil {
+		return types.TaskResult{}, errors.Wrapf(
+			err,
+			"Task failed: Index %d: Name %q: Recipe %q / %q",
+			idx+1,
+			task.Name,
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+		)
+	}
+	return got, nil
+}
+
+// runTasks runs the tasks set in spec.tasks
+func (r *Runner) runTasks() error {
+	tasks, err := r.expandTasks(r.Recipe.Spec.Tasks)
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Recipe %q / %q",
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+		)
+	}
+	// total includes the tasks expanded from forEach
+	r.RecipeStatus.TaskCount.Total = len(tasks)
+	// tasks that passed in the previous run are skipped if
+	// checkpoint is enabled
+	resumeFrom := r.initCheckpoint(tasks)
+	for idx, task := range tasks {
+		if idx < resumeFrom {
+			continue
+		}
+		if phase := r.interruption(); phase != "" {
+			// remaining tasks are not run
+			r.interruptTasks(tasks[idx:], phase, idx)
+			return nil
+		}
+		var got types.TaskResult
+		if task.Approval != nil {
+			got, err = r.runApproval(idx, task)
+		} else {
+			got, err = r.runTask(idx, task, r.retryWithinDeadline())
+		}
+		if err != nil && r.isTimedOut() {
+			// task was stopped since it was retried till the
+			// deadline of this run
+			r.interruptTasks(tasks[idx+1:], types.TaskStatusTimedOut, idx+1)
+			r.RecipeStatus.TaskResults[task.Name] = types.TaskResult{
+				Step:    idx + 1,
+				Phase:   types.TaskStatusTimedOut,
+				Message: err.Error(),
+			}
+			return nil
+		}
+		if err != nil {
+			// We discontinue executing next tasks
+			// if current task execution resulted in
+			// error
+			return err
+		}
+		r.RecipeStatus.TaskResults[task.Name] = got
+		if got.Phase == types.TaskStatusWaitingForApproval {
+			// remaining tasks are run once this task is approved
+			r.interruptedAs = got.Phase
+			r.checkpoint(task, got)
+			return nil
+		}
+		if got.Phase == types.TaskStatusFailed {
+			// Run subsequent tasks even if current task failed
+			r.RecipeStatus.TaskCount.Failed++
+			if r.Recipe.Status.TaskResults[task.Name].Phase != types.TaskStatusFailed {
+				// record only if this task did not fail previously
+				r.recordEvent(
+					corev1.EventTypeWarning,
+					event.ReasonTaskFailed,
+					"Task %q failed: %s",
+					task.Name,
+					got.Message,
+				)
+			}
+		}
+		if got.Phase == types.TaskStatusWarning {
+			// Run subsequent tasks even if current task has warnings
+			r.RecipeStatus.TaskCount.Warning++
+		}
+		r.checkpoint(task, got)
+	}
+	return nil
+}
+
+// runHookTasks runs the provided onFailure or finally tasks & returns
+// their results. Observed results are the ones from the previous run.
+//
+// NOTE:
+//	All these tasks are run even if some of them fail or result in
+// errors. An error is recorded as a failed task. These results do not
+// change the phase of this Recipe.
+func (r *Runner) runHookTasks(
+	tasks []types.Task,
+	observed map[string]types.TaskResult,
+) map[string]types.TaskResult {
+	if len(tasks) == 0 {
+		return nil
+	}
+	var results = map[string]types.TaskResult{}
+	var record = func(name string, got types.TaskResult) {
+		results[name] = got
+		if got.Phase == types.TaskStatusFailed &&
+			observed[name].Phase != types.TaskStatusFailed {
+			// record only if this task did not fail previously
+			r.recordEvent(
+				corev1.EventTypeWarning,
+				event.ReasonTaskFailed,
+				"Task %q failed: %s",
+				name,
+				got.Message,
+			)
+		}
+	}
+	var step int
+	for _, task := range tasks {
+		expanded, err := r.expandTask(task)
+		if err != nil {
+			step++
+			record(task.Name, types.TaskResult{
+				Step:    step,
+				Phase:   types.TaskStatusFailed,
+				Message: err.Error(),
+			})
+			continue
+		}
+		for _, task := range expanded {
+			got, err := r.runTask(step, task, r.Retry)
+			step++
+			if err != nil {
+				got = types.TaskResult{
+					Step:    step,
+					Phase:   types.TaskStatusFailed,
+					Message: err.Error(),
+				}
+			}
+			record(task.Name, got)
+		}
+	}
+	return results
+}
+
 // runAllTasks runs all the tasks
 func (r *Runner) runAllTasks() (err error) {
 	defer func() {
-		r.fixture.TearDown()
+		r.getTasksFixture().TearDown()
 		if err == nil {
 			// update recipe's status if there was **no** error
 			err = r.updateRecipeWithRetries()
@@ -526,6 +1038,46 @@ func (r *Runner) runAllTasks() (err error) {
 		Total: len(r.Recipe.Spec.Tasks),
 	}
 
+	// Recipes that this Recipe depends on should reach their
+	// phases before verifying the eligibility
+	met, err := r.isDependencyMet()
+	if cycle, isCycle := errors.Cause(err).(*DependencyCycleError); isCycle {
+		// a cycle can't be resolved by retrying
+		klog.V(2).Infof(
+			"Will skip execution: %s: Recipe %q / %q",
+			cycle.Error(),
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		r.RecipeStatus.Phase = types.RecipeStatusNotEligible
+		r.RecipeStatus.Reason = cycle.Error()
+		r.RecipeStatus.Message =
+			"Remedy: Remove the cycle from spec.dependsOn of these Recipes"
+		r.RecipeStatus.TaskCount.Skipped = len(r.Recipe.Spec.Tasks)
+		return nil
+	}
+	if err != nil {
+		return errors.Wrapf(
+			err,
+			"Dependency check failed: Recipe %q / %q",
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+		)
+	}
+	if !met {
+		klog.V(2).Infof(
+			"Will skip execution: Dependencies not met: Recipe %q / %q",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		r.RecipeStatus.Phase = types.RecipeStatusNotEligible
+		r.RecipeStatus.Reason = "Did not meet dependencies"
+		r.RecipeStatus.Message =
+			"Remedy: Wait for the Recipes in spec.dependsOn to reach their phases"
+		r.RecipeStatus.TaskCount.Skipped = len(r.Recipe.Spec.Tasks)
+		return nil
+	}
+
 	// first thing to do even before running the Recipe is to
 	// verify if this Recipe is eligible to run
 	eligible, err := r.isRunEligible()
@@ -561,61 +1113,84 @@ func (r *Runner) runAllTasks() (err error) {
 	}
 
 	var start = time.Now()
-	for idx, task := range r.Recipe.Spec.Tasks {
-		var failFastRule types.FailFastRule
-		if task.FailFast != nil {
-			failFastRule = task.FailFast.When
-		}
-		tr := &TaskRunner{
-			BaseRunner: BaseRunner{
-				Fixture:      r.fixture,
-				TaskIndex:    idx + 1,
-				TaskName:     task.Name,
-				Retry:        r.Retry,
-				FailFastRule: failFastRule,
-			},
-			Task: task,
-		}
-		got, err := tr.Run()
-		if err != nil {
-			// We discontinue executing next tasks
-			// if current task execution resulted in
-			// error
-			return errors.Wrapf(
-				err,
-				"Task failed: Index %d: Name %q: Recipe %q / %q",
-				idx+1,
-				task.Name,
-				r.Recipe.Namespace,
-				r.Recipe.Name,
-			)
-		}
-		r.RecipeStatus.TaskResults[task.Name] = got
-		if got.Phase == types.TaskStatusFailed {
-			// Run subsequent tasks even if current task failed
-			r.RecipeStatus.TaskCount.Failed++
-		}
-		if got.Phase == types.TaskStatusWarning {
-			// Run subsequent tasks even if current task has warnings
-			r.RecipeStatus.TaskCount.Warning++
-		}
+	r.initDeadline(start)
+	err = r.runTasks()
+	if err == nil && r.interruptedAs == types.TaskStatusWaitingForApproval {
+		// onFailure & finally tasks are run once the remaining
+		// tasks are run
+		klog.V(2).Infof(
+			"Will pause execution: Waiting for approval: Recipe %q / %q",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		r.RecipeStatus.Phase = types.RecipeStatusWaitingForApproval
+		r.RecipeStatus.Reason = "Waiting for approval"
+		r.RecipeStatus.Message =
+			"Remedy: Approve or reject the task in status.approvals that is WaitingForApproval"
+		return nil
+	}
+	// onFailure & finally tasks are run even if above tasks
+	// resulted in an error or timed out
+	if err != nil ||
+		r.RecipeStatus.TaskCount.Failed > 0 ||
+		r.interruptedAs == types.TaskStatusTimedOut {
+		r.RecipeStatus.OnFailureTaskResults = r.runHookTasks(
+			r.Recipe.Spec.OnFailure,
+			r.Recipe.Status.OnFailureTaskResults,
+		)
 	}
+	r.RecipeStatus.FinallyTaskResults = r.runHookTasks(
+		r.Recipe.Spec.Finally,
+		r.Recipe.Status.FinallyTaskResults,
+	)
 
 	// time taken for this recipe to run all its tasks
-	duration := time.Since(start)
+	end := time.Now()
+	duration := end.Sub(start)
 	r.RecipeStatus.ExecutionTime = &types.ExecutionTime{
 		ValueInSeconds: duration.Seconds(),
 		ReadableValue:  duration.Round(time.Millisecond).String(),
 	}
 
+	if err != nil {
+		// record this errored execution
+		//
+		// NOTE:
+		//	Status is not updated here. Reconciler sets the status
+		// from this error & retains the history.
+		r.RecipeStatus.Phase = types.RecipeStatusError
+		r.RecipeStatus.Reason = err.Error()
+		r.recordRunHistory(start, end)
+		return err
+	}
+
 	// set other fields of the status
-	if r.RecipeStatus.TaskCount.Failed > 0 {
+	if r.interruptedAs == types.TaskStatusTimedOut {
+		r.RecipeStatus.Phase = types.RecipeStatusTimedOut
+		r.RecipeStatus.Reason = fmt.Sprintf(
+			"Exceeded timeout of %ds",
+			*r.Recipe.Spec.TimeoutInSeconds,
+		)
+		r.RecipeStatus.Message =
+			"Remedy: Increase spec.timeoutInSeconds or reduce the time taken by tasks"
+	} else if r.interruptedAs == types.TaskStatusCancelled {
+		r.RecipeStatus.Phase = types.RecipeStatusCancelled
+		r.RecipeStatus.Reason = "Cancelled via annotation"
+		r.RecipeStatus.Message = fmt.Sprintf(
+			"Remedy: Remove annotation %q & change annotation %q to run again",
+			types.AnnotationKeyCancel,
+			types.AnnotationKeyRerunToken,
+		)
+	} else if r.RecipeStatus.TaskCount.Failed > 0 {
 		// recipe is set to failed if any of its tasks resulted in failure
 		r.RecipeStatus.Phase = types.RecipeStatusFailed
 	} else {
 		r.RecipeStatus.Phase = r.mayBePassedOrCompletedStatus()
 	}
 
+	// record this execution
+	r.recordRunHistory(start, end)
+
 	return nil
 }
 
@@ -625,6 +1200,29 @@ func (r *Runner) Run() (status types.RecipeStatus, err error) {
 	if err != nil {
 		return types.RecipeStatus{}, err
 	}
+	if r.isSuspended() {
+		return r.suspend()
+	}
+	// a scheduled Recipe is run only at its scheduled times
+	due, err := r.isScheduleDue()
+	if err != nil {
+		return types.RecipeStatus{}, err
+	}
+	if !due && r.isRerun {
+		klog.V(3).Infof(
+			"Will execute before scheduled time: Rerun requested: Recipe %q / %q",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+	} else if !due {
+		klog.V(3).Infof(
+			"Will skip execution: Not scheduled: Recipe %q / %q: %s",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+			r.RecipeStatus.Schedule.Message,
+		)
+		return r.updateStatusWithoutRun(types.RecipeStatusScheduled)
+	}
 	// proceed further by verifying the presence of LOCK
 	//
 	// NOTE:
@@ -644,16 +1242,68 @@ func (r *Runner) Run() (status types.RecipeStatus, err error) {
 			r.Recipe.Status.Reason,
 		)
 	}
+	if locked && r.isRerun && lockrunner.Status.LeaseDurationSeconds == nil {
+		// lock of the previous run is removed to run once more
+		//
+		// NOTE:
+		//	A lock with lease is not removed since it is held by
+		// an ongoing execution
+		_, err = lockrunner.MustUnlock()
+		if err != nil {
+			return types.RecipeStatus{}, errors.Wrapf(
+				err,
+				"Remove previous lock failed: Rerun %q: Recipe %q / %q",
+				r.RecipeStatus.RerunToken,
+				r.Recipe.GetNamespace(),
+				r.Recipe.GetName(),
+			)
+		}
+		klog.V(2).Infof(
+			"Removed previous lock: Rerun %q: Recipe %q / %q",
+			r.RecipeStatus.RerunToken,
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		locked = false
+	}
 	if locked {
 		klog.V(3).Infof(
-			"Will skip execution: Previous lock exists: Recipe %q / %q: Status %q %q",
+			"Will skip execution: Previous lock exists: Recipe %q / %q: Status %q %q: Holder %q",
 			r.Recipe.GetNamespace(),
 			r.Recipe.GetName(),
 			r.Recipe.Status.Phase,
 			r.Recipe.Status.Reason,
+			lockrunner.Status.HolderIdentity,
 		)
+		if lockrunner.Status.LeaseDurationSeconds != nil {
+			// surface the holder of this lock since the lock is
+			// held by an ongoing execution
+			metrics.IncLockContention(
+				"Recipe",
+				r.Recipe.GetNamespace(),
+				r.Recipe.GetName(),
+			)
+			r.RecipeStatus.Lock = lockrunner.Status
+			// repeated events with the same holder are aggregated
+			// by the event recorder
+			r.recordEvent(
+				corev1.EventTypeNormal,
+				string(types.RecipeStatusLocked),
+				"Skipped execution: Locked by %s",
+				lockrunner.Status.HolderIdentity,
+			)
+			_, err = r.updateStatusWithoutRun(types.RecipeStatusLocked)
+			if err != nil {
+				return types.RecipeStatus{}, err
+			}
+		} else if r.isResumed || r.isRevisionUnknown() {
+			// persist the phase prior to suspension or the revision
+			// since this Recipe is not run
+			return r.updateStatusWithoutRun(types.RecipeStatusLocked)
+		}
 		return types.RecipeStatus{
 			Phase: types.RecipeStatusLocked,
+			Lock:  lockrunner.Status,
 		}, nil
 	}
 
@@ -677,18 +1327,21 @@ func (r *Runner) Run() (status types.RecipeStatus, err error) {
 			r.Recipe.Status.Reason,
 		)
 	}
+	r.RecipeStatus.Lock = lockrunner.Status
 	// make use of defer to UNLOCK
 	defer func() {
 		// FORCE UNLOCK in case of one of following:
 		// - Executing recipe resulted in error _OR_
 		// - Recipe is currently not eligible to be executed _OR_
-		// - Recipe schema is invalid
+		// - Recipe schema is invalid _OR_
+		// - Recipe is waiting for approval
 		//
 		// NOTE:
 		//	Lock is removed to enable subsequent reconcile attempts
 		if err != nil ||
 			r.RecipeStatus.Phase == types.RecipeStatusNotEligible ||
-			r.RecipeStatus.Phase == types.RecipeStatusInvalidSchema {
+			r.RecipeStatus.Phase == types.RecipeStatusInvalidSchema ||
+			r.RecipeStatus.Phase == types.RecipeStatusWaitingForApproval {
 			_, unlockerr := lockrunner.MustUnlock()
 			if unlockerr != nil {
 				// swallow unlock error by logging
@@ -749,12 +1402,18 @@ func (r *Runner) Run() (status types.RecipeStatus, err error) {
 		)
 	}()
 
+	err = r.expandIncludes()
+	if err != nil {
+		return types.RecipeStatus{}, err
+	}
 	err = r.evalAllTasks()
 	if err != nil {
 		return types.RecipeStatus{}, err
 	}
 
-	return *r.RecipeStatus, r.runAllTasks()
+	// status is returned after running the tasks
+	err = r.runAllTasks()
+	return *r.RecipeStatus, err
 }
 
 // RunWithoutLocking executes the tasks in a sequential order
@@ -764,6 +1423,9 @@ func (r *Runner) RunWithoutLocking() (status types.RecipeStatus, err error) {
 	if err != nil {
 		return types.RecipeStatus{}, err
 	}
+	if r.isSuspended() {
+		return r.suspend()
+	}
 
 	klog.V(2).Infof(
 		"Will execute: Recipe %q / %q: Status %q %q",
@@ -773,9 +1435,14 @@ func (r *Runner) RunWithoutLocking() (status types.RecipeStatus, err error) {
 		r.Recipe.Status.Reason,
 	)
 
+	err = r.expandIncludes()
+	if err != nil {
+		return types.RecipeStatus{}, err
+	}
 	err = r.evalAllTasks()
 	if err != nil {
 		return types.RecipeStatus{}, err
 	}
-	return *r.RecipeStatus, r.runAllTasks()
+	err = r.runAllTasks()
+	return *r.RecipeStatus, err
 }
@@ -19,10 +19,13 @@ limitations under the License.
 package recipe
 
 import (
+	"errors"
 	"testing"
 	"time"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/record"
 	"mayadata.io/d-operators/common/pointer"
 	"mayadata.io/d-operators/pkg/kubernetes"
 	types "mayadata.io/d-operators/types/recipe"
@@ -350,3 +353,721 @@ func TestRunnerRunAllTasks(t *testing.T) {
 		})
 	}
 }
+
+func TestRunnerRunAllTasksWithOnFailureAndFinally(t *testing.T) {
+	// newCreateTask returns a task that creates a config map
+	newCreateTask := func(name string, ignore types.IgnoreErrorRule) types.Task {
+		return types.Task{
+			Name:            name,
+			IgnoreErrorRule: ignore,
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": "cm",
+						},
+					},
+				},
+			},
+		}
+	}
+	var spec = types.RecipeSpec{
+		Tasks: []types.Task{
+			newCreateTask("create", ""),
+		},
+		OnFailure: []types.Task{
+			newCreateTask("collect", types.IgnoreErrorAsWarning),
+		},
+		Finally: []types.Task{
+			newCreateTask("cleanup", ""),
+		},
+	}
+	var tests = map[string]struct {
+		baseFixture       *BaseFixture
+		expectedPhase     types.RecipeStatusPhase
+		expectedOnFailure map[string]types.TaskStatusPhase
+		expectedFinally   map[string]types.TaskStatusPhase
+		isErr             bool
+	}{
+		"passed tasks do not run onFailure tasks": {
+			baseFixture:   NoopFixture,
+			expectedPhase: types.RecipeStatusCompleted,
+			expectedFinally: map[string]types.TaskStatusPhase{
+				"cleanup": types.TaskStatusPassed,
+			},
+		},
+		"failed tasks run onFailure tasks": {
+			baseFixture:   ForbiddenFixture,
+			expectedPhase: types.RecipeStatusFailed,
+			expectedOnFailure: map[string]types.TaskStatusPhase{
+				"collect": types.TaskStatusWarning,
+			},
+			expectedFinally: map[string]types.TaskStatusPhase{
+				"cleanup": types.TaskStatusFailed,
+			},
+		},
+		"errored tasks run onFailure tasks": {
+			baseFixture:   NewErrorFixture(errors.New("connection refused")),
+			expectedPhase: types.RecipeStatusError,
+			expectedOnFailure: map[string]types.TaskStatusPhase{
+				"collect": types.TaskStatusWarning,
+			},
+			expectedFinally: map[string]types.TaskStatusPhase{
+				"cleanup": types.TaskStatusFailed,
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			r := &Runner{
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Recipe: types.Recipe{
+					Spec: spec,
+				},
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: make(map[string]types.TaskResult),
+				},
+				fixture: &Fixture{
+					BaseFixture: mock.baseFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			}
+			r.initEnabled()        // init to avoid nil pointers
+			err := r.runAllTasks() // method under test
+			if mock.isErr && err == nil {
+				t.Fatal("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if r.RecipeStatus.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected status.phase %q got %q",
+					mock.expectedPhase,
+					r.RecipeStatus.Phase,
+				)
+			}
+			// errored runs are recorded as well
+			if len(r.RecipeStatus.History) != 1 ||
+				r.RecipeStatus.History[0].Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected history with phase %q got %+v",
+					mock.expectedPhase,
+					r.RecipeStatus.History,
+				)
+			}
+			for _, check := range []struct {
+				expected map[string]types.TaskStatusPhase
+				got      map[string]types.TaskResult
+			}{
+				{mock.expectedOnFailure, r.RecipeStatus.OnFailureTaskResults},
+				{mock.expectedFinally, r.RecipeStatus.FinallyTaskResults},
+			} {
+				if len(check.got) != len(check.expected) {
+					t.Fatalf(
+						"Expected %d task results got %d: %v",
+						len(check.expected),
+						len(check.got),
+						check.got,
+					)
+				}
+				for task, phase := range check.expected {
+					if check.got[task].Phase != phase {
+						t.Fatalf(
+							"Expected task %q with phase %q got %q: %s",
+							task,
+							phase,
+							check.got[task].Phase,
+							check.got[task].Message,
+						)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRunnerRunAllTasksWithTimeoutAndCancel(t *testing.T) {
+	// newCreateTask returns a task that creates a config map
+	newCreateTask := func(name string) types.Task {
+		return types.Task{
+			Name: name,
+			Create: &types.Create{
+				State: &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": name,
+						},
+					},
+				},
+			},
+		}
+	}
+	var zero int64
+	var tests = map[string]struct {
+		timeoutInSeconds *int64
+		annotations      map[string]string
+		cancelAfter      int
+		expectedPhase    types.RecipeStatusPhase
+		expectedTasks    map[string]types.TaskStatusPhase
+		expectedSkipped  int
+		expectedCollect  types.TaskStatusPhase
+	}{
+		"tasks within timeout": {
+			expectedPhase: types.RecipeStatusCompleted,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"one": types.TaskStatusPassed,
+				"two": types.TaskStatusPassed,
+			},
+		},
+		"tasks exceed timeout": {
+			timeoutInSeconds: &zero,
+			expectedPhase:    types.RecipeStatusTimedOut,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"one": types.TaskStatusTimedOut,
+				"two": types.TaskStatusTimedOut,
+			},
+			expectedSkipped: 2,
+			expectedCollect: types.TaskStatusPassed,
+		},
+		"cancelled before running tasks": {
+			annotations: map[string]string{
+				types.AnnotationKeyCancel: "true",
+			},
+			expectedPhase: types.RecipeStatusCancelled,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"one": types.TaskStatusCancelled,
+				"two": types.TaskStatusCancelled,
+			},
+			expectedSkipped: 2,
+		},
+		"cancelled while running tasks": {
+			cancelAfter:   1,
+			expectedPhase: types.RecipeStatusCancelled,
+			expectedTasks: map[string]types.TaskStatusPhase{
+				"one": types.TaskStatusPassed,
+				"two": types.TaskStatusCancelled,
+			},
+			expectedSkipped: 1,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			timeout := 1 * time.Second // unit test don't need to retry
+			var verified int
+			r := &Runner{
+				Retry: kubernetes.NewRetry(kubernetes.RetryConfig{
+					WaitTimeout: &timeout,
+				}),
+				Recipe: types.Recipe{
+					ObjectMeta: metav1.ObjectMeta{
+						Annotations: mock.annotations,
+					},
+					Spec: types.RecipeSpec{
+						TimeoutInSeconds: mock.timeoutInSeconds,
+						Tasks: []types.Task{
+							newCreateTask("one"),
+							newCreateTask("two"),
+						},
+						OnFailure: []types.Task{
+							newCreateTask("collect"),
+						},
+						Finally: []types.Task{
+							newCreateTask("cleanup"),
+						},
+					},
+				},
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: make(map[string]types.TaskResult),
+				},
+				fixture: &Fixture{
+					BaseFixture: NoopFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			}
+			if mock.cancelAfter > 0 {
+				r.IsCancelledFn = func() (bool, error) {
+					verified++
+					return verified > mock.cancelAfter, nil
+				}
+			}
+			r.initEnabled()        // init to avoid nil pointers
+			err := r.runAllTasks() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if r.RecipeStatus.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected status.phase %q got %q",
+					mock.expectedPhase,
+					r.RecipeStatus.Phase,
+				)
+			}
+			for task, phase := range mock.expectedTasks {
+				got := r.RecipeStatus.TaskResults[task]
+				if got.Phase != phase {
+					t.Fatalf(
+						"Expected task %q with phase %q got %q: %s",
+						task,
+						phase,
+						got.Phase,
+						got.Message,
+					)
+				}
+			}
+			if r.RecipeStatus.TaskCount.Skipped != mock.expectedSkipped {
+				t.Fatalf(
+					"Expected %d skipped tasks got %d",
+					mock.expectedSkipped,
+					r.RecipeStatus.TaskCount.Skipped,
+				)
+			}
+			collect := r.RecipeStatus.OnFailureTaskResults["collect"]
+			if collect.Phase != mock.expectedCollect {
+				t.Fatalf(
+					"Expected onFailure task with phase %q got %q",
+					mock.expectedCollect,
+					collect.Phase,
+				)
+			}
+			// finally tasks are run even if tasks were interrupted
+			cleanup := r.RecipeStatus.FinallyTaskResults["cleanup"]
+			if cleanup.Phase != types.TaskStatusPassed {
+				t.Fatalf(
+					"Expected finally task with phase %q got %q: %s",
+					types.TaskStatusPassed,
+					cleanup.Phase,
+					cleanup.Message,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerInitRerun(t *testing.T) {
+	var tests = map[string]struct {
+		annotations         map[string]string
+		status              types.RecipeStatus
+		isRerun             bool
+		expectedToken       string
+		expectedPrevious    types.RecipeStatusPhase
+		expectedHasPrevious bool
+	}{
+		"no rerun token": {
+			status: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+			},
+			isRerun: false,
+		},
+		"rerun token already observed": {
+			annotations: map[string]string{
+				types.AnnotationKeyRerunToken: "1",
+			},
+			status: types.RecipeStatus{
+				Phase:      types.RecipeStatusCompleted,
+				RerunToken: "1",
+				PreviousRun: &types.RecipeRunSummary{
+					Phase: types.RecipeStatusFailed,
+				},
+			},
+			isRerun:             false,
+			expectedToken:       "1",
+			expectedPrevious:    types.RecipeStatusFailed,
+			expectedHasPrevious: true,
+		},
+		"new rerun token": {
+			annotations: map[string]string{
+				types.AnnotationKeyRerunToken: "2",
+			},
+			status: types.RecipeStatus{
+				Phase:      types.RecipeStatusCompleted,
+				RerunToken: "1",
+			},
+			isRerun:             true,
+			expectedToken:       "2",
+			expectedPrevious:    types.RecipeStatusCompleted,
+			expectedHasPrevious: true,
+		},
+		"rerun token before first run": {
+			annotations: map[string]string{
+				types.AnnotationKeyRerunToken: "1",
+			},
+			isRerun:       true,
+			expectedToken: "1",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			recipe := types.Recipe{
+				Status: mock.status,
+			}
+			recipe.SetAnnotations(mock.annotations)
+			r := &Runner{
+				Recipe:       recipe,
+				RecipeStatus: &types.RecipeStatus{},
+			}
+			r.initRerun()
+			if r.isRerun != mock.isRerun {
+				t.Fatalf("Expected rerun %t got %t", mock.isRerun, r.isRerun)
+			}
+			if r.RecipeStatus.RerunToken != mock.expectedToken {
+				t.Fatalf(
+					"Expected rerun token %q got %q",
+					mock.expectedToken,
+					r.RecipeStatus.RerunToken,
+				)
+			}
+			if (r.RecipeStatus.PreviousRun != nil) != mock.expectedHasPrevious {
+				t.Fatalf(
+					"Expected previous run %t got %+v",
+					mock.expectedHasPrevious,
+					r.RecipeStatus.PreviousRun,
+				)
+			}
+			if mock.expectedHasPrevious &&
+				r.RecipeStatus.PreviousRun.Phase != mock.expectedPrevious {
+				t.Fatalf(
+					"Expected previous phase %q got %q",
+					mock.expectedPrevious,
+					r.RecipeStatus.PreviousRun.Phase,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerRunWithRerunToken(t *testing.T) {
+	var tests = map[string]struct {
+		rerunToken    string
+		observedToken string
+		expectedPhase types.RecipeStatusPhase
+	}{
+		"locked forever without rerun token": {
+			expectedPhase: types.RecipeStatusLocked,
+		},
+		"locked forever with observed rerun token": {
+			rerunToken:    "1",
+			observedToken: "1",
+			expectedPhase: types.RecipeStatusLocked,
+		},
+		"locked forever with new rerun token": {
+			rerunToken:    "2",
+			observedToken: "1",
+			expectedPhase: types.RecipeStatusCompleted,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			// lock of a Recipe that was run once
+			lockrunner, client := newLockRunner(
+				&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": "once-lock",
+						},
+						"data": map[string]interface{}{
+							types.LockDataKeyHolderIdentity: "pod-0",
+						},
+					},
+				},
+			)
+			recipe := types.Recipe{
+				Status: types.RecipeStatus{
+					Phase:      types.RecipeStatusCompleted,
+					RerunToken: mock.observedToken,
+				},
+			}
+			recipe.SetName("once")
+			if mock.rerunToken != "" {
+				recipe.SetAnnotations(map[string]string{
+					types.AnnotationKeyRerunToken: mock.rerunToken,
+				})
+			}
+			r := NewRunner(RunnerConfig{
+				Recipe:  recipe,
+				Retry:   lockrunner.Retry,
+				Fixture: lockrunner.Fixture,
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			})
+			got, err := r.Run()
+			if err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+			if got.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected phase %q got %q",
+					mock.expectedPhase,
+					got.Phase,
+				)
+			}
+			if mock.expectedPhase == types.RecipeStatusLocked {
+				return
+			}
+			if got.RerunToken != mock.rerunToken {
+				t.Fatalf(
+					"Expected rerun token %q got %q",
+					mock.rerunToken,
+					got.RerunToken,
+				)
+			}
+			if got.PreviousRun == nil ||
+				got.PreviousRun.Phase != types.RecipeStatusCompleted ||
+				got.PreviousRun.RerunToken != mock.observedToken {
+				t.Fatalf("Expected archived previous run got %+v", got.PreviousRun)
+			}
+			// Recipe is locked forever once again
+			lock, err := client.Get("once-lock", metav1.GetOptions{})
+			if err != nil {
+				t.Fatalf("Expected lock got [%+v]", err)
+			}
+			if isLeaseExpired(lock, time.Now().Add(time.Hour)) {
+				t.Fatalf("Expected lock without lease")
+			}
+		})
+	}
+}
+
+func TestRunnerRecordRunHistory(t *testing.T) {
+	record := func(phase types.RecipeStatusPhase) types.RecipeRunSummary {
+		return types.RecipeRunSummary{
+			Phase: phase,
+		}
+	}
+	var tests = map[string]struct {
+		limit               *int
+		history             []types.RecipeRunSummary
+		taskResults         map[string]types.TaskResult
+		expectedPhases      []types.RecipeStatusPhase
+		expectedFailedTasks []string
+	}{
+		"first run": {
+			taskResults: map[string]types.TaskResult{
+				"one": {Phase: types.TaskStatusPassed},
+				"two": {Phase: types.TaskStatusPassed},
+			},
+			expectedPhases: []types.RecipeStatusPhase{
+				types.RecipeStatusFailed,
+			},
+		},
+		"failed tasks are recorded": {
+			history: []types.RecipeRunSummary{
+				record(types.RecipeStatusPassed),
+			},
+			taskResults: map[string]types.TaskResult{
+				"one": {Phase: types.TaskStatusFailed},
+				"two": {Phase: types.TaskStatusPassed},
+			},
+			expectedPhases: []types.RecipeStatusPhase{
+				types.RecipeStatusPassed,
+				types.RecipeStatusFailed,
+			},
+			expectedFailedTasks: []string{"one"},
+		},
+		"failed tasks expanded from forEach are recorded": {
+			taskResults: map[string]types.TaskResult{
+				"one[1]": {Step: 2, Phase: types.TaskStatusFailed},
+				"one[0]": {Step: 1, Phase: types.TaskStatusFailed},
+				"two":    {Step: 3, Phase: types.TaskStatusPassed},
+			},
+			expectedPhases: []types.RecipeStatusPhase{
+				types.RecipeStatusFailed,
+			},
+			expectedFailedTasks: []string{"one[0]", "one[1]"},
+		},
+		"oldest records are removed": {
+			limit: pointer.Int(2),
+			history: []types.RecipeRunSummary{
+				record(types.RecipeStatusPassed),
+				record(types.RecipeStatusWarning),
+			},
+			expectedPhases: []types.RecipeStatusPhase{
+				types.RecipeStatusWarning,
+				types.RecipeStatusFailed,
+			},
+		},
+		"history is disabled": {
+			limit: pointer.Int(0),
+			history: []types.RecipeRunSummary{
+				record(types.RecipeStatusPassed),
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						RunHistoryLimit: mock.limit,
+						Tasks: []types.Task{
+							{Name: "one"},
+							{Name: "two"},
+						},
+					},
+					Status: types.RecipeStatus{
+						History: mock.history,
+					},
+				},
+				RecipeStatus: &types.RecipeStatus{
+					Phase:       types.RecipeStatusFailed,
+					TaskResults: mock.taskResults,
+				},
+			}
+			r.initHistory()
+			start := time.Now()
+			r.recordRunHistory(start, start.Add(time.Second))
+			if len(r.RecipeStatus.History) != len(mock.expectedPhases) {
+				t.Fatalf(
+					"Expected history count %d got %d",
+					len(mock.expectedPhases),
+					len(r.RecipeStatus.History),
+				)
+			}
+			for idx, phase := range mock.expectedPhases {
+				if r.RecipeStatus.History[idx].Phase != phase {
+					t.Fatalf(
+						"Expected phase %q at %d got %q",
+						phase,
+						idx,
+						r.RecipeStatus.History[idx].Phase,
+					)
+				}
+			}
+			if len(mock.expectedPhases) == 0 {
+				return
+			}
+			latest := r.RecipeStatus.History[len(r.RecipeStatus.History)-1]
+			if latest.StartTime == nil || latest.CompletionTime == nil {
+				t.Fatalf("Expected start & completion time got %+v", latest)
+			}
+			if len(latest.FailedTasks) != len(mock.expectedFailedTasks) {
+				t.Fatalf(
+					"Expected failed tasks %v got %v",
+					mock.expectedFailedTasks,
+					latest.FailedTasks,
+				)
+			}
+			for idx, task := range mock.expectedFailedTasks {
+				if latest.FailedTasks[idx] != task {
+					t.Fatalf(
+						"Expected failed tasks %v got %v",
+						mock.expectedFailedTasks,
+						latest.FailedTasks,
+					)
+				}
+			}
+		})
+	}
+}
+
+func TestRunnerRecordPhaseTransition(t *testing.T) {
+	var tests = map[string]struct {
+		observed     types.RecipeStatus
+		current      types.RecipeStatus
+		expectEvents []string
+	}{
+		"first run": {
+			current: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+			},
+			expectEvents: []string{
+				"Normal Completed Phase changed to Completed",
+			},
+		},
+		"same phase during resync": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+			},
+			current: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+			},
+		},
+		"completed to failed": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusCompleted,
+			},
+			current: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+				TaskCount: &types.TaskCount{
+					Total:  2,
+					Failed: 1,
+				},
+			},
+			expectEvents: []string{
+				"Warning Failed Phase changed from Completed to Failed: 1 of 2 task(s) failed",
+			},
+		},
+		"failed to not eligible": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+			},
+			current: types.RecipeStatus{
+				Phase:  types.RecipeStatusNotEligible,
+				Reason: "Did not meet eligibility criteria",
+			},
+			expectEvents: []string{
+				"Normal NotEligible Phase changed from Failed to NotEligible: Did not meet eligibility criteria",
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(10)
+			r := &Runner{
+				Recipe: types.Recipe{
+					Status: mock.observed,
+				},
+				RecipeStatus:              &mock.current,
+				Recorder:                  recorder,
+				UpdateRecipeWithRetriesFn: func() error { return nil },
+			}
+			err := r.updateRecipeWithRetries()
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			close(recorder.Events)
+			var got []string
+			for e := range recorder.Events {
+				got = append(got, e)
+			}
+			if len(got) != len(mock.expectEvents) {
+				t.Fatalf("Expected events %v got %v", mock.expectEvents, got)
+			}
+			for idx, e := range mock.expectEvents {
+				if got[idx] != e {
+					t.Fatalf("Expected event %q got %q", e, got[idx])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// SpecHash returns the hash of the provided Recipe spec
+//
+// NOTE:
+//	Suspend is excluded since suspending or resuming a Recipe does
+// not change what this Recipe does
+func SpecHash(spec types.RecipeSpec) (string, error) {
+	spec.Suspend = nil
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(raw)), nil
+}
+
+// initRevision sets the revision of the spec that is run
+//
+// NOTE:
+//	This should be invoked before the spec gets modified e.g. when
+// tasks are included from RecipeTemplates
+func (r *Runner) initRevision() {
+	hash, err := SpecHash(r.Recipe.Spec)
+	if err != nil {
+		r.err = err
+		return
+	}
+	r.RecipeStatus.Revision = &types.SpecRevision{
+		Generation: r.Recipe.GetGeneration(),
+		SpecHash:   hash,
+	}
+}
+
+// isRerunOnSpecChange returns true if this Recipe is meant to be
+// run once more when its spec changes
+func (r *Runner) isRerunOnSpecChange() bool {
+	return r.Recipe.Spec.Enabled != nil &&
+		r.Recipe.Spec.Enabled.When == types.EnabledRuleOnce &&
+		r.Recipe.Spec.Enabled.RerunPolicy == types.RerunPolicyOnSpecChange
+}
+
+// isRevisionUnknown returns true if this Recipe has run previously
+// without recording its revision e.g. a Recipe that was run before
+// its rerun policy was set
+func (r *Runner) isRevisionUnknown() bool {
+	return r.isRerunOnSpecChange() &&
+		r.Recipe.Status.Phase != "" &&
+		r.Recipe.Status.Revision == nil &&
+		r.RecipeStatus.Revision != nil
+}
+
+// isSpecChanged returns true if this Recipe is meant to be run
+// once more since its spec has changed after its previous run
+//
+// NOTE:
+//	A change in generation that does not change the hash e.g.
+// suspending this Recipe does not result in a re-run
+//
+// NOTE:
+//	An unknown observed revision is not considered as a change.
+// Current revision is recorded instead to detect the subsequent
+// changes.
+func (r *Runner) isSpecChanged() bool {
+	if !r.isRerunOnSpecChange() {
+		return false
+	}
+	var observed = r.Recipe.Status.Revision
+	var current = r.RecipeStatus.Revision
+	if r.Recipe.Status.Phase == "" || observed == nil || current == nil {
+		// Recipe has not run yet or its revision is not known
+		return false
+	}
+	if observed.Generation != 0 &&
+		observed.Generation == current.Generation {
+		// spec was not modified
+		return false
+	}
+	return observed.SpecHash != current.SpecHash
+}
@@ -0,0 +1,277 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestSpecHash(t *testing.T) {
+	var suspend = true
+	var base = types.RecipeSpec{
+		Tasks: []types.Task{{Name: "one"}},
+	}
+	baseHash, err := SpecHash(base)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	var tests = map[string]struct {
+		spec         types.RecipeSpec
+		isHashChange bool
+	}{
+		"same spec": {
+			spec: types.RecipeSpec{
+				Tasks: []types.Task{{Name: "one"}},
+			},
+		},
+		"suspended spec": {
+			spec: types.RecipeSpec{
+				Suspend: &suspend,
+				Tasks:   []types.Task{{Name: "one"}},
+			},
+		},
+		"changed tasks": {
+			spec: types.RecipeSpec{
+				Tasks: []types.Task{{Name: "one"}, {Name: "two"}},
+			},
+			isHashChange: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := SpecHash(mock.spec)
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if (got != baseHash) != mock.isHashChange {
+				t.Fatalf(
+					"Expected hash change %t got hash %q base %q",
+					mock.isHashChange,
+					got,
+					baseHash,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerInitRerunOnSpecChange(t *testing.T) {
+	var spec = types.RecipeSpec{
+		Enabled: &types.Enabled{
+			When:        types.EnabledRuleOnce,
+			RerunPolicy: types.RerunPolicyOnSpecChange,
+		},
+		Tasks: []types.Task{{Name: "one"}},
+	}
+	hash, err := SpecHash(spec)
+	if err != nil {
+		t.Fatalf("Expected no error got %+v", err)
+	}
+	var tests = map[string]struct {
+		policy     types.RerunPolicy
+		generation int64
+		observed   types.RecipeStatus
+		isRerun    bool
+
+		isRevisionUnknown bool
+	}{
+		"no rerun policy": {
+			generation: 2,
+			observed: types.RecipeStatus{
+				Phase:    types.RecipeStatusPassed,
+				Revision: &types.SpecRevision{Generation: 1, SpecHash: "old"},
+			},
+		},
+		"recipe has not run yet": {
+			policy:     types.RerunPolicyOnSpecChange,
+			generation: 1,
+		},
+		"same generation": {
+			policy:     types.RerunPolicyOnSpecChange,
+			generation: 1,
+			observed: types.RecipeStatus{
+				Phase:    types.RecipeStatusPassed,
+				Revision: &types.SpecRevision{Generation: 1, SpecHash: "old"},
+			},
+		},
+		"new generation with same hash": {
+			policy:     types.RerunPolicyOnSpecChange,
+			generation: 2,
+			observed: types.RecipeStatus{
+				Phase:    types.RecipeStatusPassed,
+				Revision: &types.SpecRevision{Generation: 1, SpecHash: hash},
+			},
+		},
+		"new generation with changed hash": {
+			policy:     types.RerunPolicyOnSpecChange,
+			generation: 2,
+			observed: types.RecipeStatus{
+				Phase:    types.RecipeStatusPassed,
+				Revision: &types.SpecRevision{Generation: 1, SpecHash: "old"},
+			},
+			isRerun: true,
+		},
+		"previous run without revision": {
+			policy:     types.RerunPolicyOnSpecChange,
+			generation: 1,
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusFailed,
+			},
+			isRevisionUnknown: true,
+		},
+		"previous run that resulted in error": {
+			policy:     types.RerunPolicyOnSpecChange,
+			generation: 2,
+			observed: types.RecipeStatus{
+				Phase:    types.RecipeStatusError,
+				Revision: &types.SpecRevision{Generation: 2, SpecHash: "old"},
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			recipe := types.Recipe{
+				Spec: types.RecipeSpec{
+					Enabled: &types.Enabled{
+						When:        types.EnabledRuleOnce,
+						RerunPolicy: mock.policy,
+					},
+					Tasks: spec.Tasks,
+				},
+				Status: mock.observed,
+			}
+			recipe.SetGeneration(mock.generation)
+			r := &Runner{
+				Recipe:       recipe,
+				RecipeStatus: &types.RecipeStatus{},
+			}
+			r.initRevision()
+			r.initRerun()
+			if r.err != nil {
+				t.Fatalf("Expected no error got %+v", r.err)
+			}
+			if r.isRerun != mock.isRerun {
+				t.Fatalf("Expected rerun %t got %t", mock.isRerun, r.isRerun)
+			}
+			if r.isRevisionUnknown() != mock.isRevisionUnknown {
+				t.Fatalf(
+					"Expected unknown revision %t got %t",
+					mock.isRevisionUnknown,
+					r.isRevisionUnknown(),
+				)
+			}
+			if mock.isRerun &&
+				(r.RecipeStatus.PreviousRun == nil ||
+					r.RecipeStatus.PreviousRun.Phase != mock.observed.Phase) {
+				t.Fatalf(
+					"Expected previous run with phase %q got %+v",
+					mock.observed.Phase,
+					r.RecipeStatus.PreviousRun,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerRunRecordsUnknownRevision(t *testing.T) {
+	var tests = map[string]struct {
+		policy             types.RerunPolicy
+		isRevisionRecorded bool
+	}{
+		"no rerun policy": {},
+		"rerun on spec change": {
+			policy:             types.RerunPolicyOnSpecChange,
+			isRevisionRecorded: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			// lock of a Recipe that was run once
+			lockrunner, _ := newLockRunner(
+				&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "ConfigMap",
+						"apiVersion": "v1",
+						"metadata": map[string]interface{}{
+							"name": "once-lock",
+						},
+						"data": map[string]interface{}{
+							types.LockDataKeyHolderIdentity: "pod-0",
+						},
+					},
+				},
+			)
+			recipe := types.Recipe{
+				Spec: types.RecipeSpec{
+					Enabled: &types.Enabled{
+						When:        types.EnabledRuleOnce,
+						RerunPolicy: mock.policy,
+					},
+				},
+				Status: types.RecipeStatus{
+					Phase: types.RecipeStatusCompleted,
+				},
+			}
+			recipe.SetName("once")
+			var updated int
+			r := NewRunner(RunnerConfig{
+				Recipe:  recipe,
+				Retry:   lockrunner.Retry,
+				Fixture: lockrunner.Fixture,
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					updated++
+					return nil
+				},
+			})
+			got, err := r.Run()
+			if err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+			if got.Phase != types.RecipeStatusCompleted &&
+				got.Phase != types.RecipeStatusLocked {
+				t.Fatalf("Expected locked recipe got phase %q", got.Phase)
+			}
+			if (got.Revision != nil) != mock.isRevisionRecorded {
+				t.Fatalf(
+					"Expected revision recorded %t got %+v",
+					mock.isRevisionRecorded,
+					got.Revision,
+				)
+			}
+			if (updated > 0) != mock.isRevisionRecorded {
+				t.Fatalf(
+					"Expected recipe updated %t got %d update(s)",
+					mock.isRevisionRecorded,
+					updated,
+				)
+			}
+		})
+	}
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// defaultStartingDeadline is the duration after a scheduled time
+// within which a run is considered to be on time
+const defaultStartingDeadline = 60 * time.Second
+
+// SchedulingConfig helps in constructing new instance of
+// Scheduling
+type SchedulingConfig struct {
+	RecipeName string
+	Schedule   *types.Schedule
+
+	// Status is the schedule status observed from the last
+	// reconciliation if any
+	Status *types.ScheduleStatus
+
+	// CreationTime is used to compute the first scheduled time
+	// if Recipe was never run before
+	CreationTime metav1.Time
+}
+
+// Scheduling decides if a Recipe is due for its next run
+// based on its cron schedule
+//
+// NOTE:
+//	Scheduling depends on the times that are recorded in Recipe's
+// status & hence survives operator restarts
+type Scheduling struct {
+	RecipeName   string
+	Schedule     *types.Schedule
+	Status       types.ScheduleStatus
+	CreationTime metav1.Time
+
+	cronSchedule cron.Schedule
+	location     *time.Location
+	deadline     time.Duration
+}
+
+// NewScheduling returns a new instance of Scheduling
+func NewScheduling(config SchedulingConfig) (*Scheduling, error) {
+	if config.Schedule == nil {
+		return nil, errors.Errorf(
+			"Invalid schedule: Nil schedule: Recipe %s",
+			config.RecipeName,
+		)
+	}
+	s := &Scheduling{
+		RecipeName:   config.RecipeName,
+		Schedule:     config.Schedule,
+		CreationTime: config.CreationTime,
+		location:     time.UTC,
+		deadline:     defaultStartingDeadline,
+	}
+	if config.Status != nil {
+		s.Status = *config.Status
+	}
+	err := s.initAndValidate()
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Scheduling) initAndValidate() error {
+	var errs []string
+	if strings.TrimSpace(s.Schedule.Cron) == "" {
+		errs = append(errs, "Missing cron")
+	} else {
+		sched, err := cron.ParseStandard(s.Schedule.Cron)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Invalid cron: %s", err.Error()))
+		}
+		s.cronSchedule = sched
+	}
+	if s.Schedule.TimeZone != "" {
+		loc, err := time.LoadLocation(s.Schedule.TimeZone)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("Invalid time zone: %s", err.Error()))
+		}
+		s.location = loc
+	}
+	if s.Schedule.StartTime != nil && s.Schedule.EndTime != nil &&
+		!s.Schedule.EndTime.After(s.Schedule.StartTime.Time) {
+		errs = append(errs, "End time must be after start time")
+	}
+	switch s.Schedule.MissedRunPolicy {
+	case "", types.MissedRunPolicySkip, types.MissedRunPolicyRunOnce:
+		// these are supported
+	default:
+		errs = append(
+			errs,
+			fmt.Sprintf(
+				"Unsupported missed run policy %q",
+				s.Schedule.MissedRunPolicy,
+			),
+		)
+	}
+	if s.Schedule.StartingDeadlineSeconds != nil {
+		if *s.Schedule.StartingDeadlineSeconds <= 0 {
+			errs = append(errs, "Starting deadline seconds must be positive")
+		}
+		s.deadline = time.Duration(*s.Schedule.StartingDeadlineSeconds) * time.Second
+	}
+	if len(errs) != 0 {
+		return errors.Errorf(
+			"Invalid schedule: Recipe %s: %s",
+			s.RecipeName,
+			strings.Join(errs, ": "),
+		)
+	}
+	return nil
+}
+
+// next returns the scheduled time after the given time
+func (s *Scheduling) next(t time.Time) time.Time {
+	return s.cronSchedule.Next(t.In(s.location))
+}
+
+// isBeyondEndTime returns true if the given time is after the
+// end time of this schedule
+func (s *Scheduling) isBeyondEndTime(t time.Time) bool {
+	return s.Schedule.EndTime != nil && t.After(s.Schedule.EndTime.Time)
+}
+
+// reference returns the time after which scheduled times need
+// to be considered
+func (s *Scheduling) reference() time.Time {
+	var ref = s.CreationTime.Time
+	if s.Status.LastScheduleTime != nil {
+		ref = s.Status.LastScheduleTime.Time
+	}
+	if s.Schedule.StartTime != nil && s.Schedule.StartTime.After(ref) {
+		// a scheduled time that equals the start time is
+		// considered as well
+		ref = s.Schedule.StartTime.Add(-time.Nanosecond)
+	}
+	return ref
+}
+
+// Resume skips the scheduled times up to the given time at which
+// Recipe is resumed
+//
+// NOTE:
+//	Scheduled times that fell during suspension are neither run
+// nor counted as missed runs. Hence the missed run policy does not
+// result in a run right after resuming.
+func (s *Scheduling) Resume(now time.Time) {
+	var latest *time.Time
+	t := s.next(s.reference())
+	for !t.IsZero() && !t.After(now) && !s.isBeyondEndTime(t) {
+		scheduled := t
+		latest = &scheduled
+		t = s.next(t)
+	}
+	if latest != nil {
+		s.Status.LastScheduleTime = &metav1.Time{Time: *latest}
+	}
+}
+
+// IsDue returns true if Recipe should be run at the given time.
+// Status is updated with the scheduling decision.
+func (s *Scheduling) IsDue(now time.Time) bool {
+	var latest *time.Time
+	var count int
+	// find the latest scheduled time that is not in the future
+	t := s.next(s.reference())
+	for !t.IsZero() && !t.After(now) && !s.isBeyondEndTime(t) {
+		scheduled := t
+		latest = &scheduled
+		count++
+		t = s.next(t)
+	}
+	// t is now the next scheduled time
+	s.Status.NextScheduleTime = nil
+	if !t.IsZero() && !s.isBeyondEndTime(t) {
+		s.Status.NextScheduleTime = &metav1.Time{Time: t}
+	}
+	if latest == nil {
+		if s.Status.NextScheduleTime == nil {
+			s.Status.Message = "No more runs: Schedule has ended"
+		} else {
+			s.Status.Message = "Waiting for next scheduled time"
+		}
+		return false
+	}
+	// runs that were missed prior to the latest scheduled time
+	var missed = count - 1
+	var isRunOnce = s.Schedule.MissedRunPolicy == types.MissedRunPolicyRunOnce
+	s.Status.LastScheduleTime = &metav1.Time{Time: *latest}
+	if now.Sub(*latest) > s.deadline {
+		// latest scheduled time is missed as well
+		missed++
+		if !isRunOnce {
+			s.Status.MissedRuns += missed
+			s.Status.Message = fmt.Sprintf(
+				"Skipped %d missed run(s): Waiting for next scheduled time",
+				missed,
+			)
+			return false
+		}
+		s.Status.Message = fmt.Sprintf(
+			"Running once for %d missed run(s)",
+			missed,
+		)
+		return true
+	}
+	if !isRunOnce {
+		s.Status.MissedRuns += missed
+	}
+	s.Status.Message = "Running as per schedule"
+	return true
+}
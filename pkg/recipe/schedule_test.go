@@ -0,0 +1,302 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"mayadata.io/d-operators/common/pointer"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestNewScheduling(t *testing.T) {
+	var tests = map[string]struct {
+		schedule *types.Schedule
+		isErr    bool
+	}{
+		"nil schedule": {
+			isErr: true,
+		},
+		"missing cron": {
+			schedule: &types.Schedule{},
+			isErr:    true,
+		},
+		"invalid cron": {
+			schedule: &types.Schedule{
+				Cron: "* * *",
+			},
+			isErr: true,
+		},
+		"invalid time zone": {
+			schedule: &types.Schedule{
+				Cron:     "0 2 * * *",
+				TimeZone: "Junk/Zone",
+			},
+			isErr: true,
+		},
+		"invalid missed run policy": {
+			schedule: &types.Schedule{
+				Cron:            "0 2 * * *",
+				MissedRunPolicy: "Junk",
+			},
+			isErr: true,
+		},
+		"end time before start time": {
+			schedule: &types.Schedule{
+				Cron: "0 2 * * *",
+				StartTime: &metav1.Time{
+					Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC),
+				},
+				EndTime: &metav1.Time{
+					Time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+				},
+			},
+			isErr: true,
+		},
+		"valid daily schedule": {
+			schedule: &types.Schedule{
+				Cron: "0 2 * * *",
+			},
+		},
+		"valid descriptor": {
+			schedule: &types.Schedule{
+				Cron:            "@hourly",
+				MissedRunPolicy: types.MissedRunPolicyRunOnce,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			_, err := NewScheduling(SchedulingConfig{
+				RecipeName: name,
+				Schedule:   mock.schedule,
+			})
+			if mock.isErr && err == nil {
+				t.Fatalf("Expected error got none")
+			}
+			if !mock.isErr && err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+		})
+	}
+}
+
+func TestSchedulingIsDue(t *testing.T) {
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2020, 1, day, hour, min, 0, 0, time.UTC)
+	}
+	mtime := func(t time.Time) *metav1.Time {
+		return &metav1.Time{Time: t}
+	}
+	var tests = map[string]struct {
+		schedule           types.Schedule
+		status             *types.ScheduleStatus
+		creationTime       time.Time
+		now                time.Time
+		isDue              bool
+		expectedLast       *time.Time
+		expectedNext       *time.Time
+		expectedMissedRuns int
+	}{
+		"before first scheduled time": {
+			schedule: types.Schedule{
+				Cron: "0 2 * * *",
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(1, 1, 0),
+			isDue:        false,
+			expectedNext: timePtr(at(1, 2, 0)),
+		},
+		"at scheduled time": {
+			schedule: types.Schedule{
+				Cron: "0 2 * * *",
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(1, 2, 0),
+			isDue:        true,
+			expectedLast: timePtr(at(1, 2, 0)),
+			expectedNext: timePtr(at(2, 2, 0)),
+		},
+		"already run at last scheduled time": {
+			schedule: types.Schedule{
+				Cron: "0 2 * * *",
+			},
+			status: &types.ScheduleStatus{
+				LastScheduleTime: mtime(at(1, 2, 0)),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(1, 2, 1),
+			isDue:        false,
+			expectedLast: timePtr(at(1, 2, 0)),
+			expectedNext: timePtr(at(2, 2, 0)),
+		},
+		"missed runs are skipped by default": {
+			schedule: types.Schedule{
+				Cron: "0 2 * * *",
+			},
+			status: &types.ScheduleStatus{
+				LastScheduleTime: mtime(at(1, 2, 0)),
+			},
+			creationTime:       at(1, 0, 0),
+			now:                at(3, 9, 0),
+			isDue:              false,
+			expectedLast:       timePtr(at(3, 2, 0)),
+			expectedNext:       timePtr(at(4, 2, 0)),
+			expectedMissedRuns: 2,
+		},
+		"missed runs are run once": {
+			schedule: types.Schedule{
+				Cron:            "0 2 * * *",
+				MissedRunPolicy: types.MissedRunPolicyRunOnce,
+			},
+			status: &types.ScheduleStatus{
+				LastScheduleTime: mtime(at(1, 2, 0)),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(3, 9, 0),
+			isDue:        true,
+			expectedLast: timePtr(at(3, 2, 0)),
+			expectedNext: timePtr(at(4, 2, 0)),
+		},
+		"within starting deadline": {
+			schedule: types.Schedule{
+				Cron:                    "0 2 * * *",
+				StartingDeadlineSeconds: pointer.Int64(600),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(1, 2, 9),
+			isDue:        true,
+			expectedLast: timePtr(at(1, 2, 0)),
+			expectedNext: timePtr(at(2, 2, 0)),
+		},
+		"before start time": {
+			schedule: types.Schedule{
+				Cron:      "0 2 * * *",
+				StartTime: mtime(at(5, 2, 0)),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(2, 2, 0),
+			isDue:        false,
+			expectedNext: timePtr(at(5, 2, 0)),
+		},
+		"at start time": {
+			schedule: types.Schedule{
+				Cron:      "0 2 * * *",
+				StartTime: mtime(at(5, 2, 0)),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(5, 2, 0),
+			isDue:        true,
+			expectedLast: timePtr(at(5, 2, 0)),
+			expectedNext: timePtr(at(6, 2, 0)),
+		},
+		"last run before end time": {
+			schedule: types.Schedule{
+				Cron:    "0 2 * * *",
+				EndTime: mtime(at(2, 3, 0)),
+			},
+			status: &types.ScheduleStatus{
+				LastScheduleTime: mtime(at(1, 2, 0)),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(2, 2, 0),
+			isDue:        true,
+			expectedLast: timePtr(at(2, 2, 0)),
+		},
+		"after end time": {
+			schedule: types.Schedule{
+				Cron:    "0 2 * * *",
+				EndTime: mtime(at(2, 3, 0)),
+			},
+			status: &types.ScheduleStatus{
+				LastScheduleTime: mtime(at(2, 2, 0)),
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(3, 2, 0),
+			isDue:        false,
+			expectedLast: timePtr(at(2, 2, 0)),
+		},
+		"cron in time zone": {
+			schedule: types.Schedule{
+				Cron:     "30 7 * * *",
+				TimeZone: "Asia/Kolkata",
+			},
+			creationTime: at(1, 0, 0),
+			now:          at(1, 2, 0),
+			isDue:        true,
+			// 07:30 IST is 02:00 UTC
+			expectedLast: timePtr(at(1, 2, 0)),
+			expectedNext: timePtr(at(2, 2, 0)),
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			s, err := NewScheduling(SchedulingConfig{
+				RecipeName:   name,
+				Schedule:     &mock.schedule,
+				Status:       mock.status,
+				CreationTime: metav1.Time{Time: mock.creationTime},
+			})
+			if err != nil {
+				t.Fatalf("Expected no error got [%+v]", err)
+			}
+			got := s.IsDue(mock.now)
+			if got != mock.isDue {
+				t.Fatalf(
+					"Expected due %t got %t: %s",
+					mock.isDue,
+					got,
+					s.Status.Message,
+				)
+			}
+			assertTime := func(field string, expected *time.Time, got *metav1.Time) {
+				if expected == nil && got == nil {
+					return
+				}
+				if expected == nil || got == nil || !expected.Equal(got.Time) {
+					t.Fatalf(
+						"Expected %s %v got %v",
+						field,
+						expected,
+						got,
+					)
+				}
+			}
+			assertTime("last schedule time", mock.expectedLast, s.Status.LastScheduleTime)
+			assertTime("next schedule time", mock.expectedNext, s.Status.NextScheduleTime)
+			if s.Status.MissedRuns != mock.expectedMissedRuns {
+				t.Fatalf(
+					"Expected missed runs %d got %d",
+					mock.expectedMissedRuns,
+					s.Status.MissedRuns,
+				)
+			}
+		})
+	}
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
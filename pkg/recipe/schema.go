@@ -0,0 +1,677 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"mayadata.io/d-operators/pkg/schema"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// actions supported by a Recipe task
+var taskActions = []string{"assert", "apply", "create", "delete", "label", "include", "approval"}
+
+// list count based operators that need a count
+//
+// NOTE:
+//	State check operators share the same values with the
+// corresponding eligible item rules
+var countOperators = map[string]bool{
+	string(types.EligibleItemRuleListCountEquals):    true,
+	string(types.EligibleItemRuleListCountNotEquals): true,
+	string(types.EligibleItemRuleListCountGTE):       true,
+	string(types.EligibleItemRuleListCountLTE):       true,
+}
+
+var labelSelectorOperators = []string{"In", "NotIn", "Exists", "DoesNotExist"}
+
+var pathCheckOperators = []string{
+	string(types.PathCheckOperatorExists),
+	string(types.PathCheckOperatorNotExists),
+	string(types.PathCheckOperatorEquals),
+	string(types.PathCheckOperatorNotEquals),
+	string(types.PathCheckOperatorGTE),
+	string(types.PathCheckOperatorLTE),
+}
+
+var pathValueDataTypes = []string{
+	string(types.PathValueDataTypeInt64),
+	string(types.PathValueDataTypeFloat64),
+	string(types.PathValueDataTypeString),
+}
+
+// recipeValueRules validate the values of individual Recipe fields
+var recipeValueRules = append([]schema.ValueRule{
+	{Path: "spec.teardown", Type: schema.ValueTypeBool},
+	{Path: "spec.resync.onNotEligibleResyncInSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.resync.onErrorResyncInSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.resync.intervalInSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.resync.onWaitingForApprovalResyncInSeconds", Type: schema.ValueTypeInt},
+	{
+		Path: "spec.enabled.when",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.EnabledRuleAlways),
+			string(types.EnabledRuleNever),
+			string(types.EnabledRuleOnce),
+		},
+	},
+	{
+		Path: "spec.enabled.rerunPolicy",
+		Type: schema.ValueTypeString,
+		Enum: []string{string(types.RerunPolicyOnSpecChange)},
+	},
+	// spec.eligible
+	{Path: "spec.eligible.checks", Type: schema.ValueTypeList},
+	{Path: "spec.eligible.checks.[*].id", Type: schema.ValueTypeString},
+	{Path: "spec.eligible.checks.[*].apiVersion", Type: schema.ValueTypeString},
+	{Path: "spec.eligible.checks.[*].kind", Type: schema.ValueTypeString},
+	{Path: "spec.eligible.checks.[*].name", Type: schema.ValueTypeString},
+	{Path: "spec.eligible.checks.[*].namespace", Type: schema.ValueTypeString},
+	{Path: "spec.eligible.checks.[*].count", Type: schema.ValueTypeInt},
+	{
+		Path: "spec.eligible.checks.[*].when",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.EligibleItemRuleExists),
+			string(types.EligibleItemRuleNotFound),
+			string(types.EligibleItemRuleListCountEquals),
+			string(types.EligibleItemRuleListCountNotEquals),
+			string(types.EligibleItemRuleListCountGTE),
+			string(types.EligibleItemRuleListCountLTE),
+		},
+	},
+	{Path: "spec.eligible.checks.[*].pathChecks", Type: schema.ValueTypeList},
+	{Path: "spec.eligible.checks.[*].pathChecks.[*].path", Type: schema.ValueTypeString, Required: true},
+	{
+		Path: "spec.eligible.checks.[*].pathChecks.[*].pathCheckOperator",
+		Type: schema.ValueTypeString,
+		Enum: pathCheckOperators,
+	},
+	{
+		Path: "spec.eligible.checks.[*].pathChecks.[*].dataType",
+		Type: schema.ValueTypeString,
+		Enum: pathValueDataTypes,
+	},
+	{
+		Path: "spec.eligible.checks.[*].labelSelector.matchExpressions.[*].operator",
+		Type: schema.ValueTypeString,
+		Enum: labelSelectorOperators,
+	},
+	{
+		Path: "spec.eligible.condition.operator",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.EligibleConditionOperatorAND),
+			string(types.EligibleConditionOperatorOR),
+			string(types.EligibleConditionOperatorNOT),
+		},
+	},
+	// spec.schedule
+	{Path: "spec.schedule.cron", Type: schema.ValueTypeString, Required: true},
+	{Path: "spec.schedule.timeZone", Type: schema.ValueTypeString},
+	{Path: "spec.schedule.startTime", Type: schema.ValueTypeString},
+	{Path: "spec.schedule.endTime", Type: schema.ValueTypeString},
+	{
+		Path: "spec.schedule.missedRunPolicy",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.MissedRunPolicySkip),
+			string(types.MissedRunPolicyRunOnce),
+		},
+	},
+	{Path: "spec.schedule.startingDeadlineSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.lock.leaseDurationSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.runHistoryLimit", Type: schema.ValueTypeInt},
+	{Path: "spec.timeoutInSeconds", Type: schema.ValueTypeInt},
+	{Path: "spec.suspend", Type: schema.ValueTypeBool},
+	{Path: "spec.checkpoint.enabled", Type: schema.ValueTypeBool},
+	{Path: "spec.targetCluster.secretName", Type: schema.ValueTypeString, Required: true},
+	{Path: "spec.targetCluster.key", Type: schema.ValueTypeString},
+	{Path: "spec.serviceAccountName", Type: schema.ValueTypeString},
+	{Path: "spec.params", Type: schema.ValueTypeMap},
+	// spec.dependsOn
+	{Path: "spec.dependsOn", Type: schema.ValueTypeList},
+	{Path: "spec.dependsOn.[*].name", Type: schema.ValueTypeString},
+	{
+		Path: "spec.dependsOn.[*].phase",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.RecipeStatusCompleted),
+			string(types.RecipeStatusPassed),
+			string(types.RecipeStatusFailed),
+		},
+	},
+	{
+		Path: "spec.dependsOn.[*].labelSelector.matchExpressions.[*].operator",
+		Type: schema.ValueTypeString,
+		Enum: labelSelectorOperators,
+	},
+}, taskListValueRules()...)
+
+// taskValueRules validate the values of individual task fields
+//
+// NOTE:
+//	Paths are relative to a task
+var taskValueRules = []schema.ValueRule{
+	{Path: "name", Type: schema.ValueTypeString, Required: true},
+	{
+		Path: "failFast.when",
+		Type: schema.ValueTypeString,
+		Enum: []string{string(types.FailFastOnDiscoveryError)},
+	},
+	{
+		Path: "ignoreError",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.IgnoreErrorAsPassed),
+			string(types.IgnoreErrorAsWarning),
+			string(types.IgnoreErrorForbiddenAsPassed),
+		},
+	},
+	// impersonate
+	{Path: "impersonate.user", Type: schema.ValueTypeString},
+	{Path: "impersonate.groups", Type: schema.ValueTypeList},
+	{Path: "impersonate.serviceAccount.name", Type: schema.ValueTypeString, Required: true},
+	{Path: "impersonate.serviceAccount.namespace", Type: schema.ValueTypeString},
+	// forEach
+	{Path: "forEach.items", Type: schema.ValueTypeList},
+	{Path: "forEach.param", Type: schema.ValueTypeString},
+	{Path: "forEach.list.state", Type: schema.ValueTypeMap, Required: true},
+	// include
+	{Path: "include.template", Type: schema.ValueTypeString, Required: true},
+	{Path: "include.params", Type: schema.ValueTypeMap},
+	// approval
+	{Path: "approval.configMap.name", Type: schema.ValueTypeString, Required: true},
+	{Path: "approval.configMap.key", Type: schema.ValueTypeString, Required: true},
+	{Path: "approval.configMap.approverKey", Type: schema.ValueTypeString},
+	{Path: "approval.timeoutInSeconds", Type: schema.ValueTypeInt},
+	// create
+	{Path: "create.state", Type: schema.ValueTypeMap, Required: true},
+	{Path: "create.replicas", Type: schema.ValueTypeInt},
+	{Path: "create.ignoreDiscovery", Type: schema.ValueTypeBool},
+	{Path: "create.expectError.code", Type: schema.ValueTypeInt},
+	{Path: "create.expectError.reason", Type: schema.ValueTypeString},
+	{Path: "create.expectError.messagePattern", Type: schema.ValueTypeString},
+	// apply
+	{Path: "apply.state", Type: schema.ValueTypeMap, Required: true},
+	{Path: "apply.replicas", Type: schema.ValueTypeInt},
+	{Path: "apply.ignoreDiscovery", Type: schema.ValueTypeBool},
+	{Path: "apply.expectError.code", Type: schema.ValueTypeInt},
+	{Path: "apply.expectError.reason", Type: schema.ValueTypeString},
+	{Path: "apply.expectError.messagePattern", Type: schema.ValueTypeString},
+	// delete
+	{Path: "delete.state", Type: schema.ValueTypeMap, Required: true},
+	{Path: "delete.expectError.code", Type: schema.ValueTypeInt},
+	{Path: "delete.expectError.reason", Type: schema.ValueTypeString},
+	{Path: "delete.expectError.messagePattern", Type: schema.ValueTypeString},
+	// assert
+	{Path: "assert.state", Type: schema.ValueTypeMap, Required: true},
+	{Path: "assert.errorOnAssertFailure", Type: schema.ValueTypeBool},
+	{
+		Path: "assert.stateCheck.stateCheckOperator",
+		Type: schema.ValueTypeString,
+		Enum: []string{
+			string(types.StateCheckOperatorEquals),
+			string(types.StateCheckOperatorNotEquals),
+			string(types.StateCheckOperatorNotFound),
+			string(types.StateCheckOperatorListCountEquals),
+			string(types.StateCheckOperatorListCountNotEquals),
+		},
+	},
+	{Path: "assert.stateCheck.count", Type: schema.ValueTypeInt},
+	{Path: "assert.pathCheck.path", Type: schema.ValueTypeString, Required: true},
+	{
+		Path: "assert.pathCheck.pathCheckOperator",
+		Type: schema.ValueTypeString,
+		Enum: pathCheckOperators,
+	},
+	{
+		Path: "assert.pathCheck.dataType",
+		Type: schema.ValueTypeString,
+		Enum: pathValueDataTypes,
+	},
+	// label
+	{Path: "label.state", Type: schema.ValueTypeMap, Required: true},
+	{Path: "label.includeByNames", Type: schema.ValueTypeList},
+	{Path: "label.autoUnset", Type: schema.ValueTypeBool},
+	{Path: "label.fieldSelector", Type: schema.ValueTypeString},
+	{Path: "label.removeLabels", Type: schema.ValueTypeList},
+	{Path: "label.removeAnnotations", Type: schema.ValueTypeList},
+	{Path: "label.applyLabels", Type: schema.ValueTypeMap},
+	{Path: "label.applyAnnotations", Type: schema.ValueTypeMap},
+	{
+		Path: "label.labelSelector.matchExpressions.[*].operator",
+		Type: schema.ValueTypeString,
+		Enum: labelSelectorOperators,
+	},
+	{
+		Path: "label.namespaceSelector.matchExpressions.[*].operator",
+		Type: schema.ValueTypeString,
+		Enum: labelSelectorOperators,
+	},
+}
+
+// validateTaskHasOneAction verifies if the task has exactly one
+// action
+func validateTaskHasOneAction(path string, task map[string]interface{}) []schema.ErrorMessage {
+	actions := schema.SetFields(task, taskActions...)
+	if len(actions) == 1 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid task: Path %q: Want exactly one action got %d [%s]",
+				path,
+				len(actions),
+				strings.Join(actions, ", "),
+			),
+			Remedy: fmt.Sprintf(
+				"Set one of the actions: %s",
+				strings.Join(taskActions, ", "),
+			),
+		},
+	}
+}
+
+// validateAssertChecks verifies if the assert task has at most
+// one of stateCheck & pathCheck
+func validateAssertChecks(path string, assert map[string]interface{}) []schema.ErrorMessage {
+	checks := schema.SetFields(assert, "stateCheck", "pathCheck")
+	if len(checks) <= 1 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid assert: Path %q: Both stateCheck & pathCheck are set",
+				path,
+			),
+			Remedy: "Set either stateCheck or pathCheck",
+		},
+	}
+}
+
+// validateCountWithOperator verifies if a count is set when the
+// operator is based on list count
+func validateCountWithOperator(operatorField string) func(string, map[string]interface{}) []schema.ErrorMessage {
+	return func(path string, obj map[string]interface{}) []schema.ErrorMessage {
+		operator, _ := obj[operatorField].(string)
+		if !countOperators[operator] {
+			return nil
+		}
+		if len(schema.SetFields(obj, "count")) == 1 {
+			return nil
+		}
+		return []schema.ErrorMessage{
+			{
+				Error: fmt.Sprintf(
+					"Missing field: Path %q: Count is required by %s %q",
+					path,
+					operatorField,
+					operator,
+				),
+				Remedy: fmt.Sprintf("Set a value at %q", path+".count"),
+			},
+		}
+	}
+}
+
+// validateScheduleWithEnabled verifies if a schedule is set only
+// for a Recipe that can run repeatedly
+func validateScheduleWithEnabled(path string, spec map[string]interface{}) []schema.ErrorMessage {
+	if len(schema.SetFields(spec, "schedule")) == 0 {
+		return nil
+	}
+	enabled, _ := spec["enabled"].(map[string]interface{})
+	when, _ := enabled["when"].(string)
+	if when != string(types.EnabledRuleOnce) {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid schedule: Path %q: Can't be used with enabled.when %q",
+				path+".schedule",
+				types.EnabledRuleOnce,
+			),
+			Remedy: fmt.Sprintf(
+				"Either remove the schedule or set enabled.when to %q",
+				types.EnabledRuleAlways,
+			),
+		},
+	}
+}
+
+// validateRerunPolicyWithWhen verifies if a rerun policy is set
+// only for a Recipe that is meant to be run once
+func validateRerunPolicyWithWhen(path string, enabled map[string]interface{}) []schema.ErrorMessage {
+	if len(schema.SetFields(enabled, "rerunPolicy")) == 0 {
+		return nil
+	}
+	when, _ := enabled["when"].(string)
+	if when == "" || when == string(types.EnabledRuleOnce) {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid rerunPolicy: Path %q: Can't be used with when %q",
+				path+".rerunPolicy",
+				when,
+			),
+			Remedy: fmt.Sprintf(
+				"Either remove the rerunPolicy or set when to %q",
+				types.EnabledRuleOnce,
+			),
+		},
+	}
+}
+
+// validateImpersonate verifies if exactly one of user & service
+// account is impersonated
+func validateImpersonate(path string, impersonate map[string]interface{}) []schema.ErrorMessage {
+	identities := schema.SetFields(impersonate, "user", "serviceAccount")
+	if len(identities) == 1 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid impersonate: Path %q: Want exactly one identity got %d [%s]",
+				path,
+				len(identities),
+				strings.Join(identities, ", "),
+			),
+			Remedy: "Set either user or serviceAccount",
+		},
+	}
+}
+
+// validateImpersonateWithServiceAccount verifies if none of the
+// tasks impersonate when the Recipe is run as a service account
+//
+// NOTE:
+//	A task that impersonates is run with the privileges of this
+// operator & hence can escalate the privileges of the service
+// account
+func validateImpersonateWithServiceAccount(path string, spec map[string]interface{}) []schema.ErrorMessage {
+	if len(schema.SetFields(spec, "serviceAccountName")) == 0 {
+		return nil
+	}
+	var out []schema.ErrorMessage
+	for _, list := range types.TaskListPaths {
+		field := strings.TrimPrefix(list, path+".")
+		tasks, _ := spec[field].([]interface{})
+		for idx, item := range tasks {
+			task, _ := item.(map[string]interface{})
+			if len(schema.SetFields(task, "impersonate")) == 0 {
+				continue
+			}
+			out = append(out, schema.ErrorMessage{
+				Error: fmt.Sprintf(
+					"Invalid impersonate: Path %q: Can't be used with serviceAccountName",
+					fmt.Sprintf("%s.[%d].impersonate", list, idx),
+				),
+				Remedy: "Either remove the impersonate or remove spec.serviceAccountName",
+			})
+		}
+	}
+	return out
+}
+
+// validateDependency verifies if exactly one of name & label
+// selector is set
+func validateDependency(path string, dependency map[string]interface{}) []schema.ErrorMessage {
+	selectors := schema.SetFields(dependency, "name", "labelSelector")
+	if len(selectors) == 1 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid dependency: Path %q: Want exactly one selector got %d [%s]",
+				path,
+				len(selectors),
+				strings.Join(selectors, ", "),
+			),
+			Remedy: "Set either name or labelSelector",
+		},
+	}
+}
+
+// validateTaskWithoutApproval verifies if the task is not an
+// approval task
+//
+// NOTE:
+//	OnFailure & finally tasks are run after the tasks are run &
+// hence can't pause the Recipe
+func validateTaskWithoutApproval(path string, task map[string]interface{}) []schema.ErrorMessage {
+	if len(schema.SetFields(task, "approval")) == 0 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid task: Path %q: Approval is not supported",
+				path,
+			),
+			Remedy: "Move this approval task to spec.tasks",
+		},
+	}
+}
+
+// validateForEach verifies if exactly one source of items is set
+func validateForEach(path string, forEach map[string]interface{}) []schema.ErrorMessage {
+	sources := schema.SetFields(forEach, "items", "param", "list")
+	if len(sources) == 1 {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid forEach: Path %q: Want exactly one source of items got %d [%s]",
+				path,
+				len(sources),
+				strings.Join(sources, ", "),
+			),
+			Remedy: "Set either items, param or list",
+		},
+	}
+}
+
+// validateMessagePattern verifies if the expected error message
+// is a valid regular expression
+func validateMessagePattern(path string, expectError map[string]interface{}) []schema.ErrorMessage {
+	pattern, _ := expectError["messagePattern"].(string)
+	_, err := regexp.Compile(pattern)
+	if err == nil {
+		return nil
+	}
+	return []schema.ErrorMessage{
+		{
+			Error: fmt.Sprintf(
+				"Invalid messagePattern: Path %q: %s",
+				path+".messagePattern",
+				err.Error(),
+			),
+			Remedy: "Set a valid regular expression",
+		},
+	}
+}
+
+// recipeObjectRules validate the relations between Recipe fields
+var recipeObjectRules = append(
+	append(
+		[]schema.ObjectRule{
+			{Path: "spec", Validate: validateScheduleWithEnabled},
+			{Path: "spec", Validate: validateImpersonateWithServiceAccount},
+			{Path: "spec.enabled", Validate: validateRerunPolicyWithWhen},
+		},
+		taskListObjectRules()...,
+	),
+	schema.ObjectRule{
+		Path:     "spec.eligible.checks.[*]",
+		Validate: validateCountWithOperator("when"),
+	},
+	schema.ObjectRule{
+		Path:     "spec.dependsOn.[*]",
+		Validate: validateDependency,
+	},
+	schema.ObjectRule{
+		Path:     "spec.onFailure.[*]",
+		Validate: validateTaskWithoutApproval,
+	},
+	schema.ObjectRule{
+		Path:     "spec.finally.[*]",
+		Validate: validateTaskWithoutApproval,
+	},
+)
+
+// taskObjectRules validate the relations between task fields
+//
+// NOTE:
+//	Paths are relative to a task. An empty path refers to the task.
+var taskObjectRules = []schema.ObjectRule{
+	{Path: "", Validate: validateTaskHasOneAction},
+	{Path: "assert", Validate: validateAssertChecks},
+	{Path: "impersonate", Validate: validateImpersonate},
+	{Path: "forEach", Validate: validateForEach},
+	{Path: "create.expectError", Validate: validateMessagePattern},
+	{Path: "apply.expectError", Validate: validateMessagePattern},
+	{Path: "delete.expectError", Validate: validateMessagePattern},
+	{
+		Path:     "assert.stateCheck",
+		Validate: validateCountWithOperator("stateCheckOperator"),
+	},
+}
+
+// taskListObjectRules returns the object rules of every list of
+// tasks
+func taskListObjectRules() []schema.ObjectRule {
+	var out []schema.ObjectRule
+	for _, list := range types.TaskListPaths {
+		out = append(out, taskObjectRulesAt(list)...)
+	}
+	return out
+}
+
+// taskObjectRulesAt returns the object rules of the tasks found
+// at the provided list
+func taskObjectRulesAt(list string) []schema.ObjectRule {
+	var out []schema.ObjectRule
+	for _, rule := range taskObjectRules {
+		if rule.Path == "" {
+			rule.Path = list + ".[*]"
+		} else {
+			rule.Path = list + ".[*]." + rule.Path
+		}
+		out = append(out, rule)
+	}
+	return out
+}
+
+// taskListValueRules returns the value rules of every list of
+// tasks
+func taskListValueRules() []schema.ValueRule {
+	var out []schema.ValueRule
+	for _, list := range types.TaskListPaths {
+		out = append(out, taskValueRulesAt(list)...)
+	}
+	return out
+}
+
+// taskValueRulesAt returns the value rules of the tasks found at
+// the provided list
+func taskValueRulesAt(list string) []schema.ValueRule {
+	var out = []schema.ValueRule{
+		{Path: list, Type: schema.ValueTypeList},
+	}
+	for _, rule := range taskValueRules {
+		rule.Path = list + ".[*]." + rule.Path
+		out = append(out, rule)
+	}
+	return out
+}
+
+// templateValueRules validate the values of individual
+// RecipeTemplate fields
+var templateValueRules = append([]schema.ValueRule{
+	{Path: "spec.params", Type: schema.ValueTypeList},
+	{Path: "spec.params.[*].name", Type: schema.ValueTypeString, Required: true},
+	{Path: "spec.params.[*].description", Type: schema.ValueTypeString},
+}, taskValueRulesAt("spec.tasks")...)
+
+// templateObjectRules validate the relations between RecipeTemplate
+// fields
+var templateObjectRules = taskObjectRulesAt("spec.tasks")
+
+// ValueRules returns the rules that validate the values of
+// individual Recipe fields
+func ValueRules() []schema.ValueRule {
+	return recipeValueRules
+}
+
+// TemplateValueRules returns the rules that validate the values of
+// individual RecipeTemplate fields
+func TemplateValueRules() []schema.ValueRule {
+	return templateValueRules
+}
+
+// ValidateTemplateSchemaValues validates the types, enums, required
+// fields & relations between the fields of the provided
+// RecipeTemplate
+func ValidateTemplateSchemaValues(template map[string]interface{}) *schema.FieldPathValidationResult {
+	v := &schema.ValueValidation{
+		Target:      template,
+		ValueRules:  templateValueRules,
+		ObjectRules: templateObjectRules,
+	}
+	return v.Validate()
+}
+
+// ValidateSchemaValues validates the types, enums, required fields
+// & relations between the fields of the provided Recipe
+//
+// NOTE:
+//	Provided Recipe is in its unstructured form since values with
+// invalid types can't be converted to a typed Recipe
+func ValidateSchemaValues(recipe map[string]interface{}) *schema.FieldPathValidationResult {
+	v := &schema.ValueValidation{
+		Target:      recipe,
+		ValueRules:  recipeValueRules,
+		ObjectRules: recipeObjectRules,
+	}
+	return v.Validate()
+}
+
+// ValidateSchema validates the field paths as well as the values
+// of the provided Recipe
+func ValidateSchema(recipe map[string]interface{}) *schema.FieldPathValidationResult {
+	v := &schema.FieldPathValidation{
+		Target:                  recipe,
+		SupportedAbsolutePaths:  types.SupportedAbsolutePaths,
+		UserAllowedPathPrefixes: types.UserAllowedPathPrefixes,
+	}
+	result := v.Validate()
+	result.Merge(ValidateSchemaValues(recipe))
+	return result
+}
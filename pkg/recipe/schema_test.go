@@ -0,0 +1,458 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/google/go-cmp/cmp"
+	"mayadata.io/d-operators/pkg/schema"
+)
+
+func TestValidateSchemaValues(t *testing.T) {
+	var tests = map[string]struct {
+		recipe         string
+		expectedErrors []string
+	}{
+		"valid recipe": {
+			recipe: `
+apiVersion: dope.mayadata.io/v1
+kind: Recipe
+spec:
+  enabled:
+    when: Always
+  tasks:
+  - name: create-cm
+    create:
+      state:
+        kind: ConfigMap
+      replicas: 2
+  - name: assert-cm
+    assert:
+      state:
+        kind: ConfigMap
+      stateCheck:
+        stateCheckOperator: ListCountEquals
+        count: 2
+`,
+		},
+		"invalid types & enums": {
+			recipe: `
+spec:
+  teardown: "yes"
+  enabled:
+    when: Twice
+  tasks:
+  - name: assert-cm
+    assert:
+      state:
+        kind: ConfigMap
+      stateCheck:
+        count: "2"
+`,
+			expectedErrors: []string{
+				`Invalid type: Path "spec.teardown": Want bool got string`,
+				`Invalid value "Twice": Path "spec.enabled.when"`,
+				`Invalid type: Path "spec.tasks.[0].assert.stateCheck.count": Want int got string`,
+			},
+		},
+		"missing name & state": {
+			recipe: `
+spec:
+  tasks:
+  - apply:
+      replicas: 1
+`,
+			expectedErrors: []string{
+				`Missing field: Path "spec.tasks.[0].name"`,
+				`Missing field: Path "spec.tasks.[0].apply.state"`,
+			},
+		},
+		"task with no action & task with many actions": {
+			recipe: `
+spec:
+  tasks:
+  - name: none
+  - name: many
+    create:
+      state:
+        kind: ConfigMap
+    delete:
+      state:
+        kind: ConfigMap
+`,
+			expectedErrors: []string{
+				`Invalid task: Path "spec.tasks.[0]": Want exactly one action got 0 []`,
+				`Invalid task: Path "spec.tasks.[1]": Want exactly one action got 2 [create, delete]`,
+			},
+		},
+		"cross field rules": {
+			recipe: `
+spec:
+  enabled:
+    when: Once
+  schedule:
+    cron: "*/5 * * * *"
+  eligible:
+    checks:
+    - when: ListCountEquals
+  tasks:
+  - name: assert
+    assert:
+      state:
+        kind: ConfigMap
+      pathCheck:
+        path: data.a
+      stateCheck:
+        stateCheckOperator: ListCountNotEquals
+`,
+			expectedErrors: []string{
+				`Invalid schedule: Path "spec.schedule": Can't be used with enabled.when "Once"`,
+				`Invalid assert: Path "spec.tasks.[0].assert": Both stateCheck & pathCheck are set`,
+				`Missing field: Path "spec.tasks.[0].assert.stateCheck": Count is required by stateCheckOperator "ListCountNotEquals"`,
+				`Missing field: Path "spec.eligible.checks.[0]": Count is required by when "ListCountEquals"`,
+			},
+		},
+		"impersonate": {
+			recipe: `
+spec:
+  tasks:
+  - name: as-alice
+    ignoreError: ForbiddenAsPassed
+    impersonate:
+      user: alice
+      groups:
+      - dev
+    create:
+      state:
+        kind: Secret
+  - name: as-alice-and-tester
+    impersonate:
+      user: alice
+      serviceAccount:
+        name: tester
+    create:
+      state:
+        kind: Secret
+  - name: as-nobody
+    impersonate:
+      groups:
+      - dev
+    create:
+      state:
+        kind: Secret
+`,
+			expectedErrors: []string{
+				`Invalid impersonate: Path "spec.tasks.[1].impersonate": Want exactly one identity got 2 [serviceAccount, user]`,
+				`Invalid impersonate: Path "spec.tasks.[2].impersonate": Want exactly one identity got 0 []`,
+			},
+		},
+		"impersonate with service account": {
+			recipe: `
+spec:
+  serviceAccountName: restricted
+  tasks:
+  - name: as-recipe
+    create:
+      state:
+        kind: Secret
+  - name: as-admin
+    impersonate:
+      user: x
+      groups:
+      - system:masters
+    create:
+      state:
+        kind: Secret
+  finally:
+  - name: cleanup-as-admin
+    impersonate:
+      serviceAccount:
+        name: admin
+    delete:
+      state:
+        kind: Secret
+`,
+			expectedErrors: []string{
+				`Invalid impersonate: Path "spec.tasks.[1].impersonate": Can't be used with serviceAccountName`,
+				`Invalid impersonate: Path "spec.finally.[0].impersonate": Can't be used with serviceAccountName`,
+			},
+		},
+		"expect error": {
+			recipe: `
+spec:
+  tasks:
+  - name: create-invalid
+    create:
+      state:
+        kind: ConfigMap
+      expectError:
+        code: 422
+        reason: Invalid
+        messagePattern: "denied by (webhook|policy)"
+  - name: delete-missing
+    delete:
+      state:
+        kind: ConfigMap
+      expectError:
+        code: "404"
+        messagePattern: "not found("
+`,
+			expectedErrors: []string{
+				`Invalid type: Path "spec.tasks.[1].delete.expectError.code": Want int got string`,
+				`Invalid messagePattern: Path "spec.tasks.[1].delete.expectError.messagePattern": error parsing regexp: missing closing ): ` + "`not found(`",
+			},
+		},
+		"onFailure & finally tasks": {
+			recipe: `
+spec:
+  tasks:
+  - name: create-cm
+    create:
+      state:
+        kind: ConfigMap
+  onFailure:
+  - name: assert-cm
+    assert:
+      state:
+        kind: ConfigMap
+      stateCheck:
+        count: "2"
+  finally:
+  - apply:
+      state:
+        kind: ConfigMap
+  - name: none
+`,
+			expectedErrors: []string{
+				`Invalid type: Path "spec.onFailure.[0].assert.stateCheck.count": Want int got string`,
+				`Missing field: Path "spec.finally.[0].name"`,
+				`Invalid task: Path "spec.finally.[1]": Want exactly one action got 0 []`,
+			},
+		},
+		"forEach": {
+			recipe: `
+spec:
+  params:
+    namespaces:
+    - ns-1
+  tasks:
+  - name: create-cm
+    forEach:
+      param: namespaces
+    create:
+      state:
+        kind: ConfigMap
+        metadata:
+          namespace: $(item)
+  - name: label-pods
+    forEach:
+      items:
+      - ns-1
+      list:
+        state:
+          kind: Pod
+    label:
+      state:
+        kind: Pod
+  - name: delete-cm
+    forEach:
+      items: ns-1
+      list: {}
+    delete:
+      state:
+        kind: ConfigMap
+`,
+			expectedErrors: []string{
+				`Invalid type: Path "spec.tasks.[2].forEach.items": Want list got string`,
+				`Missing field: Path "spec.tasks.[2].forEach.list.state"`,
+				`Invalid forEach: Path "spec.tasks.[1].forEach": Want exactly one source of items got 2 [items, list]`,
+				`Invalid forEach: Path "spec.tasks.[2].forEach": Want exactly one source of items got 2 [items, list]`,
+			},
+		},
+		"dependsOn": {
+			recipe: `
+spec:
+  dependsOn:
+  - name: setup
+  - name: setup
+    phase: Running
+  - name: setup
+    labelSelector:
+      matchLabels:
+        app: setup
+  - labelSelector:
+      matchExpressions:
+      - key: app
+        operator: Equal
+  tasks:
+  - name: create-cm
+    create:
+      state:
+        kind: ConfigMap
+`,
+			expectedErrors: []string{
+				`Invalid value "Running": Path "spec.dependsOn.[1].phase"`,
+				`Invalid value "Equal": Path "spec.dependsOn.[3].labelSelector.matchExpressions.[0].operator"`,
+				`Invalid dependency: Path "spec.dependsOn.[2]": Want exactly one selector got 2 [labelSelector, name]`,
+			},
+		},
+		"rerunPolicy": {
+			recipe: `
+spec:
+  enabled:
+    when: Always
+    rerunPolicy: OnChange
+  tasks:
+  - name: create-cm
+    create:
+      state:
+        kind: ConfigMap
+`,
+			expectedErrors: []string{
+				`Invalid value "OnChange": Path "spec.enabled.rerunPolicy"`,
+				`Invalid rerunPolicy: Path "spec.enabled.rerunPolicy": Can't be used with when "Always"`,
+			},
+		},
+		"approval": {
+			recipe: `
+spec:
+  tasks:
+  - name: approve-expand
+    approval:
+      timeoutInSeconds: 3600
+  - name: approve-shrink
+    approval:
+      configMap:
+        name: approvals
+  finally:
+  - name: approve-cleanup
+    approval: {}
+`,
+			expectedErrors: []string{
+				`Missing field: Path "spec.tasks.[1].approval.configMap.key"`,
+				`Invalid task: Path "spec.finally.[0]": Approval is not supported`,
+			},
+		},
+		"include": {
+			recipe: `
+spec:
+  tasks:
+  - name: setup
+    include:
+      template: namespace-setup
+      params:
+        namespace: ns-1
+  - name: cleanup
+    include:
+      params: []
+`,
+			expectedErrors: []string{
+				`Missing field: Path "spec.tasks.[1].include.template"`,
+				`Invalid type: Path "spec.tasks.[1].include.params": Want map got list`,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var obj map[string]interface{}
+			err := yaml.Unmarshal([]byte(mock.recipe), &obj)
+			if err != nil {
+				t.Fatalf("Invalid test data: %s", err.Error())
+			}
+			got := ValidateSchemaValues(obj)
+			var gotErrors []string
+			for _, f := range got.Failures {
+				gotErrors = append(gotErrors, f.Error)
+				if f.Remedy == "" {
+					t.Fatalf("Expected remedy got none: %q", f.Error)
+				}
+			}
+			if diff := cmp.Diff(mock.expectedErrors, gotErrors); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+			expectedStatus := schema.FieldPathValidationStatusValid
+			if len(mock.expectedErrors) != 0 {
+				expectedStatus = schema.FieldPathValidationStatusInvalid
+			}
+			if got.Status != expectedStatus {
+				t.Fatalf("Expected status %q got %q", expectedStatus, got.Status)
+			}
+		})
+	}
+}
+
+func TestValidateTemplateSchemaValues(t *testing.T) {
+	var tests = map[string]struct {
+		template       string
+		expectedErrors []string
+	}{
+		"valid template": {
+			template: `
+apiVersion: dope.mayadata.io/v1
+kind: RecipeTemplate
+spec:
+  params:
+  - name: namespace
+  tasks:
+  - name: create-ns
+    create:
+      state:
+        kind: Namespace
+        metadata:
+          name: $(params.namespace)
+`,
+		},
+		"invalid template": {
+			template: `
+spec:
+  params:
+  - description: no name
+  tasks:
+  - name: none
+`,
+			expectedErrors: []string{
+				`Missing field: Path "spec.params.[0].name"`,
+				`Invalid task: Path "spec.tasks.[0]": Want exactly one action got 0 []`,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var obj map[string]interface{}
+			err := yaml.Unmarshal([]byte(mock.template), &obj)
+			if err != nil {
+				t.Fatalf("Invalid test data: %s", err.Error())
+			}
+			got := ValidateTemplateSchemaValues(obj)
+			var gotErrors []string
+			for _, f := range got.Failures {
+				gotErrors = append(gotErrors, f.Error)
+			}
+			if diff := cmp.Diff(mock.expectedErrors, gotErrors); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
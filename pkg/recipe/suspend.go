@@ -0,0 +1,78 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// isSuspended returns true if further runs of this Recipe are
+// stopped
+func (r *Runner) isSuspended() bool {
+	return r.Recipe.Spec.Suspend != nil && *r.Recipe.Spec.Suspend
+}
+
+// initResume restores the phase of this Recipe prior to its
+// suspension if this Recipe was resumed
+//
+// NOTE:
+//	Observed status is restored so that this Recipe continues from
+// where it was suspended e.g. a Recipe that was meant to be run once
+// & had completed prior to its suspension continues to be locked
+func (r *Runner) initResume() {
+	if r.isSuspended() || r.Recipe.Status.Phase != types.RecipeStatusSuspended {
+		return
+	}
+	r.isResumed = true
+	var previous types.SuspensionStatus
+	if r.Recipe.Status.Suspension != nil {
+		previous = *r.Recipe.Status.Suspension
+	}
+	r.Recipe.Status.Phase = previous.Phase
+	r.Recipe.Status.Reason = previous.Reason
+	r.Recipe.Status.Message = previous.Message
+	r.Recipe.Status.Suspension = nil
+}
+
+// suspend updates the Recipe status to Suspended without running
+// this Recipe. Rest of the observed status is retained.
+func (r *Runner) suspend() (types.RecipeStatus, error) {
+	klog.V(3).Infof(
+		"Will skip execution: Suspended: Recipe %q / %q",
+		r.Recipe.GetNamespace(),
+		r.Recipe.GetName(),
+	)
+	status := r.Recipe.Status
+	if status.Phase != types.RecipeStatusSuspended {
+		now := metav1.Now()
+		// record the phase prior to this suspension
+		status.Suspension = &types.SuspensionStatus{
+			SuspendTime: &now,
+			Phase:       status.Phase,
+			Reason:      status.Reason,
+			Message:     status.Message,
+		}
+	}
+	status.Phase = types.RecipeStatusSuspended
+	status.Reason = "Suspended via spec.suspend"
+	status.Message = "Remedy: Set spec.suspend to false to resume"
+	r.RecipeStatus = &status
+	return status, r.updateRecipeWithRetries()
+}
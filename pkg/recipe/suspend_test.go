@@ -0,0 +1,322 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+func TestRunnerRunWhenSuspended(t *testing.T) {
+	var suspend = true
+	var suspendTime = metav1.Now()
+	var tests = map[string]struct {
+		observed           types.RecipeStatus
+		expectedSuspension types.SuspensionStatus
+		isNewSuspension    bool
+	}{
+		"suspend a passed recipe": {
+			observed: types.RecipeStatus{
+				Phase:  types.RecipeStatusPassed,
+				Reason: "all good",
+				History: []types.RecipeRunSummary{
+					{Phase: types.RecipeStatusPassed},
+				},
+				Lock: &types.LockStatus{
+					HolderIdentity: "pod-1",
+				},
+			},
+			expectedSuspension: types.SuspensionStatus{
+				Phase:  types.RecipeStatusPassed,
+				Reason: "all good",
+			},
+			isNewSuspension: true,
+		},
+		"suspend a recipe that has not run yet": {
+			isNewSuspension: true,
+		},
+		"recipe is already suspended": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusSuspended,
+				Suspension: &types.SuspensionStatus{
+					SuspendTime: &suspendTime,
+					Phase:       types.RecipeStatusFailed,
+				},
+			},
+			expectedSuspension: types.SuspensionStatus{
+				SuspendTime: &suspendTime,
+				Phase:       types.RecipeStatusFailed,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var updated int
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						Suspend: &suspend,
+						Tasks:   []types.Task{{Name: "one"}},
+					},
+					Status: mock.observed,
+				},
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: map[string]types.TaskResult{},
+				},
+				fixture: &Fixture{
+					BaseFixture: NoopFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					updated++
+					return nil
+				},
+			}
+			got, err := r.Run() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if updated != 1 {
+				t.Fatalf("Expected 1 update got %d", updated)
+			}
+			if got.Phase != types.RecipeStatusSuspended {
+				t.Fatalf(
+					"Expected phase %q got %q",
+					types.RecipeStatusSuspended,
+					got.Phase,
+				)
+			}
+			if len(got.History) != len(mock.observed.History) {
+				t.Fatalf(
+					"Expected %d history records got %d",
+					len(mock.observed.History),
+					len(got.History),
+				)
+			}
+			if (got.Lock == nil) != (mock.observed.Lock == nil) {
+				t.Fatalf("Expected lock %v got %v", mock.observed.Lock, got.Lock)
+			}
+			if got.Suspension == nil {
+				t.Fatalf("Expected suspension got none")
+			}
+			if got.Suspension.Phase != mock.expectedSuspension.Phase ||
+				got.Suspension.Reason != mock.expectedSuspension.Reason {
+				t.Fatalf(
+					"Expected suspension %v got %v",
+					mock.expectedSuspension,
+					*got.Suspension,
+				)
+			}
+			if mock.isNewSuspension && got.Suspension.SuspendTime == nil {
+				t.Fatalf("Expected suspend time got none")
+			}
+			if !mock.isNewSuspension &&
+				!got.Suspension.SuspendTime.Equal(mock.expectedSuspension.SuspendTime) {
+				t.Fatalf(
+					"Expected suspend time %s got %s",
+					mock.expectedSuspension.SuspendTime,
+					got.Suspension.SuspendTime,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerInitResume(t *testing.T) {
+	var suspend = true
+	var resume = false
+	var tests = map[string]struct {
+		suspend         *bool
+		observed        types.RecipeStatus
+		expectedPhase   types.RecipeStatusPhase
+		expectedReason  string
+		expectedResumed bool
+	}{
+		"recipe is not suspended": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusPassed,
+			},
+			expectedPhase: types.RecipeStatusPassed,
+		},
+		"recipe continues to be suspended": {
+			suspend: &suspend,
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusSuspended,
+				Suspension: &types.SuspensionStatus{
+					Phase: types.RecipeStatusCompleted,
+				},
+			},
+			expectedPhase: types.RecipeStatusSuspended,
+		},
+		"recipe is resumed": {
+			suspend: &resume,
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusSuspended,
+				Suspension: &types.SuspensionStatus{
+					Phase:  types.RecipeStatusCompleted,
+					Reason: "done",
+				},
+			},
+			expectedPhase:   types.RecipeStatusCompleted,
+			expectedReason:  "done",
+			expectedResumed: true,
+		},
+		"recipe is resumed without suspension details": {
+			observed: types.RecipeStatus{
+				Phase: types.RecipeStatusSuspended,
+			},
+			expectedResumed: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						Suspend: mock.suspend,
+					},
+					Status: mock.observed,
+				},
+			}
+			r.initResume() // method under test
+			if r.isResumed != mock.expectedResumed {
+				t.Fatalf(
+					"Expected resumed %t got %t",
+					mock.expectedResumed,
+					r.isResumed,
+				)
+			}
+			if r.Recipe.Status.Phase != mock.expectedPhase {
+				t.Fatalf(
+					"Expected phase %q got %q",
+					mock.expectedPhase,
+					r.Recipe.Status.Phase,
+				)
+			}
+			if r.Recipe.Status.Reason != mock.expectedReason {
+				t.Fatalf(
+					"Expected reason %q got %q",
+					mock.expectedReason,
+					r.Recipe.Status.Reason,
+				)
+			}
+			if mock.expectedResumed && r.Recipe.Status.Suspension != nil {
+				t.Fatalf(
+					"Expected no suspension got %v",
+					*r.Recipe.Status.Suspension,
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerRunAfterResumeWithSchedule(t *testing.T) {
+	var resume = false
+	var hour = time.Now().UTC().Truncate(time.Hour)
+	var suspendTime = metav1.NewTime(hour.Add(-150 * time.Minute))
+	var lastScheduleTime = metav1.NewTime(hour.Add(-3 * time.Hour))
+	var tests = map[string]struct {
+		policy types.MissedRunPolicy
+	}{
+		"resume with skip policy": {
+			policy: types.MissedRunPolicySkip,
+		},
+		"resume with run once policy": {
+			policy: types.MissedRunPolicyRunOnce,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: types.RecipeSpec{
+						Suspend: &resume,
+						Schedule: &types.Schedule{
+							Cron:            "0 * * * *",
+							MissedRunPolicy: mock.policy,
+						},
+						Tasks: []types.Task{{Name: "one"}},
+					},
+					Status: types.RecipeStatus{
+						Phase: types.RecipeStatusSuspended,
+						Suspension: &types.SuspensionStatus{
+							SuspendTime: &suspendTime,
+							Phase:       types.RecipeStatusCompleted,
+						},
+						Schedule: &types.ScheduleStatus{
+							LastScheduleTime: &lastScheduleTime,
+						},
+					},
+				},
+				RecipeStatus: &types.RecipeStatus{
+					TaskResults: map[string]types.TaskResult{},
+				},
+				fixture: &Fixture{
+					BaseFixture: NoopFixture,
+				},
+				UpdateRecipeWithRetriesFn: func() error {
+					// update recipe function is mocked
+					return nil
+				},
+			}
+			got, err := r.Run() // method under test
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if got.Phase != types.RecipeStatusCompleted {
+				t.Fatalf(
+					"Expected phase %q got %q",
+					types.RecipeStatusCompleted,
+					got.Phase,
+				)
+			}
+			if len(got.TaskResults) != 0 {
+				t.Fatalf("Expected no run got %v", got.TaskResults)
+			}
+			if got.Schedule == nil {
+				t.Fatalf("Expected schedule status got none")
+			}
+			if got.Schedule.MissedRuns != 0 {
+				t.Fatalf("Expected no missed runs got %d", got.Schedule.MissedRuns)
+			}
+			if got.Schedule.LastScheduleTime.Before(&suspendTime) {
+				t.Fatalf(
+					"Expected last schedule time after suspension got %s",
+					got.Schedule.LastScheduleTime,
+				)
+			}
+			if !got.Schedule.NextScheduleTime.After(time.Now()) {
+				t.Fatalf(
+					"Expected next schedule time in future got %s",
+					got.Schedule.NextScheduleTime,
+				)
+			}
+		})
+	}
+}
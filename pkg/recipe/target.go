@@ -0,0 +1,419 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"k8s.io/klog/v2"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+const (
+	// targetAPIDiscoveryInterval is the interval at which the apis
+	// of a target cluster are discovered. In other words, CRDs that
+	// are applied in the target cluster will take this interval to
+	// be discovered.
+	targetAPIDiscoveryInterval = 30 * time.Second
+
+	// targetAPIDiscoveryTimeout is used if the kubeconfig of the
+	// target cluster does not set any timeout
+	targetAPIDiscoveryTimeout = 10 * time.Second
+
+	// targetAPIDiscoveryBackoff is the initial duration during which
+	// the discovery of an unreachable target cluster is not retried.
+	// This doubles after every failed discovery.
+	targetAPIDiscoveryBackoff = 10 * time.Second
+
+	// targetAPIDiscoveryMaxBackoff is the maximum duration during
+	// which the discovery of an unreachable target cluster is not
+	// retried
+	targetAPIDiscoveryMaxBackoff = 5 * time.Minute
+)
+
+// targetCluster has the details to connect to a target cluster
+type targetCluster struct {
+	// resource version of the Secret from which these details
+	// were built
+	secretResourceVersion string
+
+	kubeConfig   *rest.Config
+	apiDiscovery *dynamicdiscovery.APIResourceDiscovery
+}
+
+// targetClusterEntry is the cached entry of a target cluster
+//
+// NOTE:
+//	Every entry has its own mutex so that discovering the apis of
+// one target cluster does not block the Recipes that refer to other
+// target clusters
+type targetClusterEntry struct {
+	mutex   sync.Mutex
+	cluster *targetCluster
+
+	// details of the last failed discovery
+	failedVersion string
+	failedAt      time.Time
+	failures      int
+	failure       error
+}
+
+// backoff returns the duration during which the discovery of this
+// target cluster is not retried
+func (e *targetClusterEntry) backoff() time.Duration {
+	backoff := targetAPIDiscoveryBackoff
+	for i := 1; i < e.failures && backoff < targetAPIDiscoveryMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > targetAPIDiscoveryMaxBackoff {
+		backoff = targetAPIDiscoveryMaxBackoff
+	}
+	return backoff
+}
+
+// targetClusterCache caches the details of target clusters
+//
+// NOTE:
+//	This avoids discovering the apis of a target cluster during
+// every reconciliation of the Recipe(s) that refer to it
+type targetClusterCache struct {
+	// mutex guards the entries only & is never held during
+	// discovery
+	mutex   sync.Mutex
+	entries map[string]*targetClusterEntry
+
+	// functions useful to mock during unit tests
+	newAPIDiscoveryFn func(*rest.Config) (*dynamicdiscovery.APIResourceDiscovery, error)
+	nowFn             func() time.Time
+}
+
+// targetClusters is the cache used by all Recipes
+var targetClusters = &targetClusterCache{
+	entries:           map[string]*targetClusterEntry{},
+	newAPIDiscoveryFn: newTargetAPIDiscovery,
+	nowFn:             time.Now,
+}
+
+// newTargetAPIDiscovery returns a started api discovery of the
+// cluster referred to by the provided config
+func newTargetAPIDiscovery(config *rest.Config) (*dynamicdiscovery.APIResourceDiscovery, error) {
+	discoveryConfig := rest.CopyConfig(config)
+	if discoveryConfig.Timeout == 0 {
+		// an unreachable cluster should not block discovery forever
+		discoveryConfig.Timeout = targetAPIDiscoveryTimeout
+	}
+	client, err := discovery.NewDiscoveryClientForConfig(discoveryConfig)
+	if err != nil {
+		return nil, err
+	}
+	d := dynamicdiscovery.NewAPIResourceDiscoverer(client)
+	d.Start(targetAPIDiscoveryInterval)
+
+	// loop for a while till resources are discovered
+	var count int
+	for {
+		if d.HasSynced() || count >= 5 {
+			break
+		}
+		time.Sleep(1 * time.Second)
+		count++
+	}
+	if !d.HasSynced() {
+		// stop in the background since stop waits for an
+		// ongoing discovery to complete
+		go d.Stop()
+		return nil, errors.Errorf(
+			"API discovery did not sync: Host %q",
+			config.Host,
+		)
+	}
+	return d, nil
+}
+
+// validateTargetKubeConfig verifies if the provided kubeconfig sets
+// its credentials inline
+//
+// NOTE:
+//	Kubeconfig is read from a Secret that can be created by the
+// users of a namespace. Plugins & files referred to by kubeconfig
+// are not allowed since these would run commands or read files
+// e.g. the token of this operator from the operator's pod.
+func validateTargetKubeConfig(config *clientcmdapi.Config) error {
+	for name, user := range config.AuthInfos {
+		for field, isSet := range map[string]bool{
+			"exec":               user.Exec != nil,
+			"auth-provider":      user.AuthProvider != nil,
+			"tokenFile":          user.TokenFile != "",
+			"client-certificate": user.ClientCertificate != "",
+			"client-key":         user.ClientKey != "",
+		} {
+			if isSet {
+				return errors.Errorf(
+					"Unsupported kubeconfig: User %q: Field %q is not allowed: Set credentials inline",
+					name,
+					field,
+				)
+			}
+		}
+	}
+	for name, cluster := range config.Clusters {
+		if cluster.CertificateAuthority != "" {
+			return errors.Errorf(
+				"Unsupported kubeconfig: Cluster %q: Field %q is not allowed: Set %q instead",
+				name,
+				"certificate-authority",
+				"certificate-authority-data",
+			)
+		}
+	}
+	return nil
+}
+
+// restConfigFromTargetKubeConfig returns the rest config from the
+// provided kubeconfig of a target cluster
+//
+// NOTE:
+//	Kubeconfig is validated before building the rest config since
+// building reads the files referred to by the kubeconfig
+func restConfigFromTargetKubeConfig(kubeconfig []byte) (*rest.Config, error) {
+	raw, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		// parse error is not wrapped since it may quote the kubeconfig
+		return nil, errors.Errorf("Invalid kubeconfig")
+	}
+	err = validateTargetKubeConfig(raw)
+	if err != nil {
+		return nil, err
+	}
+	config, err := clientcmd.NewDefaultClientConfig(
+		*raw,
+		&clientcmd.ConfigOverrides{},
+	).ClientConfig()
+	if err != nil {
+		// error is not wrapped since it may quote the kubeconfig
+		return nil, errors.Errorf("Invalid kubeconfig")
+	}
+	// verify the resulting config as well in case the kubeconfig
+	// had other ways to refer to plugins or files
+	if config.ExecProvider != nil ||
+		config.AuthProvider != nil ||
+		config.BearerTokenFile != "" ||
+		config.CertFile != "" ||
+		config.KeyFile != "" ||
+		config.CAFile != "" {
+		return nil, errors.Errorf(
+			"Unsupported kubeconfig: Plugins & files are not allowed: Set credentials inline",
+		)
+	}
+	return config, nil
+}
+
+// entry returns the cached entry of the provided id. A new entry
+// is added if none exists.
+func (c *targetClusterCache) entry(id string) *targetClusterEntry {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	e := c.entries[id]
+	if e == nil {
+		e = &targetClusterEntry{}
+		c.entries[id] = e
+	}
+	return e
+}
+
+// forget removes the entries of the provided Secret & stops their
+// api discoveries
+//
+// NOTE:
+//	This is invoked once the Secret is found to be deleted
+func (c *targetClusterCache) forget(namespace, name string) {
+	prefix := fmt.Sprintf("%s/%s/", namespace, name)
+
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	for id, e := range c.entries {
+		if !strings.HasPrefix(id, prefix) {
+			continue
+		}
+		delete(c.entries, id)
+		go func(e *targetClusterEntry) {
+			// stop in the background since an ongoing discovery
+			// of this entry holds its lock
+			e.mutex.Lock()
+			defer e.mutex.Unlock()
+			if e.cluster != nil && e.cluster.apiDiscovery != nil {
+				e.cluster.apiDiscovery.Stop()
+			}
+		}(e)
+		klog.V(2).Infof("Removed target cluster: Secret %q / %q", namespace, name)
+	}
+}
+
+// get returns the details of the target cluster whose kubeconfig
+// is found at the provided key of the provided Secret
+//
+// NOTE:
+//	Cached details are rebuilt if the Secret was updated
+//
+// NOTE:
+//	A failed discovery is not retried till its backoff expires
+// unless the Secret is updated
+//
+// NOTE:
+//	Errors never include the contents of the kubeconfig since these
+// errors end up in the status of the Recipe
+func (c *targetClusterCache) get(secret *corev1.Secret, key string) (*targetCluster, error) {
+	id := fmt.Sprintf("%s/%s/%s", secret.GetNamespace(), secret.GetName(), key)
+
+	e := c.entry(id)
+	e.mutex.Lock()
+	defer e.mutex.Unlock()
+
+	cached := e.cluster
+	if cached != nil && cached.secretResourceVersion == secret.GetResourceVersion() {
+		return cached, nil
+	}
+	if e.failure != nil &&
+		e.failedVersion == secret.GetResourceVersion() &&
+		c.nowFn().Sub(e.failedAt) < e.backoff() {
+		return nil, errors.Wrapf(
+			e.failure,
+			"Will retry discovery after %s",
+			e.backoff(),
+		)
+	}
+	kubeconfig, found := secret.Data[key]
+	if !found || len(kubeconfig) == 0 {
+		return nil, errors.Errorf(
+			"Missing kubeconfig: Key %q: Secret %q / %q",
+			key,
+			secret.GetNamespace(),
+			secret.GetName(),
+		)
+	}
+	config, err := restConfigFromTargetKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Key %q: Secret %q / %q",
+			key,
+			secret.GetNamespace(),
+			secret.GetName(),
+		)
+	}
+	apiDiscovery, err := c.newAPIDiscoveryFn(config)
+	if err != nil {
+		err = errors.Wrapf(
+			err,
+			"Discover target cluster apis failed: Secret %q / %q",
+			secret.GetNamespace(),
+			secret.GetName(),
+		)
+		if e.failedVersion != secret.GetResourceVersion() {
+			// backoff restarts if the Secret was updated
+			e.failures = 0
+		}
+		e.failedVersion = secret.GetResourceVersion()
+		e.failedAt = c.nowFn()
+		e.failures++
+		e.failure = err
+		return nil, err
+	}
+	if cached != nil && cached.apiDiscovery != nil {
+		// discovery based on the previous kubeconfig is no longer used
+		go cached.apiDiscovery.Stop()
+	}
+	klog.V(2).Infof(
+		"Discovered target cluster apis: Host %q: Secret %q / %q: Version %q",
+		config.Host,
+		secret.GetNamespace(),
+		secret.GetName(),
+		secret.GetResourceVersion(),
+	)
+	e.cluster = &targetCluster{
+		secretResourceVersion: secret.GetResourceVersion(),
+		kubeConfig:            config,
+		apiDiscovery:          apiDiscovery,
+	}
+	e.failedVersion, e.failures, e.failure = "", 0, nil
+	return e.cluster, nil
+}
+
+// initTargetFixture sets the fixture that is used to run the tasks
+// of this Recipe against its target cluster
+//
+// NOTE:
+//	The Secret with the kubeconfig is read from the cluster that
+// has this Recipe
+func (r *Runner) initTargetFixture() {
+	target := r.Recipe.Spec.TargetCluster
+	if target == nil || r.tasksFixture != nil {
+		// no further action required
+		return
+	}
+	var key = target.Key
+	if key == "" {
+		key = types.DefaultTargetClusterKey
+	}
+	secret, err := r.fixture.kubeClientset.CoreV1().
+		Secrets(r.Recipe.GetNamespace()).
+		Get(target.SecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		// cached details of a deleted Secret are no longer valid
+		targetClusters.forget(r.Recipe.GetNamespace(), target.SecretName)
+	}
+	if err != nil {
+		r.err = errors.Wrapf(
+			err,
+			"Get target cluster secret failed: Recipe %q / %q",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		return
+	}
+	cluster, err := targetClusters.get(secret, key)
+	if err != nil {
+		r.err = errors.Wrapf(
+			err,
+			"Init target cluster failed: Recipe %q / %q",
+			r.Recipe.GetNamespace(),
+			r.Recipe.GetName(),
+		)
+		return
+	}
+	r.tasksFixture, r.err = NewFixture(FixtureConfig{
+		KubeConfig:   cluster.kubeConfig,
+		APIDiscovery: cluster.apiDiscovery,
+		IsTearDown:   r.isTearDown,
+	})
+}
+
@@ -0,0 +1,458 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+const testKubeConfig = `
+apiVersion: v1
+kind: Config
+clusters:
+- name: workload
+  cluster:
+    server: https://10.0.0.1:6443
+users:
+- name: admin
+  user:
+    token: top-secret-token
+contexts:
+- name: workload
+  context:
+    cluster: workload
+    user: admin
+current-context: workload
+`
+
+// withTestUser returns the test kubeconfig with its user credentials
+// replaced by the provided ones
+func withTestUser(user string) []byte {
+	return []byte(
+		strings.Replace(testKubeConfig, "    token: top-secret-token\n", user, 1),
+	)
+}
+
+func newTestTargetClusterCache(discoveries *int) *targetClusterCache {
+	return &targetClusterCache{
+		entries: map[string]*targetClusterEntry{},
+		nowFn:   time.Now,
+		newAPIDiscoveryFn: func(*rest.Config) (*dynamicdiscovery.APIResourceDiscovery, error) {
+			*discoveries++
+			d := dynamicdiscovery.NewAPIResourceDiscoverer(
+				fake.NewSimpleClientset().Discovery(),
+			)
+			// started since a replaced discovery gets stopped
+			d.Start(time.Minute)
+			return d, nil
+		},
+	}
+}
+
+func TestTargetClusterCacheGet(t *testing.T) {
+	var tests = map[string]struct {
+		data                map[string][]byte
+		key                 string
+		resourceVersions    []string
+		expectedDiscoveries int
+		isErr               bool
+		errField            string
+	}{
+		"cached till secret is updated": {
+			data:                map[string][]byte{"kubeconfig": []byte(testKubeConfig)},
+			key:                 "kubeconfig",
+			resourceVersions:    []string{"1", "1", "2", "2"},
+			expectedDiscoveries: 2,
+		},
+		"custom key": {
+			data:                map[string][]byte{"config": []byte(testKubeConfig)},
+			key:                 "config",
+			resourceVersions:    []string{"1"},
+			expectedDiscoveries: 1,
+		},
+		"missing key": {
+			data:             map[string][]byte{"config": []byte(testKubeConfig)},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+		},
+		"invalid kubeconfig": {
+			data: map[string][]byte{
+				"kubeconfig": []byte("token: top-secret-token\n\tjunk"),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+		},
+		"inline credentials": {
+			data: map[string][]byte{
+				"kubeconfig": []byte(strings.Replace(
+					string(withTestUser(
+						"    client-certificate-data: Y2VydA==\n"+
+							"    client-key-data: a2V5\n",
+					)),
+					"    server: https://10.0.0.1:6443\n",
+					"    server: https://10.0.0.1:6443\n"+
+						"    certificate-authority-data: Y2E=\n",
+					1,
+				)),
+			},
+			key:                 "kubeconfig",
+			resourceVersions:    []string{"1"},
+			expectedDiscoveries: 1,
+		},
+		"exec plugin": {
+			data: map[string][]byte{
+				"kubeconfig": withTestUser(
+					"    exec:\n" +
+						"      apiVersion: client.authentication.k8s.io/v1alpha1\n" +
+						"      command: cat\n" +
+						"      args: [\"/etc/passwd\"]\n",
+				),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+			errField:         "exec",
+		},
+		"auth provider": {
+			data: map[string][]byte{
+				"kubeconfig": withTestUser(
+					"    auth-provider:\n" +
+						"      name: gcp\n",
+				),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+			errField:         "auth-provider",
+		},
+		"token file": {
+			data: map[string][]byte{
+				"kubeconfig": withTestUser(
+					"    tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token\n",
+				),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+			errField:         "tokenFile",
+		},
+		"client certificate file": {
+			data: map[string][]byte{
+				"kubeconfig": withTestUser(
+					"    client-certificate: /etc/dope/tls.crt\n",
+				),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+			errField:         "client-certificate",
+		},
+		"client key file": {
+			data: map[string][]byte{
+				"kubeconfig": withTestUser(
+					"    client-key: /etc/dope/tls.key\n",
+				),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+			errField:         "client-key",
+		},
+		"certificate authority file": {
+			data: map[string][]byte{
+				"kubeconfig": []byte(strings.Replace(
+					testKubeConfig,
+					"    server: https://10.0.0.1:6443\n",
+					"    server: https://10.0.0.1:6443\n"+
+						"    certificate-authority: /var/run/secrets/kubernetes.io/serviceaccount/ca.crt\n",
+					1,
+				)),
+			},
+			key:              "kubeconfig",
+			resourceVersions: []string{"1"},
+			isErr:            true,
+			errField:         "certificate-authority",
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var discoveries int
+			cache := newTestTargetClusterCache(&discoveries)
+			for _, version := range mock.resourceVersions {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace:       "ns",
+						Name:            "workload",
+						ResourceVersion: version,
+					},
+					Data: mock.data,
+				}
+				got, err := cache.get(secret, mock.key)
+				if mock.isErr {
+					if err == nil {
+						t.Fatalf("Expected error got none")
+					}
+					if strings.Contains(err.Error(), "top-secret-token") {
+						t.Fatalf("Expected error without credentials got %s", err.Error())
+					}
+					if !strings.Contains(err.Error(), mock.errField) {
+						t.Fatalf(
+							"Expected error with field %q got %s",
+							mock.errField,
+							err.Error(),
+						)
+					}
+					if discoveries != 0 {
+						t.Fatalf("Expected no discoveries got %d", discoveries)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Expected no error got %+v", err)
+				}
+				if got.kubeConfig.Host != "https://10.0.0.1:6443" {
+					t.Fatalf("Expected target host got %q", got.kubeConfig.Host)
+				}
+			}
+			if discoveries != mock.expectedDiscoveries {
+				t.Fatalf(
+					"Expected %d discoveries got %d",
+					mock.expectedDiscoveries,
+					discoveries,
+				)
+			}
+		})
+	}
+}
+
+func TestTargetClusterCacheGetWithFailedDiscovery(t *testing.T) {
+	var now = time.Now()
+	var discoveries int
+	cache := newTestTargetClusterCache(&discoveries)
+	cache.nowFn = func() time.Time { return now }
+	cache.newAPIDiscoveryFn = func(*rest.Config) (*dynamicdiscovery.APIResourceDiscovery, error) {
+		discoveries++
+		return nil, errors.New("connection refused")
+	}
+	var steps = []struct {
+		version             string
+		elapsed             time.Duration
+		expectedDiscoveries int
+	}{
+		// first discovery fails
+		{version: "1", expectedDiscoveries: 1},
+		// backoff is honoured
+		{version: "1", elapsed: 5 * time.Second, expectedDiscoveries: 1},
+		// retried after backoff
+		{version: "1", elapsed: 11 * time.Second, expectedDiscoveries: 2},
+		// backoff is doubled
+		{version: "1", elapsed: 11 * time.Second, expectedDiscoveries: 2},
+		{version: "1", elapsed: 21 * time.Second, expectedDiscoveries: 3},
+		// retried immediately if secret is updated
+		{version: "2", expectedDiscoveries: 4},
+	}
+	for idx, step := range steps {
+		now = now.Add(step.elapsed)
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns",
+				Name:            "workload",
+				ResourceVersion: step.version,
+			},
+			Data: map[string][]byte{"kubeconfig": []byte(testKubeConfig)},
+		}
+		_, err := cache.get(secret, "kubeconfig")
+		if err == nil {
+			t.Fatalf("Expected error at step %d got none", idx)
+		}
+		if discoveries != step.expectedDiscoveries {
+			t.Fatalf(
+				"Expected %d discoveries at step %d got %d",
+				step.expectedDiscoveries,
+				idx,
+				discoveries,
+			)
+		}
+	}
+}
+
+func TestTargetClusterCacheGetIsNotBlockedByOtherClusters(t *testing.T) {
+	var discoveries int
+	cache := newTestTargetClusterCache(&discoveries)
+	discover := cache.newAPIDiscoveryFn
+	unblock := make(chan struct{})
+	cache.newAPIDiscoveryFn = func(config *rest.Config) (*dynamicdiscovery.APIResourceDiscovery, error) {
+		if config.Host == "https://10.0.0.2:6443" {
+			// unreachable cluster blocks its discovery
+			<-unblock
+			return nil, errors.New("timeout")
+		}
+		return discover(config)
+	}
+	newSecret := func(name, host string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:       "ns",
+				Name:            name,
+				ResourceVersion: "1",
+			},
+			Data: map[string][]byte{
+				"kubeconfig": []byte(
+					strings.Replace(testKubeConfig, "https://10.0.0.1:6443", host, 1),
+				),
+			},
+		}
+	}
+	blocked := make(chan error)
+	go func() {
+		_, err := cache.get(newSecret("unreachable", "https://10.0.0.2:6443"), "kubeconfig")
+		blocked <- err
+	}()
+	done := make(chan error)
+	go func() {
+		_, err := cache.get(newSecret("workload", "https://10.0.0.1:6443"), "kubeconfig")
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("Expected no error got %+v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Expected discovery not to be blocked by another cluster")
+	}
+	close(unblock)
+	if err := <-blocked; err == nil {
+		t.Fatalf("Expected error from unreachable cluster got none")
+	}
+}
+
+func TestRunnerInitTargetFixture(t *testing.T) {
+	var tests = map[string]struct {
+		target          *types.TargetCluster
+		secrets         []corev1.Secret
+		cached          []string
+		expectedCached  int
+		isTargetFixture bool
+		isErr           bool
+	}{
+		"no target cluster": {},
+		"target cluster with default key": {
+			target: &types.TargetCluster{SecretName: "workload"},
+			secrets: []corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "workload"},
+					Data:       map[string][]byte{"kubeconfig": []byte(testKubeConfig)},
+				},
+			},
+			isTargetFixture: true,
+		},
+		"secret in another namespace": {
+			target: &types.TargetCluster{SecretName: "workload"},
+			secrets: []corev1.Secret{
+				{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "workload"},
+					Data:       map[string][]byte{"kubeconfig": []byte(testKubeConfig)},
+				},
+			},
+			isErr: true,
+		},
+		"deleted secret is removed from cache": {
+			target: &types.TargetCluster{SecretName: "workload"},
+			cached: []string{
+				"ns/workload/kubeconfig",
+				"ns/workload/config",
+				"ns/other/kubeconfig",
+			},
+			expectedCached: 1,
+			isErr:          true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			var discoveries int
+			original := targetClusters
+			targetClusters = newTestTargetClusterCache(&discoveries)
+			defer func() { targetClusters = original }()
+			for _, id := range mock.cached {
+				targetClusters.entry(id)
+			}
+
+			client := fake.NewSimpleClientset()
+			for i := range mock.secrets {
+				_, err := client.CoreV1().
+					Secrets(mock.secrets[i].Namespace).
+					Create(&mock.secrets[i])
+				if err != nil {
+					t.Fatalf("Expected no error got %+v", err)
+				}
+			}
+			local := &Fixture{BaseFixture: &BaseFixture{}, kubeClientset: client}
+			r := &Runner{
+				Recipe: types.Recipe{
+					ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "recipe"},
+					Spec:       types.RecipeSpec{TargetCluster: mock.target},
+				},
+				fixture: local,
+			}
+			r.initTargetFixture()
+			if len(targetClusters.entries) != mock.expectedCached &&
+				len(mock.cached) != 0 {
+				t.Fatalf(
+					"Expected %d cached entries got %d",
+					mock.expectedCached,
+					len(targetClusters.entries),
+				)
+			}
+			if mock.isErr {
+				if r.err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if r.err != nil {
+				t.Fatalf("Expected no error got %+v", r.err)
+			}
+			if mock.isTargetFixture == (r.getTasksFixture() == local) {
+				t.Fatalf(
+					"Expected target fixture %t got %t",
+					mock.isTargetFixture,
+					!mock.isTargetFixture,
+				)
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"mayadata.io/d-operators/pkg/kubernetes"
 	types "mayadata.io/d-operators/types/recipe"
 	"openebs.io/metac/dynamic/clientset"
 )
@@ -31,6 +32,9 @@ import (
 type TaskRunner struct {
 	BaseRunner
 	Task types.Task
+
+	// action that was run by this task e.g. create, apply, etc.
+	action string
 }
 
 func (r *TaskRunner) isDeleteFromApply() (bool, error) {
@@ -273,32 +277,81 @@ func (r *TaskRunner) tryRunCreate() (*types.TaskResult, bool, error) {
 	return got, true, err
 }
 
+// resultFromError returns the result of this task based on the
+// error that resulted from running its action
+func (r *TaskRunner) resultFromError(err error) (types.TaskResult, error) {
+	isForbidden := apierrors.IsForbidden(errors.Cause(err))
+	switch {
+	case r.Task.IgnoreErrorRule == types.IgnoreErrorForbiddenAsPassed && isForbidden:
+		return types.TaskResult{
+			Step:    r.TaskIndex,
+			Phase:   types.TaskStatusPassed,
+			Message: fmt.Sprintf("Forbidden as expected: %s", errors.Cause(err)),
+		}, nil
+	case r.Task.IgnoreErrorRule == types.IgnoreErrorAsWarning:
+		// treat error as warning & continue
+		return types.TaskResult{
+			Step:    r.TaskIndex,
+			Phase:   types.TaskStatusWarning,
+			Warning: err.Error(),
+		}, nil
+	case isForbidden:
+		// a denied action fails this task instead of erroring
+		// the Recipe
+		return types.TaskResult{
+			Step:    r.TaskIndex,
+			Phase:   types.TaskStatusFailed,
+			Message: fmt.Sprintf("Forbidden: %s", errors.Cause(err)),
+		}, nil
+	default:
+		return types.TaskResult{}, err
+	}
+}
+
 // Run executes the test step
 func (r *TaskRunner) Run() (types.TaskResult, error) {
 	// only one of the probables will run
-	var probables = []func() (*types.TaskResult, bool, error){
-		r.tryRunCreate,
-		r.tryRunAssert,
-		r.tryRunDelete,
-		r.tryRunApply,
-		r.tryRunLabel,
+	var probables = []struct {
+		action string
+		run    func() (*types.TaskResult, bool, error)
+	}{
+		{"create", r.tryRunCreate},
+		{"assert", r.tryRunAssert},
+		{"delete", r.tryRunDelete},
+		{"apply", r.tryRunApply},
+		{"label", r.tryRunLabel},
+	}
+	if r.expectedError() != nil {
+		// action is run once since retrying hides the expected error
+		// e.g. AlreadyExists would be retried till timeout
+		r.Retry = kubernetes.NewRetry(kubernetes.RetryConfig{RunOnce: true})
 	}
-	for _, fn := range probables {
-		got, hasRun, err := fn()
+	for _, p := range probables {
+		got, hasRun, err := p.run()
+		if expect := r.expectedError(); expect != nil && (hasRun || err != nil) {
+			// expected error takes precedence over ignore error
+			r.action = p.action
+			return r.resultFromExpectedError(*expect, got, err)
+		}
 		if err != nil {
-			if r.Task.IgnoreErrorRule == types.IgnoreErrorAsWarning {
-				// treat error as warning & continue
-				return types.TaskResult{
-					Step:    r.TaskIndex,
-					Phase:   types.TaskStatusWarning,
-					Warning: err.Error(),
-				}, nil
-			}
-			return types.TaskResult{}, err
+			r.action = p.action
+			return r.resultFromError(err)
 		}
 		if !hasRun {
 			continue
 		}
+		r.action = p.action
+		if r.Task.IgnoreErrorRule == types.IgnoreErrorForbiddenAsPassed {
+			// this action was expected to be denied
+			return types.TaskResult{
+				Step:  r.TaskIndex,
+				Phase: types.TaskStatusFailed,
+				Message: fmt.Sprintf(
+					"Expected forbidden: Action was allowed: %s",
+					got.Message,
+				),
+			}, nil
+		}
 		got.Step = r.TaskIndex
 		return *got, nil
 	}
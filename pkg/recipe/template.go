@@ -0,0 +1,219 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"mayadata.io/d-operators/common/unstruct"
+	"mayadata.io/d-operators/types/gvk"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// paramRefRegex matches the references to a template param e.g.
+// $(params.namespace)
+var paramRefRegex = regexp.MustCompile(`\$\(params\.([^)]+)\)`)
+
+// getTemplate returns the RecipeTemplate with the provided name from
+// the namespace of this Recipe
+//
+// NOTE:
+//	Templates are read with the identity of this operator since
+// these are found in the cluster that runs this operator
+func (r *Runner) getTemplate(name string) (*types.RecipeTemplate, error) {
+	client, err := r.fixture.GetClientForAPIVersionAndKind(
+		gvk.APIVersionRecipe,
+		gvk.KindRecipeTemplate,
+	)
+	if err != nil {
+		return nil, err
+	}
+	got, err := client.
+		Namespace(r.Recipe.GetNamespace()).
+		Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	var template types.RecipeTemplate
+	err = unstruct.ToTyped(got, &template)
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// TemplateParams returns the values of the provided template's params.
+// Values set by the include take precedence over the defaults.
+func TemplateParams(
+	template types.RecipeTemplate,
+	include types.Include,
+) (map[string]interface{}, error) {
+	var params = map[string]interface{}{}
+	for _, param := range template.Spec.Params {
+		value, found := include.Params[param.Name]
+		if !found {
+			value = param.Default
+		}
+		if value == nil {
+			return nil, errors.Errorf(
+				"Missing param %q: Template %q",
+				param.Name,
+				template.GetName(),
+			)
+		}
+		params[param.Name] = value
+	}
+	for name := range include.Params {
+		if _, found := params[name]; !found {
+			return nil, errors.Errorf(
+				"Unknown param %q: Template %q",
+				name,
+				template.GetName(),
+			)
+		}
+	}
+	return params, nil
+}
+
+// IncludeTemplateTasks returns the tasks of the provided template
+// with the provided params substituted. These tasks are named after
+// the provided including task.
+func IncludeTemplateTasks(
+	including string,
+	template types.RecipeTemplate,
+	params map[string]interface{},
+) ([]types.Task, error) {
+	resolve := func(ref string) (interface{}, error) {
+		name := paramRefRegex.FindStringSubmatch(ref)[1]
+		value, found := params[name]
+		if !found {
+			return nil, errors.Errorf("Invalid reference %q: Param not found", ref)
+		}
+		return value, nil
+	}
+	var tasks []types.Task
+	for _, task := range template.Spec.Tasks {
+		if task.Include != nil {
+			return nil, errors.Errorf(
+				"Nested include is not supported: Task %q",
+				task.Name,
+			)
+		}
+		var obj map[string]interface{}
+		err := unstruct.MarshalThenUnmarshal(task, &obj)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Task %q", task.Name)
+		}
+		got, err := substitute(obj, paramRefRegex, resolve)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Task %q", task.Name)
+		}
+		var included types.Task
+		err = unstruct.MarshalThenUnmarshal(got, &included)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Task %q", task.Name)
+		}
+		if included.ForEach != nil && included.ForEach.Param != "" {
+			// forEach.param refers to this template's param if found
+			// & to the Recipe's param otherwise
+			if value, found := params[included.ForEach.Param]; found {
+				items, ok := value.([]interface{})
+				if !ok {
+					return nil, errors.Errorf(
+						"Invalid forEach: Task %q: Param %q: Want list got %T",
+						task.Name,
+						included.ForEach.Param,
+						value,
+					)
+				}
+				included.ForEach.Items = items
+				included.ForEach.Param = ""
+			}
+		}
+		included.Name = fmt.Sprintf("%s/%s", including, task.Name)
+		tasks = append(tasks, included)
+	}
+	return tasks, nil
+}
+
+// includeTasks returns the tasks of the template included by the
+// provided task
+func (r *Runner) includeTasks(task types.Task) ([]types.Task, error) {
+	if task.ForEach != nil {
+		return nil, errors.Errorf("Include can't be used with forEach")
+	}
+	template, err := r.getTemplate(task.Include.Template)
+	if err != nil {
+		return nil, errors.Wrapf(
+			err,
+			"Get template failed: Template %q",
+			task.Include.Template,
+		)
+	}
+	params, err := TemplateParams(*template, *task.Include)
+	if err != nil {
+		return nil, err
+	}
+	return IncludeTemplateTasks(task.Name, *template, params)
+}
+
+// expandIncludes replaces the tasks that include a RecipeTemplate
+// with the tasks of that template
+//
+// NOTE:
+//	This is done before the tasks are evaluated. Names of the
+// included tasks are set in the status.
+func (r *Runner) expandIncludes() error {
+	for _, tasks := range []*[]types.Task{
+		&r.Recipe.Spec.Tasks,
+		&r.Recipe.Spec.OnFailure,
+		&r.Recipe.Spec.Finally,
+	} {
+		var expanded []types.Task
+		for _, task := range *tasks {
+			if task.Include == nil {
+				expanded = append(expanded, task)
+				continue
+			}
+			included, err := r.includeTasks(task)
+			if err != nil {
+				return errors.Wrapf(
+					err,
+					"Include failed: Task %q: Recipe %q / %q",
+					task.Name,
+					r.Recipe.GetNamespace(),
+					r.Recipe.GetName(),
+				)
+			}
+			if r.RecipeStatus.IncludedTasks == nil {
+				r.RecipeStatus.IncludedTasks = map[string][]string{}
+			}
+			var names = []string{}
+			for _, t := range included {
+				names = append(names, t.Name)
+			}
+			r.RecipeStatus.IncludedTasks[task.Name] = names
+			expanded = append(expanded, included...)
+		}
+		*tasks = expanded
+	}
+	return nil
+}
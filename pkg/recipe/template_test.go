@@ -0,0 +1,358 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"openebs.io/metac/dynamic/clientset"
+	dynamicdiscovery "openebs.io/metac/dynamic/discovery"
+
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// newNamespaceTemplate returns a template that creates a namespace
+// & a config map in this namespace
+func newNamespaceTemplate() types.RecipeTemplate {
+	return types.RecipeTemplate{
+		Spec: types.RecipeTemplateSpec{
+			Params: []types.TemplateParam{
+				{Name: "namespace"},
+				{Name: "labels", Default: map[string]interface{}{"app": "test"}},
+				{Name: "configs", Default: []interface{}{"cm-1"}},
+			},
+			Tasks: []types.Task{
+				{
+					Name: "create-ns",
+					Create: &types.Create{
+						State: &unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"kind":       "Namespace",
+								"apiVersion": "v1",
+								"metadata": map[string]interface{}{
+									"name":   "$(params.namespace)",
+									"labels": "$(params.labels)",
+								},
+							},
+						},
+					},
+				},
+				{
+					Name: "create-cm",
+					ForEach: &types.ForEach{
+						Param: "configs",
+					},
+					Create: &types.Create{
+						State: &unstructured.Unstructured{
+							Object: map[string]interface{}{
+								"kind":       "ConfigMap",
+								"apiVersion": "v1",
+								"metadata": map[string]interface{}{
+									"name":      "$(item)",
+									"namespace": "$(params.namespace)",
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestTemplateParams(t *testing.T) {
+	var tests = map[string]struct {
+		include  types.Include
+		expected map[string]interface{}
+		isErr    bool
+	}{
+		"defaults": {
+			include: types.Include{
+				Params: map[string]interface{}{"namespace": "ns-1"},
+			},
+			expected: map[string]interface{}{
+				"namespace": "ns-1",
+				"labels":    map[string]interface{}{"app": "test"},
+				"configs":   []interface{}{"cm-1"},
+			},
+		},
+		"override defaults": {
+			include: types.Include{
+				Params: map[string]interface{}{
+					"namespace": "ns-1",
+					"configs":   []interface{}{"cm-1", "cm-2"},
+				},
+			},
+			expected: map[string]interface{}{
+				"namespace": "ns-1",
+				"labels":    map[string]interface{}{"app": "test"},
+				"configs":   []interface{}{"cm-1", "cm-2"},
+			},
+		},
+		"missing param": {
+			isErr: true,
+		},
+		"unknown param": {
+			include: types.Include{
+				Params: map[string]interface{}{
+					"namespace": "ns-1",
+					"junk":      "junk",
+				},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := TemplateParams(newNamespaceTemplate(), mock.include)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if diff := cmp.Diff(mock.expected, got); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIncludeTemplateTasks(t *testing.T) {
+	var tests = map[string]struct {
+		template types.RecipeTemplate
+		params   map[string]interface{}
+		isErr    bool
+	}{
+		"include": {
+			template: newNamespaceTemplate(),
+			params: map[string]interface{}{
+				"namespace": "ns-1",
+				"labels":    map[string]interface{}{"app": "test"},
+				"configs":   []interface{}{"cm-1", "cm-2"},
+			},
+		},
+		"forEach param is not a list": {
+			template: newNamespaceTemplate(),
+			params: map[string]interface{}{
+				"namespace": "ns-1",
+				"labels":    map[string]interface{}{"app": "test"},
+				"configs":   "cm-1",
+			},
+			isErr: true,
+		},
+		"nested include": {
+			template: types.RecipeTemplate{
+				Spec: types.RecipeTemplateSpec{
+					Tasks: []types.Task{
+						{
+							Name: "include",
+							Include: &types.Include{
+								Template: "other",
+							},
+						},
+					},
+				},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			got, err := IncludeTemplateTasks("setup", mock.template, mock.params)
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			if len(got) != 2 {
+				t.Fatalf("Expected 2 tasks got %d", len(got))
+			}
+			if got[0].Name != "setup/create-ns" || got[1].Name != "setup/create-cm" {
+				t.Fatalf(
+					"Expected included task names got %q & %q",
+					got[0].Name,
+					got[1].Name,
+				)
+			}
+			ns := got[0].Create.State
+			if ns.GetName() != "ns-1" {
+				t.Fatalf("Expected namespace %q got %q", "ns-1", ns.GetName())
+			}
+			if diff := cmp.Diff(map[string]string{"app": "test"}, ns.GetLabels()); diff != "" {
+				t.Fatalf("Expected no diff in labels got:\n%s", diff)
+			}
+			forEach := got[1].ForEach
+			if forEach.Param != "" || len(forEach.Items) != 2 {
+				t.Fatalf("Expected forEach items from param got %+v", forEach)
+			}
+			cm := got[1].Create.State
+			if cm.GetName() != "$(item)" || cm.GetNamespace() != "ns-1" {
+				t.Fatalf(
+					"Expected config map $(item) / ns-1 got %s / %s",
+					cm.GetName(),
+					cm.GetNamespace(),
+				)
+			}
+		})
+	}
+}
+
+func TestRunnerExpandIncludes(t *testing.T) {
+	// fixture is loaded with a template that has no params
+	fixture := &BaseFixture{
+		getClientForAPIVersionAndKindFn: func(
+			apiversion string,
+			kind string,
+		) (*clientset.ResourceClient, error) {
+			di := dynamicfake.NewSimpleDynamicClient(
+				runtime.NewScheme(),
+				&unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"kind":       "RecipeTemplate",
+						"apiVersion": "dope.mayadata.io/v1",
+						"metadata": map[string]interface{}{
+							"name":      "cleanup",
+							"namespace": "ns",
+						},
+						"spec": map[string]interface{}{
+							"tasks": []interface{}{
+								map[string]interface{}{
+									"name": "delete-cm",
+									"delete": map[string]interface{}{
+										"state": map[string]interface{}{
+											"kind":       "ConfigMap",
+											"apiVersion": "v1",
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			)
+			nri := di.Resource(schema.GroupVersionResource{
+				Group:    "dope.mayadata.io",
+				Version:  "v1",
+				Resource: "recipetemplates",
+			})
+			// noop api resource ignores the namespace
+			ri := nri.Namespace("ns")
+			return &clientset.ResourceClient{
+				ResourceInterface: ri,
+				APIResource:       &dynamicdiscovery.APIResource{},
+			}, nil
+		},
+	}
+	var include = func(name string) types.Task {
+		return types.Task{
+			Name:    name,
+			Include: &types.Include{Template: name},
+		}
+	}
+	var tests = map[string]struct {
+		spec             types.RecipeSpec
+		expectedTasks    []string
+		expectedFinally  []string
+		expectedIncluded map[string][]string
+		isErr            bool
+	}{
+		"no includes": {
+			spec: types.RecipeSpec{
+				Tasks: []types.Task{{Name: "create"}},
+			},
+			expectedTasks: []string{"create"},
+		},
+		"includes": {
+			spec: types.RecipeSpec{
+				Tasks:   []types.Task{{Name: "create"}, include("cleanup")},
+				Finally: []types.Task{include("cleanup")},
+			},
+			expectedTasks:   []string{"create", "cleanup/delete-cm"},
+			expectedFinally: []string{"cleanup/delete-cm"},
+			expectedIncluded: map[string][]string{
+				"cleanup": {"cleanup/delete-cm"},
+			},
+		},
+		"missing template": {
+			spec: types.RecipeSpec{
+				Tasks: []types.Task{include("junk")},
+			},
+			isErr: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			r := &Runner{
+				Recipe: types.Recipe{
+					Spec: mock.spec,
+				},
+				RecipeStatus: &types.RecipeStatus{},
+				fixture: &Fixture{
+					BaseFixture: fixture,
+				},
+			}
+			r.Recipe.SetNamespace("ns")
+			err := r.expandIncludes() // method under test
+			if mock.isErr {
+				if err == nil {
+					t.Fatalf("Expected error got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error got %+v", err)
+			}
+			var names = func(tasks []types.Task) []string {
+				var out []string
+				for _, task := range tasks {
+					out = append(out, task.Name)
+				}
+				return out
+			}
+			if diff := cmp.Diff(mock.expectedTasks, names(r.Recipe.Spec.Tasks)); diff != "" {
+				t.Fatalf("Expected no diff in tasks got:\n%s", diff)
+			}
+			if diff := cmp.Diff(mock.expectedFinally, names(r.Recipe.Spec.Finally)); diff != "" {
+				t.Fatalf("Expected no diff in finally tasks got:\n%s", diff)
+			}
+			if diff := cmp.Diff(mock.expectedIncluded, r.RecipeStatus.IncludedTasks); diff != "" {
+				t.Fatalf("Expected no diff in included tasks got:\n%s", diff)
+			}
+		})
+	}
+}
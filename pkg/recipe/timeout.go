@@ -0,0 +1,170 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package recipe
+
+import (
+	"fmt"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"mayadata.io/d-operators/pkg/kubernetes"
+	types "mayadata.io/d-operators/types/recipe"
+)
+
+// isCancelRequested returns true if the provided annotations
+// request to cancel the Recipe
+func isCancelRequested(annotations map[string]string) bool {
+	return annotations[types.AnnotationKeyCancel] == "true"
+}
+
+// initDeadline sets the time by which the tasks of this run should
+// complete
+func (r *Runner) initDeadline(start time.Time) {
+	if r.Recipe.Spec.TimeoutInSeconds == nil {
+		return
+	}
+	timeout := *r.Recipe.Spec.TimeoutInSeconds
+	if timeout < 0 {
+		timeout = 0
+	}
+	r.deadline = start.Add(time.Duration(timeout) * time.Second)
+}
+
+// isTimedOut returns true if this run has exceeded its deadline
+func (r *Runner) isTimedOut() bool {
+	return !r.deadline.IsZero() && !time.Now().Before(r.deadline)
+}
+
+// retryWithinDeadline returns the retry options of a task such that
+// this task does not retry beyond the deadline of this run
+func (r *Runner) retryWithinDeadline() *kubernetes.Retryable {
+	if r.deadline.IsZero() || r.Retry == nil {
+		return r.Retry
+	}
+	remaining := time.Until(r.deadline)
+	if remaining >= r.Retry.WaitTimeout {
+		return r.Retry
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	bounded := *r.Retry
+	bounded.WaitTimeout = remaining
+	return &bounded
+}
+
+// isCancelled returns true if this Recipe was requested to be
+// cancelled
+//
+// NOTE:
+//	Latest annotations of this Recipe are read from the cluster since
+// cancellation is requested while this Recipe is running. Only the
+// observed annotations are verified if this Recipe is not watched
+// as a custom resource.
+func (r *Runner) isCancelled() bool {
+	if r.IsCancelledFn != nil {
+		cancelled, err := r.IsCancelledFn()
+		if err != nil {
+			klog.Errorf(
+				"Verify cancel failed: Recipe %q / %q: %s",
+				r.Recipe.Namespace,
+				r.Recipe.Name,
+				err.Error(),
+			)
+		}
+		return cancelled
+	}
+	if isCancelRequested(r.Recipe.GetAnnotations()) {
+		return true
+	}
+	if r.UpdateRecipeWithRetriesFn != nil {
+		// Recipe is not watched as a custom resource
+		return false
+	}
+	annotations, err := r.getLatestAnnotations()
+	if err != nil {
+		// swallow the error by logging since cancellation will be
+		// verified again before running the next task
+		klog.Errorf(
+			"Verify cancel failed: Recipe %q / %q: %s",
+			r.Recipe.Namespace,
+			r.Recipe.Name,
+			err.Error(),
+		)
+		return false
+	}
+	return isCancelRequested(annotations)
+}
+
+// getLatestAnnotations returns the annotations of this Recipe from
+// the cluster
+func (r *Runner) getLatestAnnotations() (map[string]string, error) {
+	client, err := r.fixture.GetClientForAPIVersionAndKind(
+		r.Recipe.APIVersion,
+		r.Recipe.Kind,
+	)
+	if err != nil {
+		return nil, err
+	}
+	latest, err := client.
+		Namespace(r.Recipe.Namespace).
+		Get(r.Recipe.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return latest.GetAnnotations(), nil
+}
+
+// interruption returns the phase of the tasks that are interrupted
+// due to timeout or cancellation. An empty phase implies tasks can
+// continue to run.
+func (r *Runner) interruption() types.TaskStatusPhase {
+	if r.isTimedOut() {
+		return types.TaskStatusTimedOut
+	}
+	if r.isCancelled() {
+		return types.TaskStatusCancelled
+	}
+	return ""
+}
+
+// interruptTasks marks the provided tasks as interrupted with the
+// provided phase. These tasks are counted as skipped.
+func (r *Runner) interruptTasks(
+	tasks []types.Task,
+	phase types.TaskStatusPhase,
+	offset int,
+) {
+	r.interruptedAs = phase
+	message := "Recipe was cancelled"
+	if phase == types.TaskStatusTimedOut {
+		message = fmt.Sprintf(
+			"Recipe exceeded timeout of %ds",
+			*r.Recipe.Spec.TimeoutInSeconds,
+		)
+	}
+	for idx, task := range tasks {
+		r.RecipeStatus.TaskResults[task.Name] = types.TaskResult{
+			Step:    offset + idx + 1,
+			Phase:   phase,
+			Message: message,
+		}
+		r.RecipeStatus.TaskCount.Skipped++
+	}
+}
@@ -0,0 +1,316 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// ValueType defines the data type of a field's value
+type ValueType string
+
+const (
+	// ValueTypeString expects the value to be a string
+	ValueTypeString ValueType = "string"
+
+	// ValueTypeInt expects the value to be an integer
+	ValueTypeInt ValueType = "int"
+
+	// ValueTypeNumber expects the value to be an integer or
+	// a floating point number
+	ValueTypeNumber ValueType = "number"
+
+	// ValueTypeBool expects the value to be a boolean
+	ValueTypeBool ValueType = "bool"
+
+	// ValueTypeMap expects the value to be an object
+	ValueTypeMap ValueType = "map"
+
+	// ValueTypeList expects the value to be a list
+	ValueTypeList ValueType = "list"
+)
+
+// ValueRule defines the expectations of the value found at
+// a field path
+type ValueRule struct {
+	// Path is the absolute field path. List items are
+	// represented as [*] e.g. spec.tasks.[*].name
+	Path string
+
+	// Type of the value if set
+	Type ValueType
+
+	// Enum has all the supported values if set
+	Enum []string
+
+	// Required implies this field must be set if its parent
+	// is set
+	Required bool
+}
+
+// ObjectRule defines validation across multiple fields of
+// the object found at a field path
+type ObjectRule struct {
+	// Path is the absolute field path of the object. List
+	// items are represented as [*] e.g. spec.tasks.[*]. An
+	// empty path implies the target itself.
+	Path string
+
+	// Validate returns the failures if any. The provided path
+	// is the actual path of the object e.g. spec.tasks.[1]
+	Validate func(path string, obj map[string]interface{}) []ErrorMessage
+}
+
+// ValueValidation enables validating the values of fields in
+// yaml like structures
+//
+// NOTE:
+//	This is complementary to FieldPathValidation which validates
+// the field paths but not the values set against these paths
+type ValueValidation struct {
+	// Unstructured object whose values will be validated
+	Target map[string]interface{}
+
+	// Rules that validate a single field
+	ValueRules []ValueRule
+
+	// Rules that validate relations between fields
+	ObjectRules []ObjectRule
+
+	// all the validation failures
+	failures []ErrorMessage
+}
+
+// visit invokes the provided callback against all the values
+// found at the provided path. Callback is also invoked if only
+// the leaf field is missing, with found set to false.
+func visit(
+	given interface{},
+	segments []string,
+	actualPath string,
+	callback func(path string, val interface{}, found bool),
+) {
+	if len(segments) == 0 {
+		callback(actualPath, given, true)
+		return
+	}
+	join := func(next string) string {
+		if actualPath == "" {
+			return next
+		}
+		return actualPath + "." + next
+	}
+	segment := segments[0]
+	if segment == "[*]" {
+		list, ok := given.([]interface{})
+		if !ok {
+			// type of the list is validated separately
+			return
+		}
+		for idx, item := range list {
+			visit(item, segments[1:], join(fmt.Sprintf("[%d]", idx)), callback)
+		}
+		return
+	}
+	obj, ok := given.(map[string]interface{})
+	if !ok {
+		// type of the object is validated separately
+		return
+	}
+	val, found := obj[segment]
+	if !found || val == nil {
+		if len(segments) == 1 {
+			// parent is available but not this leaf
+			callback(join(segment), nil, false)
+		}
+		return
+	}
+	visit(val, segments[1:], join(segment), callback)
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	return strings.Split(path, ".")
+}
+
+// isType returns true if the provided value matches the
+// provided type
+func isType(val interface{}, want ValueType) bool {
+	switch want {
+	case ValueTypeString:
+		_, ok := val.(string)
+		return ok
+	case ValueTypeBool:
+		_, ok := val.(bool)
+		return ok
+	case ValueTypeMap:
+		_, ok := val.(map[string]interface{})
+		return ok
+	case ValueTypeList:
+		_, ok := val.([]interface{})
+		return ok
+	case ValueTypeInt:
+		switch v := val.(type) {
+		case int, int32, int64:
+			return true
+		case float64:
+			// json numbers may be decoded as float64
+			return v == math.Trunc(v)
+		}
+		return false
+	case ValueTypeNumber:
+		switch val.(type) {
+		case int, int32, int64, float32, float64:
+			return true
+		}
+		return false
+	}
+	return true
+}
+
+// typeOf returns a readable data type of the provided value
+func typeOf(val interface{}) string {
+	switch val.(type) {
+	case string:
+		return string(ValueTypeString)
+	case bool:
+		return string(ValueTypeBool)
+	case map[string]interface{}:
+		return string(ValueTypeMap)
+	case []interface{}:
+		return string(ValueTypeList)
+	case int, int32, int64:
+		return string(ValueTypeInt)
+	case float32, float64:
+		return string(ValueTypeNumber)
+	}
+	return fmt.Sprintf("%T", val)
+}
+
+func (v *ValueValidation) validateValue(rule ValueRule) {
+	visit(
+		v.Target,
+		splitPath(rule.Path),
+		"",
+		func(path string, val interface{}, found bool) {
+			if !found {
+				if rule.Required {
+					v.failures = append(v.failures, ErrorMessage{
+						Error:  fmt.Sprintf("Missing field: Path %q", path),
+						Remedy: fmt.Sprintf("Set a value at %q", path),
+					})
+				}
+				return
+			}
+			if rule.Type != "" && !isType(val, rule.Type) {
+				v.failures = append(v.failures, ErrorMessage{
+					Error: fmt.Sprintf(
+						"Invalid type: Path %q: Want %s got %s",
+						path,
+						rule.Type,
+						typeOf(val),
+					),
+					Remedy: fmt.Sprintf("Set a %s value at %q", rule.Type, path),
+				})
+				return
+			}
+			if len(rule.Enum) == 0 {
+				return
+			}
+			str := fmt.Sprintf("%v", val)
+			for _, e := range rule.Enum {
+				if e == str {
+					return
+				}
+			}
+			v.failures = append(v.failures, ErrorMessage{
+				Error: fmt.Sprintf("Invalid value %q: Path %q", str, path),
+				Remedy: fmt.Sprintf(
+					"Supported values: %s",
+					strings.Join(rule.Enum, ", "),
+				),
+			})
+		},
+	)
+}
+
+func (v *ValueValidation) validateObject(rule ObjectRule) {
+	if rule.Validate == nil {
+		return
+	}
+	visit(
+		v.Target,
+		splitPath(rule.Path),
+		"",
+		func(path string, val interface{}, found bool) {
+			obj, ok := val.(map[string]interface{})
+			if !found || !ok {
+				return
+			}
+			v.failures = append(v.failures, rule.Validate(path, obj)...)
+		},
+	)
+}
+
+// Validate validates the values of the provided object against
+// the provided rules
+func (v *ValueValidation) Validate() *FieldPathValidationResult {
+	for _, rule := range v.ValueRules {
+		v.validateValue(rule)
+	}
+	for _, rule := range v.ObjectRules {
+		v.validateObject(rule)
+	}
+	if len(v.failures) == 0 {
+		// no validation errors; hence valid values
+		return &FieldPathValidationResult{
+			Status: FieldPathValidationStatusValid,
+		}
+	}
+	return &FieldPathValidationResult{
+		Status:   FieldPathValidationStatusInvalid,
+		Failures: v.failures,
+	}
+}
+
+// Merge adds the failures of the provided result to this result
+func (v *FieldPathValidationResult) Merge(other *FieldPathValidationResult) {
+	if other == nil || other.Status != FieldPathValidationStatusInvalid {
+		return
+	}
+	v.Status = FieldPathValidationStatusInvalid
+	v.Failures = append(v.Failures, other.Failures...)
+	v.Verbose = append(v.Verbose, other.Verbose...)
+}
+
+// SetFields returns the names of the provided fields that are
+// set in the provided object in a sorted order
+func SetFields(obj map[string]interface{}, fields ...string) []string {
+	var out []string
+	for _, f := range fields {
+		if val, found := obj[f]; found && val != nil {
+			out = append(out, f)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
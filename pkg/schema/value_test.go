@@ -0,0 +1,207 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package schema
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValueValidation(t *testing.T) {
+	var rules = []ValueRule{
+		{Path: "spec.count", Type: ValueTypeInt},
+		{Path: "spec.when", Type: ValueTypeString, Enum: []string{"Once", "Always"}},
+		{Path: "spec.items.[*].name", Type: ValueTypeString, Required: true},
+	}
+	var objRules = []ObjectRule{
+		{
+			Path: "spec.items.[*]",
+			Validate: func(path string, obj map[string]interface{}) []ErrorMessage {
+				if len(SetFields(obj, "a", "b")) == 2 {
+					return []ErrorMessage{{Error: "both: " + path}}
+				}
+				return nil
+			},
+		},
+	}
+	var tests = map[string]struct {
+		Target         map[string]interface{}
+		ExpectedStatus FieldPathValidationStatus
+		ExpectedErrors []string
+	}{
+		"empty target": {
+			Target:         map[string]interface{}{},
+			ExpectedStatus: FieldPathValidationStatusValid,
+		},
+		"valid values": {
+			Target: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"count": int64(1),
+					"when":  "Once",
+					"items": []interface{}{
+						map[string]interface{}{
+							"name": "one",
+							"a":    true,
+						},
+					},
+				},
+			},
+			ExpectedStatus: FieldPathValidationStatusValid,
+		},
+		"integral float is a valid int": {
+			Target: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"count": float64(2),
+				},
+			},
+			ExpectedStatus: FieldPathValidationStatusValid,
+		},
+		"invalid int": {
+			Target: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"count": "1",
+				},
+			},
+			ExpectedStatus: FieldPathValidationStatusInvalid,
+			ExpectedErrors: []string{
+				`Invalid type: Path "spec.count": Want int got string`,
+			},
+		},
+		"invalid enum": {
+			Target: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"when": "Never",
+				},
+			},
+			ExpectedStatus: FieldPathValidationStatusInvalid,
+			ExpectedErrors: []string{
+				`Invalid value "Never": Path "spec.when"`,
+			},
+		},
+		"missing required field & cross field failure": {
+			Target: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"items": []interface{}{
+						map[string]interface{}{
+							"name": "one",
+						},
+						map[string]interface{}{
+							"a": true,
+							"b": true,
+						},
+					},
+				},
+			},
+			ExpectedStatus: FieldPathValidationStatusInvalid,
+			ExpectedErrors: []string{
+				`Missing field: Path "spec.items.[1].name"`,
+				`both: spec.items.[1]`,
+			},
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			v := &ValueValidation{
+				Target:      mock.Target,
+				ValueRules:  rules,
+				ObjectRules: objRules,
+			}
+			got := v.Validate()
+			if got.Status != mock.ExpectedStatus {
+				t.Fatalf(
+					"Expected status %q got %q: %s",
+					mock.ExpectedStatus,
+					got.Status,
+					got.Error(),
+				)
+			}
+			var gotErrors []string
+			for _, f := range got.Failures {
+				gotErrors = append(gotErrors, f.Error)
+			}
+			if diff := cmp.Diff(mock.ExpectedErrors, gotErrors); diff != "" {
+				t.Fatalf("Expected no diff got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestValidationResultMerge(t *testing.T) {
+	var tests = map[string]struct {
+		Result         FieldPathValidationResult
+		Other          *FieldPathValidationResult
+		ExpectedStatus FieldPathValidationStatus
+		ExpectedCount  int
+	}{
+		"merge nil": {
+			Result:         FieldPathValidationResult{Status: FieldPathValidationStatusValid},
+			ExpectedStatus: FieldPathValidationStatusValid,
+		},
+		"merge valid": {
+			Result:         FieldPathValidationResult{Status: FieldPathValidationStatusValid},
+			Other:          &FieldPathValidationResult{Status: FieldPathValidationStatusValid},
+			ExpectedStatus: FieldPathValidationStatusValid,
+		},
+		"merge invalid into valid": {
+			Result: FieldPathValidationResult{Status: FieldPathValidationStatusValid},
+			Other: &FieldPathValidationResult{
+				Status:   FieldPathValidationStatusInvalid,
+				Failures: []ErrorMessage{{Error: "err"}},
+			},
+			ExpectedStatus: FieldPathValidationStatusInvalid,
+			ExpectedCount:  1,
+		},
+		"merge invalid into invalid": {
+			Result: FieldPathValidationResult{
+				Status:   FieldPathValidationStatusInvalid,
+				Failures: []ErrorMessage{{Error: "err"}},
+			},
+			Other: &FieldPathValidationResult{
+				Status:   FieldPathValidationStatusInvalid,
+				Failures: []ErrorMessage{{Error: "err again"}},
+			},
+			ExpectedStatus: FieldPathValidationStatusInvalid,
+			ExpectedCount:  2,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			mock.Result.Merge(mock.Other)
+			if mock.Result.Status != mock.ExpectedStatus {
+				t.Fatalf(
+					"Expected status %q got %q",
+					mock.ExpectedStatus,
+					mock.Result.Status,
+				)
+			}
+			if len(mock.Result.Failures) != mock.ExpectedCount {
+				t.Fatalf(
+					"Expected failures %d got %d",
+					mock.ExpectedCount,
+					len(mock.Result.Failures),
+				)
+			}
+		})
+	}
+}
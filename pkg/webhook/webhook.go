@@ -0,0 +1,228 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+
+	"mayadata.io/d-operators/pkg/command"
+	dopehttp "mayadata.io/d-operators/pkg/http"
+	"mayadata.io/d-operators/pkg/recipe"
+	"mayadata.io/d-operators/pkg/schema"
+	"mayadata.io/d-operators/types/gvk"
+)
+
+// ValidatePath is the http path that serves validation requests
+const ValidatePath = "/validate"
+
+// ValidateFn validates the provided unstructured object
+type ValidateFn func(obj map[string]interface{}) *schema.FieldPathValidationResult
+
+// DefaultValidators are the validations applied against dope
+// custom resources keyed by their kind
+var DefaultValidators = map[string]ValidateFn{
+	gvk.KindRecipe:         recipe.ValidateSchema,
+	gvk.KindRecipeTemplate: recipe.ValidateTemplateSchemaValues,
+	gvk.KindCommand:        command.ValidateSchemaValues,
+	gvk.KindHTTP:           dopehttp.ValidateSchemaValues,
+}
+
+// ServerConfig helps constructing a new instance of Server
+type ServerConfig struct {
+	// Address to listen at e.g. ":8443"
+	Addr string
+
+	// TLS certificate & key files
+	CertFile string
+	KeyFile  string
+
+	// Validators keyed by kind. Defaults to DefaultValidators.
+	Validators map[string]ValidateFn
+}
+
+// Server serves admission requests to validate dope custom
+// resources
+type Server struct {
+	Addr       string
+	CertFile   string
+	KeyFile    string
+	Validators map[string]ValidateFn
+}
+
+// NewServer returns a new instance of Server
+func NewServer(config ServerConfig) *Server {
+	validators := config.Validators
+	if validators == nil {
+		validators = DefaultValidators
+	}
+	return &Server{
+		Addr:       config.Addr,
+		CertFile:   config.CertFile,
+		KeyFile:    config.KeyFile,
+		Validators: validators,
+	}
+}
+
+// validate returns the admission response for the provided
+// admission request
+func (s *Server) validate(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	resp := &admissionv1.AdmissionResponse{
+		UID:     req.UID,
+		Allowed: true,
+	}
+	if req.Operation != admissionv1.Create &&
+		req.Operation != admissionv1.Update {
+		// only create & update are validated
+		return resp
+	}
+	validateFn, found := s.Validators[req.Kind.Kind]
+	if !found {
+		// resources without validations are allowed
+		return resp
+	}
+	var obj map[string]interface{}
+	err := json.Unmarshal(req.Object.Raw, &obj)
+	if err == nil && isSpecUnchanged(req, obj) {
+		// updates to labels, annotations, etc. are allowed so
+		// that controllers can update a previously invalid
+		// resource
+		return resp
+	}
+	if err != nil {
+		resp.Allowed = false
+		resp.Result = &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusBadRequest,
+			Reason:  metav1.StatusReasonBadRequest,
+			Message: fmt.Sprintf("Invalid %s: %s", req.Kind.Kind, err.Error()),
+		}
+		return resp
+	}
+	result := validateFn(obj)
+	if result.Status != schema.FieldPathValidationStatusInvalid {
+		return resp
+	}
+	var msgs []string
+	for _, f := range result.Failures {
+		msg := f.Error
+		if f.Remedy != "" {
+			msg = fmt.Sprintf("%s: Remedy: %s", f.Error, f.Remedy)
+		}
+		msgs = append(msgs, msg)
+	}
+	klog.V(3).Infof(
+		"Rejected %s %q / %q: %d failure(s)",
+		req.Kind.Kind,
+		req.Namespace,
+		req.Name,
+		len(result.Failures),
+	)
+	resp.Allowed = false
+	resp.Result = &metav1.Status{
+		Status: metav1.StatusFailure,
+		Code:   http.StatusUnprocessableEntity,
+		Reason: metav1.StatusReasonInvalid,
+		Message: fmt.Sprintf(
+			"Invalid %s: %s",
+			req.Kind.Kind,
+			strings.Join(msgs, "; "),
+		),
+	}
+	return resp
+}
+
+// isSpecUnchanged returns true if the provided request updates
+// the provided object without changing its spec
+func isSpecUnchanged(req *admissionv1.AdmissionRequest, obj map[string]interface{}) bool {
+	if req.Operation != admissionv1.Update || len(req.OldObject.Raw) == 0 {
+		return false
+	}
+	var old map[string]interface{}
+	err := json.Unmarshal(req.OldObject.Raw, &old)
+	if err != nil {
+		return false
+	}
+	return reflect.DeepEqual(old["spec"], obj["spec"])
+}
+
+// ServeHTTP handles the admission review requests
+//
+// NOTE:
+//	Both admission.k8s.io/v1 & admission.k8s.io/v1beta1 reviews
+// are supported since these share the same schema. Response is
+// sent with the api version of the request.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var review admissionv1.AdmissionReview
+	err = json.Unmarshal(body, &review)
+	if err != nil || review.Request == nil {
+		http.Error(w, "Invalid admission review", http.StatusBadRequest)
+		return
+	}
+	response := admissionv1.AdmissionReview{
+		TypeMeta: review.TypeMeta,
+		Response: s.validate(review.Request),
+	}
+	raw, err := json.Marshal(response)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, err = w.Write(raw)
+	if err != nil {
+		klog.Errorf("Failed to write admission response: %+v", err)
+	}
+}
+
+// Handler returns the http handler that serves validation requests
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle(ValidatePath, s)
+	return mux
+}
+
+// Start serves the validation requests over TLS. This is a
+// blocking call.
+func (s *Server) Start() error {
+	if s.CertFile == "" || s.KeyFile == "" {
+		return errors.Errorf(
+			"Can't start webhook server: Missing TLS cert or key file: Addr %q",
+			s.Addr,
+		)
+	}
+	klog.Infof("Serving validating webhook at %q", s.Addr)
+	return http.ListenAndServeTLS(s.Addr, s.CertFile, s.KeyFile, s.Handler())
+}
@@ -0,0 +1,292 @@
+// +build !integration
+
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestServerValidate(t *testing.T) {
+	server := httptest.NewTLSServer(NewServer(ServerConfig{}).Handler())
+	defer server.Close()
+
+	var tests = map[string]struct {
+		apiVersion    string
+		kind          string
+		operation     admissionv1.Operation
+		object        string
+		oldObject     string
+		expectAllowed bool
+		expectMessage string
+	}{
+		"valid recipe": {
+			kind:      "Recipe",
+			operation: admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"metadata": {"name": "test"},
+				"spec": {
+					"tasks": [
+						{"name": "one", "create": {"state": {"kind": "ConfigMap"}}}
+					]
+				}
+			}`,
+			expectAllowed: true,
+		},
+		"recipe with invalid path": {
+			kind:      "Recipe",
+			operation: admissionv1.Update,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"junk": true}
+			}`,
+			expectMessage: `Invalid path: "spec.junk"`,
+		},
+		"recipe with invalid enum": {
+			kind:      "Recipe",
+			operation: admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"enabled": {"when": "Twice"}}
+			}`,
+			expectMessage: `Invalid value "Twice": Path "spec.enabled.when"`,
+		},
+		"recipe with invalid enum via v1beta1": {
+			apiVersion: "admission.k8s.io/v1beta1",
+			kind:       "Recipe",
+			operation:  admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"enabled": {"when": "Twice"}}
+			}`,
+			expectMessage: `Invalid value "Twice": Path "spec.enabled.when"`,
+		},
+		"invalid recipe is allowed to update its labels": {
+			kind:      "Recipe",
+			operation: admissionv1.Update,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"metadata": {"labels": {"recipe.dope.mayadata.io/phase": "InvalidSchema"}},
+				"spec": {"junk": true}
+			}`,
+			oldObject: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"junk": true}
+			}`,
+			expectAllowed: true,
+		},
+		"invalid recipe is not allowed to update its spec": {
+			kind:      "Recipe",
+			operation: admissionv1.Update,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"junk": false}
+			}`,
+			oldObject: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"junk": true}
+			}`,
+			expectMessage: `Invalid path: "spec.junk"`,
+		},
+		"invalid recipe is allowed to be deleted": {
+			kind:      "Recipe",
+			operation: admissionv1.Delete,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Recipe",
+				"spec": {"enabled": {"when": "Twice"}}
+			}`,
+			expectAllowed: true,
+		},
+		"valid command": {
+			kind:      "Command",
+			operation: admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Command",
+				"spec": {
+					"commands": [{"name": "ls", "cmd": ["ls"]}]
+				}
+			}`,
+			expectAllowed: true,
+		},
+		"command with cmd & script": {
+			kind:      "Command",
+			operation: admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "Command",
+				"spec": {
+					"commands": [{"name": "ls", "cmd": ["ls"], "script": "ls"}]
+				}
+			}`,
+			expectMessage: `Want exactly one of cmd & script got 2`,
+		},
+		"valid http": {
+			kind:      "HTTP",
+			operation: admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "HTTP",
+				"spec": {"url": "https://github.com", "method": "get"}
+			}`,
+			expectAllowed: true,
+		},
+		"http with invalid method & no url": {
+			kind:      "HTTP",
+			operation: admissionv1.Create,
+			object: `{
+				"apiVersion": "dope.mayadata.io/v1",
+				"kind": "HTTP",
+				"spec": {"method": "GETT"}
+			}`,
+			expectMessage: `Missing field: Path "spec.url"`,
+		},
+		"unknown kind is allowed": {
+			kind:          "ConfigMap",
+			operation:     admissionv1.Create,
+			object:        `{"apiVersion": "v1", "kind": "ConfigMap"}`,
+			expectAllowed: true,
+		},
+	}
+	for name, mock := range tests {
+		name := name
+		mock := mock
+		t.Run(name, func(t *testing.T) {
+			apiVersion := mock.apiVersion
+			if apiVersion == "" {
+				apiVersion = "admission.k8s.io/v1"
+			}
+			review := admissionv1.AdmissionReview{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: apiVersion,
+					Kind:       "AdmissionReview",
+				},
+				Request: &admissionv1.AdmissionRequest{
+					UID: types.UID("uid-1"),
+					Kind: metav1.GroupVersionKind{
+						Group:   "dope.mayadata.io",
+						Version: "v1",
+						Kind:    mock.kind,
+					},
+					Operation: mock.operation,
+					Object: runtime.RawExtension{
+						Raw: []byte(mock.object),
+					},
+				},
+			}
+			if mock.oldObject != "" {
+				review.Request.OldObject = runtime.RawExtension{
+					Raw: []byte(mock.oldObject),
+				}
+			}
+			raw, err := json.Marshal(review)
+			if err != nil {
+				t.Fatalf("Invalid test data: %s", err.Error())
+			}
+			resp, err := server.Client().Post(
+				server.URL+ValidatePath,
+				"application/json",
+				bytes.NewReader(raw),
+			)
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Fatalf("Expected status code 200 got %d", resp.StatusCode)
+			}
+			var got admissionv1.AdmissionReview
+			err = json.NewDecoder(resp.Body).Decode(&got)
+			if err != nil {
+				t.Fatalf("Expected no error got %s", err.Error())
+			}
+			if got.APIVersion != apiVersion {
+				t.Fatalf("Expected api version %q got %q", apiVersion, got.APIVersion)
+			}
+			if got.Response == nil || got.Response.UID != "uid-1" {
+				t.Fatalf("Expected response with request uid got %+v", got.Response)
+			}
+			if got.Response.Allowed != mock.expectAllowed {
+				t.Fatalf(
+					"Expected allowed %t got %t: %+v",
+					mock.expectAllowed,
+					got.Response.Allowed,
+					got.Response.Result,
+				)
+			}
+			if mock.expectAllowed {
+				return
+			}
+			msg := got.Response.Result.Message
+			if !strings.Contains(msg, mock.expectMessage) {
+				t.Fatalf(
+					"Expected message to contain %q got %q",
+					mock.expectMessage,
+					msg,
+				)
+			}
+		})
+	}
+}
+
+func TestServerInvalidRequests(t *testing.T) {
+	server := httptest.NewTLSServer(NewServer(ServerConfig{}).Handler())
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + ValidatePath)
+	if err != nil {
+		t.Fatalf("Expected no error got %s", err.Error())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed {
+		t.Fatalf("Expected status code 405 got %d", resp.StatusCode)
+	}
+
+	resp, err = server.Client().Post(
+		server.URL+ValidatePath,
+		"application/json",
+		strings.NewReader(`{"kind": "AdmissionReview"}`),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error got %s", err.Error())
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("Expected status code 400 got %d", resp.StatusCode)
+	}
+}
@@ -103,6 +103,10 @@ fi
 echo -e "\n Verify if K3s is up and running"
 k3s kubectl get node
 
+echo -e "\n Apply dope CRDs to K3s cluster"
+k3s kubectl apply -f ../../deploy/crd.yaml
+k3s kubectl wait --for condition=established --timeout=60s -f ../../deploy/crd.yaml
+
 echo -e "\n Apply d-operators based ci to K3s cluster"
 k3s kubectl apply -f ci.yaml
 
@@ -47,6 +47,11 @@ const (
 // Enabled determines if the Command is eligible to be executed
 // and how often it should get executed
 type Enabled struct {
+	// When decides how often the Command gets executed
+	//
+	// Defaults to Once
+	//
+	// +default="Once"
 	When EnabledWhen `json:"when,omitempty"`
 }
 
@@ -33,6 +33,14 @@ const (
 
 	// APIVersionRecipe represent Recipe custom resource's api version
 	APIVersionRecipe string = "dope.mayadata.io/v1"
+
+	// KindRecipeTemplate represents RecipeTemplate custom resource
+	KindRecipeTemplate string = "RecipeTemplate"
+)
+
+const (
+	// KindCommand represents Command custom resource
+	KindCommand string = "Command"
 )
 
 const (
@@ -51,3 +59,16 @@ const (
 	// dao.mayadata.io as group & v1alpha1 as version
 	DAOMayadataIOV1Alpha1 string = GroupDAOMayadataIO + "/" + VersionV1Alpha1
 )
+
+const (
+	// APIExtensionsK8sIOV1 represents apiextensions.k8s.io
+	// as group & v1 as version
+	APIExtensionsK8sIOV1 string = "apiextensions.k8s.io/v1"
+
+	// GroupDopeMayadataIO represents dope.mayadata.io as
+	// group
+	GroupDopeMayadataIO string = "dope.mayadata.io"
+
+	// VersionV1 represents v1 version
+	VersionV1 string = "v1"
+)
@@ -0,0 +1,91 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Approval pauses a Recipe at this task till this task is approved
+//
+// NOTE:
+//	A task is approved by setting the Recipe's annotation
+// approve.dope.mayadata.io/<task name> or the key of the referred
+// ConfigMap to "true". It is rejected if either of these is set to
+// "false". A rejected task fails the Recipe & hence runs the
+// onFailure tasks.
+//
+// NOTE:
+//	Recipe is set to WaitingForApproval till then & is verified
+// again based on spec.resync.onWaitingForApprovalResyncInSeconds.
+// Tasks prior to this task are not run again.
+type Approval struct {
+	// ConfigMap refers to the ConfigMap key that approves or
+	// rejects this task
+	ConfigMap *ApprovalConfigMap `json:"configMap,omitempty"`
+
+	// TimeoutInSeconds fails this task if it is not approved
+	// within this time. Task waits for approval without any
+	// limit if this is not set.
+	TimeoutInSeconds *int64 `json:"timeoutInSeconds,omitempty"`
+}
+
+// ApprovalConfigMap refers to a ConfigMap key that approves or
+// rejects a task. ConfigMap should be in the namespace of the
+// Recipe.
+type ApprovalConfigMap struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+
+	// ApproverKey refers to the key of this ConfigMap that has
+	// the name of the approver
+	ApproverKey string `json:"approverKey,omitempty"`
+}
+
+// ApprovalPhase is a typed definition to determine the
+// result of an approval
+type ApprovalPhase string
+
+const (
+	// ApprovalPhaseWaiting implies a task that waits for approval
+	ApprovalPhaseWaiting ApprovalPhase = "WaitingForApproval"
+
+	// ApprovalPhaseApproved implies an approved task
+	ApprovalPhaseApproved ApprovalPhase = "Approved"
+
+	// ApprovalPhaseRejected implies a rejected task
+	ApprovalPhaseRejected ApprovalPhase = "Rejected"
+
+	// ApprovalPhaseTimedOut implies a task that was not approved
+	// within its timeout
+	ApprovalPhaseTimedOut ApprovalPhase = "TimedOut"
+)
+
+// ApprovalStatus has the details of approving a task
+type ApprovalStatus struct {
+	Phase ApprovalPhase `json:"phase"`
+
+	// Approver is the one who approved or rejected this task
+	Approver string `json:"approver,omitempty"`
+
+	// RequestTime is the time since this task waits for approval
+	RequestTime *metav1.Time `json:"requestTime,omitempty"`
+
+	// DecisionTime is the time when this task was approved,
+	// rejected or timed out
+	DecisionTime *metav1.Time `json:"decisionTime,omitempty"`
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+// Checkpoint enables a Recipe to resume from its first task that
+// did not pass in its previous run
+//
+// NOTE:
+//	Results of the tasks are persisted after every task. A task is
+// resumed only if its previous run was Failed, TimedOut, Cancelled,
+// Running or Error.
+type Checkpoint struct {
+	// Enabled persists the task results after every task & skips
+	// the tasks that passed in the previous run
+	Enabled bool `json:"enabled,omitempty"`
+}
+
+// CheckpointStatus has the details required to resume a Recipe
+type CheckpointStatus struct {
+	// ResumedFrom is the name of the task from which the current
+	// run was resumed
+	ResumedFrom string `json:"resumedFrom,omitempty"`
+
+	// Markers has the idempotency marker of every passed task
+	// keyed by the task name. A passed task is skipped during
+	// resume only if its marker is unchanged i.e. this task was
+	// not modified since it passed.
+	Markers map[string]string `json:"markers,omitempty"`
+}
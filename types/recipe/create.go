@@ -36,6 +36,10 @@ type Create struct {
 	// NOTE:
 	//	This is only applicable for kind: CustomResourceDefinition
 	IgnoreDiscovery bool `json:"ignoreDiscovery"`
+
+	// ExpectError lets this action pass only if it fails with
+	// the expected error
+	ExpectError *ExpectError `json:"expectError,omitempty"`
 }
 
 // String implements the Stringer interface
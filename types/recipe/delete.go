@@ -24,4 +24,8 @@ import (
 type Delete struct {
 	// Desired state that needs to be deleted
 	State *unstructured.Unstructured `json:"state"`
+
+	// ExpectError lets this action pass only if it fails with
+	// the expected error
+	ExpectError *ExpectError `json:"expectError,omitempty"`
 }
@@ -0,0 +1,69 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Dependency refers to the Recipe(s) that need to reach a phase
+// before the dependent Recipe is eligible to run
+//
+// NOTE:
+//	Either Name or LabelSelector should be set. These Recipes are
+// looked up in the namespace of the dependent Recipe.
+type Dependency struct {
+	// Name of the Recipe
+	Name string `json:"name,omitempty"`
+
+	// LabelSelector selects the Recipes. All the selected Recipes
+	// need to reach the phase. Dependency is not met if no Recipes
+	// are selected.
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// Phase that the Recipe(s) need to reach
+	//
+	// Defaults to Completed
+	//
+	// +default="Completed"
+	Phase RecipeStatusPhase `json:"phase,omitempty"`
+}
+
+// DependencyStatus has the observed phase of a Recipe that the
+// dependent Recipe depends on
+type DependencyStatus struct {
+	// Name of the Recipe. This is not set if no Recipes were found.
+	Name string `json:"name,omitempty"`
+
+	// Selector is the label selector that selected this Recipe
+	Selector string `json:"selector,omitempty"`
+
+	// Phase observed in this Recipe's status
+	Phase RecipeStatusPhase `json:"phase,omitempty"`
+
+	// Want is the phase that this Recipe needs to reach
+	Want RecipeStatusPhase `json:"want"`
+
+	// Met is true if this Recipe has reached the wanted phase
+	Met bool `json:"met"`
+
+	Message string `json:"message,omitempty"`
+
+	// DependsOn has the dependencies of this Recipe if any. This
+	// shows the chain of dependencies.
+	DependsOn []DependencyStatus `json:"dependsOn,omitempty"`
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+// ExpectError defines the error that an action is expected to
+// result in
+//
+// NOTE:
+//	An action with ExpectError passes only if it results in an api
+// error that matches all the fields set here. It fails if the action
+// succeeds or results in some other api error.
+//
+// NOTE:
+//	ExpectError can be set against create, apply & delete actions.
+// There is no patch action; use apply to patch a resource. An action
+// with ExpectError is run once i.e. it is not retried since errors
+// like AlreadyExists or Conflict would never be returned otherwise.
+type ExpectError struct {
+	// Code is the expected HTTP status code e.g. 422
+	Code *int32 `json:"code,omitempty"`
+
+	// Reason is the expected api reason e.g. Invalid, Forbidden,
+	// AlreadyExists, NotFound
+	Reason string `json:"reason,omitempty"`
+
+	// MessagePattern is a regular expression that should match
+	// the error message
+	MessagePattern string `json:"messagePattern,omitempty"`
+}
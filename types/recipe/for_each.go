@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+// ForEach runs a task once for every item
+//
+// NOTE:
+//	Exactly one of Items, Param or List should be set. Each item is
+// referred to in the task as $(item) while the fields of an item are
+// referred to as $(item.<field path>) e.g. $(item.metadata.name).
+// Position of the item is referred to as $(index). A reference to
+// the item that is the entire string value is replaced with the
+// referred value as is. $(index) is always replaced as a string.
+//
+// NOTE:
+//	Result of each run is named as <task name>[<index>]
+type ForEach struct {
+	// Items is a static list of values
+	Items []interface{} `json:"items,omitempty"`
+
+	// Param is the name of a list in spec.params
+	Param string `json:"param,omitempty"`
+
+	// List queries the cluster for the items
+	List *List `json:"list,omitempty"`
+}
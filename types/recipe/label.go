@@ -19,16 +19,42 @@ package types
 import (
 	"encoding/json"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-// Label represents the label apply operation against
-// one or more desired resources
+// Label represents the label & annotation apply operation
+// against one or more desired resources
 type Label struct {
 	// Desired state i.e. resources that needs to be
 	// labeled
+	//
+	// NOTE:
+	//	Labels set in this state are used to select the
+	// resources
 	State *unstructured.Unstructured `json:"state"`
 
+	// LabelSelector selects the resources in addition to the
+	// labels set in the state
+	//
+	// Optional
+	LabelSelector *metav1.LabelSelector `json:"labelSelector,omitempty"`
+
+	// FieldSelector selects the resources based on their field
+	// values e.g. 'metadata.name=my-cm' or 'status.phase=Running'
+	//
+	// Optional
+	FieldSelector string `json:"fieldSelector,omitempty"`
+
+	// NamespaceSelector selects the resources from all the
+	// namespaces that match this selector
+	//
+	// NOTE:
+	//	This can not be used if state has its namespace set
+	//
+	// Optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
 	// Include the resources by these names
 	//
 	// Optional
@@ -36,18 +62,38 @@ type Label struct {
 
 	// ApplyLabels represents the labels that need to be
 	// applied against the selected resources
-	//
-	// This is mandatory field
-	ApplyLabels map[string]string `json:"applyLabels"`
+	ApplyLabels map[string]string `json:"applyLabels,omitempty"`
+
+	// ApplyAnnotations represents the annotations that need
+	// to be applied against the selected resources
+	ApplyAnnotations map[string]string `json:"applyAnnotations,omitempty"`
 
-	// AutoUnset removes the labels from the resources if
-	// they were applied earlier and these resources are
-	// no longer elgible to be applied with these labels
+	// RemoveLabels represents the label keys that need to be
+	// removed from the selected resources
+	RemoveLabels []string `json:"removeLabels,omitempty"`
+
+	// RemoveAnnotations represents the annotation keys that
+	// need to be removed from the selected resources
+	RemoveAnnotations []string `json:"removeAnnotations,omitempty"`
+
+	// AutoUnset removes the labels & annotations from the
+	// resources if they were applied earlier and these resources
+	// are no longer elgible to be applied with these labels &
+	// annotations
 	//
 	// Defaults to false
 	AutoUnset bool `json:"autoUnset"`
 }
 
+// HasAnyOperation returns true if this Label has at-least one
+// of apply or remove operations set
+func (l Label) HasAnyOperation() bool {
+	return len(l.ApplyLabels) != 0 ||
+		len(l.ApplyAnnotations) != 0 ||
+		len(l.RemoveLabels) != 0 ||
+		len(l.RemoveAnnotations) != 0
+}
+
 // String implements the Stringer interface
 func (l Label) String() string {
 	raw, err := json.MarshalIndent(
@@ -40,3 +40,33 @@ const (
 	// field.
 	LblKeyRecipePhase string = "recipe.dope.mayadata.io/phase"
 )
+
+const (
+	// AnnotationKeyRerunToken is the annotation key to re-run a
+	// Recipe. A Recipe gets executed once more whenever the value
+	// of this annotation changes. This is useful to re-run Recipes
+	// that are meant to be run only once.
+	//
+	// NOTE:
+	//	Value of this annotation that was acted upon is set in
+	// Recipe's status.rerunToken field
+	AnnotationKeyRerunToken string = "recipe.dope.mayadata.io/rerun-token"
+
+	// AnnotationKeyCancel is the annotation key to cancel a Recipe.
+	// Tasks of a running Recipe that are yet to be run are skipped
+	// when this annotation is set to "true".
+	//
+	// NOTE:
+	//	Recipe is not run again till this annotation is removed
+	AnnotationKeyCancel string = "recipe.dope.mayadata.io/cancel"
+
+	// AnnotationKeyPrefixApprove is the prefix of the annotation
+	// key to approve or reject an approval task. Name of the task
+	// follows this prefix.
+	AnnotationKeyPrefixApprove string = "approve.dope.mayadata.io/"
+
+	// AnnotationKeyPrefixApprover is the prefix of the annotation
+	// key that has the name of the one who approved or rejected an
+	// approval task. Name of the task follows this prefix.
+	AnnotationKeyPrefixApprover string = "approver.dope.mayadata.io/"
+)
@@ -0,0 +1,84 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// LockDataKeyHolderIdentity is the key in lock's data that
+	// holds the identity of the lock holder
+	LockDataKeyHolderIdentity string = "holderIdentity"
+
+	// LockDataKeyAcquireTime is the key in lock's data that holds
+	// the time at which the lock was acquired
+	LockDataKeyAcquireTime string = "acquireTime"
+
+	// LockDataKeyRenewTime is the key in lock's data that holds
+	// the time at which the lock's lease was last renewed
+	LockDataKeyRenewTime string = "renewTime"
+
+	// LockDataKeyLeaseDurationSeconds is the key in lock's data
+	// that holds the lease duration
+	//
+	// NOTE:
+	//	A lock without this key never expires. This is the case
+	// with the locks of Recipes that are meant to be run only once.
+	LockDataKeyLeaseDurationSeconds string = "leaseDurationSeconds"
+
+	// LockDataKeySpecHash is the key in lock's data that holds
+	// the hash of the Recipe's spec that was executed
+	LockDataKeySpecHash string = "specHash"
+
+	// LockDataKeyGeneration is the key in lock's data that holds
+	// the generation of the Recipe that was executed
+	LockDataKeyGeneration string = "generation"
+)
+
+// Lock defines the lock that is taken while executing a Recipe
+type Lock struct {
+	// LeaseDurationSeconds is the duration for which the lock
+	// remains valid without being renewed. The lock is renewed
+	// by its holder while the Recipe is being executed. A lock
+	// that is not renewed within this duration is considered
+	// stale & gets reclaimed.
+	//
+	// Defaults to 60 seconds
+	//
+	// +default=60
+	LeaseDurationSeconds *int64 `json:"leaseDurationSeconds,omitempty"`
+}
+
+// LockStatus provides details of the lock that was taken to
+// execute a Recipe
+type LockStatus struct {
+	// HolderIdentity is the identity of the operator instance
+	// that holds this lock
+	HolderIdentity string `json:"holderIdentity,omitempty"`
+
+	// AcquireTime is the time at which the lock was acquired
+	AcquireTime *metav1.Time `json:"acquireTime,omitempty"`
+
+	// LeaseDurationSeconds is the duration for which the lock
+	// remains valid without being renewed
+	LeaseDurationSeconds *int64 `json:"leaseDurationSeconds,omitempty"`
+
+	// SpecHash is the hash of the Recipe's spec that was
+	// executed with this lock
+	SpecHash string `json:"specHash,omitempty"`
+}
@@ -44,6 +44,16 @@ const (
 	EnabledRuleOnce EnabledRule = "Once"
 )
 
+// RerunPolicy defines when a Recipe that is meant to be run
+// only once gets executed again
+type RerunPolicy string
+
+const (
+	// RerunPolicyOnSpecChange executes the Recipe once more
+	// whenever its spec is modified
+	RerunPolicyOnSpecChange RerunPolicy = "OnSpecChange"
+)
+
 // EligibleItemRule defines the eligibility criteria to grant a Recipe to get executed
 type EligibleItemRule string
 
@@ -85,6 +95,27 @@ const (
 	EligibleRuleAnyCheckPass EligibleRule = "AnyCheckPass"
 )
 
+// EligibleConditionOperator defines the boolean operator used to
+// combine the results of nested eligible conditions
+type EligibleConditionOperator string
+
+const (
+	// EligibleConditionOperatorAND passes if all the nested
+	// conditions pass
+	EligibleConditionOperatorAND EligibleConditionOperator = "AND"
+
+	// EligibleConditionOperatorOR passes if any of the nested
+	// conditions pass
+	//
+	// NOTE:
+	//	This is the default if nothing is specified
+	EligibleConditionOperatorOR EligibleConditionOperator = "OR"
+
+	// EligibleConditionOperatorNOT passes if its only nested
+	// condition fails
+	EligibleConditionOperatorNOT EligibleConditionOperator = "NOT"
+)
+
 // Recipe is a kubernetes custom resource that defines
 // the specifications to invoke kubernetes operations
 // against any kubernetes custom resource
@@ -116,8 +147,79 @@ type RecipeSpec struct {
 	ThinkTimeInSeconds *int64    `json:"thinkTimeInSeconds,omitempty"`
 	Enabled            *Enabled  `json:"enabled,omitempty"`
 	Eligible           *Eligible `json:"eligible,omitempty"`
+	Schedule           *Schedule `json:"schedule,omitempty"`
+	Lock               *Lock     `json:"lock,omitempty"`
 	Resync             Resync    `json:"resync,omitempty"`
 	Tasks              []Task    `json:"tasks"`
+
+	// OnFailure tasks are run after the tasks if any of these tasks
+	// failed or resulted in an error. These are useful to collect
+	// diagnostics e.g. describe the pods of a failed rollout.
+	OnFailure []Task `json:"onFailure,omitempty"`
+
+	// Finally tasks are always run after the tasks & onFailure tasks
+	// irrespective of their results. These are useful to clean up or
+	// to notify.
+	Finally []Task `json:"finally,omitempty"`
+
+	// TimeoutInSeconds is the maximum time that all the tasks of a
+	// single run of this Recipe can take. Remaining tasks are marked
+	// as TimedOut once this time is exceeded. OnFailure & finally
+	// tasks are still run.
+	//
+	// NOTE:
+	//	Retries of a task that is running when this time is exceeded
+	// are stopped. Tasks are run without this limit if this is not set.
+	TimeoutInSeconds *int64 `json:"timeoutInSeconds,omitempty"`
+
+	// Suspend stops further runs of this Recipe till this is set
+	// to false. Lock, history & schedule of this Recipe are retained.
+	//
+	// NOTE:
+	//	A run that is in progress is not stopped. Scheduled runs that
+	// fell during suspension are skipped & are not counted as missed
+	// runs. A resumed Recipe waits for its next scheduled time.
+	Suspend *bool `json:"suspend,omitempty"`
+
+	// Checkpoint enables this Recipe to resume from the task that
+	// did not pass in its previous run instead of running all the
+	// tasks again
+	Checkpoint *Checkpoint `json:"checkpoint,omitempty"`
+
+	// DependsOn has the Recipes that need to reach their phases
+	// before this Recipe is eligible to run. This Recipe is set
+	// to NotEligible till then.
+	//
+	// NOTE:
+	//	Set resync.onNotEligibleResyncInSeconds to re-verify these
+	// dependencies periodically
+	DependsOn []Dependency `json:"dependsOn,omitempty"`
+
+	// Params are named values that can be referred to by the tasks
+	// of this Recipe e.g. a task iterates over a list param via its
+	// forEach.param
+	Params map[string]interface{} `json:"params,omitempty"`
+
+	// TargetCluster refers to the cluster against which this Recipe's
+	// tasks get executed. Tasks are executed against the cluster that
+	// runs this operator if this is not set.
+	TargetCluster *TargetCluster `json:"targetCluster,omitempty"`
+
+	// ServiceAccountName is the name of the ServiceAccount that this
+	// Recipe's eligibility checks & tasks are run as. This
+	// ServiceAccount should be in the namespace of the Recipe. Tasks
+	// are run with the permissions of this operator if this is not set.
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+
+	// RunHistoryLimit is the maximum number of executions that
+	// are recorded in status.history. Oldest records are removed
+	// once this limit is reached. History is disabled if this is
+	// set to 0.
+	//
+	// Defaults to 10
+	//
+	// +default=10
+	RunHistoryLimit *int `json:"runHistoryLimit,omitempty"`
 }
 
 // Resync options to continously reconcile the Recipe instance
@@ -135,6 +237,15 @@ type Resync struct {
 	// of the Recipe based on this interval if Recipe's status.phase
 	// was set to NotEligible
 	OnNotEligibleResyncInSeconds *int64 `json:"onNotEligibleResyncInSeconds,omitempty"`
+
+	// OnWaitingForApprovalResyncInSeconds triggers the next
+	// reconciliation of the Recipe based on this interval if
+	// Recipe's status.phase was set to WaitingForApproval
+	//
+	// Defaults to 30
+	//
+	// +default=30
+	OnWaitingForApprovalResyncInSeconds *int64 `json:"onWaitingForApprovalResyncInSeconds,omitempty"`
 }
 
 // Enabled defines if the recipe is enabled to be executed
@@ -142,6 +253,14 @@ type Resync struct {
 type Enabled struct {
 	// Condition to enable or disable this Recipe
 	When EnabledRule `json:"when,omitempty"`
+
+	// RerunPolicy decides if a Recipe that is meant to be run
+	// only once should be executed again. Recipe is not executed
+	// again if this is not set.
+	//
+	// NOTE:
+	//	This can be set only if When is Once
+	RerunPolicy RerunPolicy `json:"rerunPolicy,omitempty"`
 }
 
 // Eligible defines the eligibility criteria to grant a Recipe to get
@@ -149,6 +268,36 @@ type Enabled struct {
 type Eligible struct {
 	Checks []EligibleItem `json:"checks"`
 	When   EligibleRule   `json:"when,omitempty"`
+
+	// Condition combines the checks as a boolean expression
+	// e.g. (A and B) or not C
+	//
+	// NOTE:
+	//	When is ignored if Condition is set
+	Condition *EligibleCondition `json:"condition,omitempty"`
+}
+
+// EligibleCondition is a node of the boolean expression that
+// evaluates the eligible checks
+//
+// NOTE:
+//	A node with CheckID set is a leaf that refers to the eligible
+// check with the same id. Any other node combines the results of
+// its nested conditions based on its operator.
+type EligibleCondition struct {
+	CheckID    string                    `json:"checkID,omitempty"`
+	Operator   EligibleConditionOperator `json:"operator,omitempty"`
+	Conditions []EligibleCondition       `json:"conditions,omitempty"`
+}
+
+// EligibleConditionResult holds the result of evaluating an
+// EligibleCondition & its nested conditions
+type EligibleConditionResult struct {
+	CheckID    string                    `json:"checkID,omitempty"`
+	Operator   EligibleConditionOperator `json:"operator,omitempty"`
+	Passed     bool                      `json:"passed"`
+	Message    string                    `json:"message,omitempty"`
+	Conditions []EligibleConditionResult `json:"conditions,omitempty"`
 }
 
 // EligibleItem defines the eligibility criteria to grant a Recipe to get
@@ -157,9 +306,17 @@ type EligibleItem struct {
 	ID            string               `json:"id,omitempty"`
 	APIVersion    string               `json:"apiVersion,omitempty"`
 	Kind          string               `json:"kind,omitempty"`
+	Name          string               `json:"name,omitempty"`
+	Namespace     string               `json:"namespace,omitempty"`
 	LabelSelector metav1.LabelSelector `json:"labelSelector,omitempty"`
-	When          EligibleItemRule     `json:"when,omitempty"`
-	Count         *int                 `json:"count,omitempty"`
+
+	// PathChecks are evaluated against each resource selected by
+	// above fields. A resource is selected only if all of these
+	// checks pass.
+	PathChecks []PathCheck `json:"pathChecks,omitempty"`
+
+	When  EligibleItemRule `json:"when,omitempty"`
+	Count *int             `json:"count,omitempty"`
 }
 
 // RecipeStatusPhase is a typed definition to determine the
@@ -189,6 +346,10 @@ const (
 	// reconcile attempts.
 	RecipeStatusNotEligible RecipeStatusPhase = "NotEligible"
 
+	// RecipeStatusScheduled implies a Recipe that is waiting
+	// for its first scheduled run
+	RecipeStatusScheduled RecipeStatusPhase = "Scheduled"
+
 	// RecipeStatusPassed implies a passed Recipe
 	RecipeStatusPassed RecipeStatusPhase = "Passed"
 
@@ -200,6 +361,42 @@ const (
 
 	// RecipeStatusWarning implies a Recipe with warnings
 	RecipeStatusWarning RecipeStatusPhase = "Warning"
+
+	// RecipeStatusTimedOut implies a Recipe whose tasks did not
+	// complete within spec.timeoutInSeconds
+	RecipeStatusTimedOut RecipeStatusPhase = "TimedOut"
+
+	// RecipeStatusCancelled implies a Recipe whose run was
+	// cancelled via annotation
+	RecipeStatusCancelled RecipeStatusPhase = "Cancelled"
+
+	// RecipeStatusRunning implies a Recipe whose tasks are being
+	// run. This is persisted only if spec.checkpoint is enabled.
+	RecipeStatusRunning RecipeStatusPhase = "Running"
+
+	// RecipeStatusWaitingForApproval implies a Recipe that is
+	// paused at its approval task
+	//
+	// NOTE:
+	//	This is a temporary phase. Recipe resumes from its approval
+	// task in subsequent reconcile attempts.
+	RecipeStatusWaitingForApproval RecipeStatusPhase = "WaitingForApproval"
+
+	// RecipeStatusSuspended implies a Recipe whose runs are
+	// stopped via spec.suspend
+	//
+	// NOTE:
+	//	This might be a temporary phase. Recipe's previous phase
+	// is restored once it is resumed.
+	RecipeStatusSuspended RecipeStatusPhase = "Suspended"
+
+	// RecipeStatusError implies a Recipe whose run resulted in
+	// an error
+	//
+	// NOTE:
+	//	This might be a temporary phase. Recipe is run again in
+	// subsequent reconcile attempts.
+	RecipeStatusError RecipeStatusPhase = "Error"
 )
 
 // ExecutionTime represents the time taken to execute
@@ -227,6 +424,39 @@ type RecipeStatus struct {
 	// against this Recipe's schema
 	Schema *SchemaResult `json:"schema,omitempty"`
 
+	// Eligibility has the result of evaluating spec.eligible.condition
+	Eligibility *EligibleConditionResult `json:"eligibility,omitempty"`
+
+	// Dependencies has the observed phases of the Recipes set in
+	// spec.dependsOn along with their own dependencies
+	Dependencies []DependencyStatus `json:"dependencies,omitempty"`
+
+	// RerunToken is the value of the rerun token annotation that
+	// was last acted upon
+	RerunToken string `json:"rerunToken,omitempty"`
+
+	// PreviousRun is the archived result of the run prior to the
+	// last re-run
+	PreviousRun *RecipeRunSummary `json:"previousRun,omitempty"`
+
+	// History has the records of the recent executions with the
+	// latest execution at the end
+	History []RecipeRunSummary `json:"history,omitempty"`
+
+	// Revision identifies the spec of this Recipe that produced
+	// the current result
+	Revision *SpecRevision `json:"revision,omitempty"`
+
+	// Suspension has the details of suspending this Recipe if
+	// spec.suspend is set
+	Suspension *SuspensionStatus `json:"suspension,omitempty"`
+
+	// Lock has the details of the lock held to execute this Recipe
+	Lock *LockStatus `json:"lock,omitempty"`
+
+	// Schedule has the last & next run times if spec.schedule is set
+	Schedule *ScheduleStatus `json:"schedule,omitempty"`
+
 	// Time taken to execute the Recipe
 	ExecutionTime *ExecutionTime `json:"executionTime,omitempty"`
 
@@ -235,6 +465,51 @@ type RecipeStatus struct {
 
 	// Detailed results of individual tasks
 	TaskResults map[string]TaskResult `json:"tasks,omitempty"`
+
+	// Checkpoint has the details to resume this Recipe if
+	// spec.checkpoint is enabled or if this Recipe has approval
+	// tasks
+	Checkpoint *CheckpointStatus `json:"checkpoint,omitempty"`
+
+	// Approvals has the details of approving the approval tasks
+	// keyed by the task name
+	Approvals map[string]ApprovalStatus `json:"approvals,omitempty"`
+
+	// Names of the tasks that were included from RecipeTemplates
+	// keyed by the including task's name
+	IncludedTasks map[string][]string `json:"includedTasks,omitempty"`
+
+	// Detailed results of individual onFailure tasks
+	OnFailureTaskResults map[string]TaskResult `json:"onFailureTasks,omitempty"`
+
+	// Detailed results of individual finally tasks
+	FinallyTaskResults map[string]TaskResult `json:"finallyTasks,omitempty"`
+}
+
+// SpecRevision identifies a revision of the Recipe's spec
+type SpecRevision struct {
+	// Generation is the metadata.generation of the Recipe
+	Generation int64 `json:"generation,omitempty"`
+
+	// SpecHash is the hash of the Recipe's spec
+	//
+	// NOTE:
+	//	Hash excludes spec.suspend since suspending a Recipe does
+	// not change what this Recipe does
+	SpecHash string `json:"specHash,omitempty"`
+}
+
+// RecipeRunSummary is a brief record of a Recipe execution
+type RecipeRunSummary struct {
+	Phase          RecipeStatusPhase `json:"phase"`
+	Reason         string            `json:"reason,omitempty"`
+	Message        string            `json:"message,omitempty"`
+	RerunToken     string            `json:"rerunToken,omitempty"`
+	StartTime      *metav1.Time      `json:"startTime,omitempty"`
+	CompletionTime *metav1.Time      `json:"completionTime,omitempty"`
+	ExecutionTime  *ExecutionTime    `json:"executionTime,omitempty"`
+	TaskCount      *TaskCount        `json:"taskCount,omitempty"`
+	FailedTasks    []string          `json:"failedTasks,omitempty"`
 }
 
 // String implements the Stringer interface
@@ -0,0 +1,94 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MissedRunPolicy defines the action to be taken when one or
+// more scheduled runs of a Recipe were missed. For example runs
+// get missed when the operator was down at the scheduled time.
+type MissedRunPolicy string
+
+const (
+	// MissedRunPolicySkip skips the missed runs & waits for the
+	// next scheduled time
+	//
+	// NOTE:
+	//	This is the default policy
+	MissedRunPolicySkip MissedRunPolicy = "Skip"
+
+	// MissedRunPolicyRunOnce runs the Recipe once irrespective of
+	// the number of runs that were missed
+	MissedRunPolicyRunOnce MissedRunPolicy = "RunOnce"
+)
+
+// Schedule defines the times at which a Recipe gets executed
+type Schedule struct {
+	// Cron is a standard cron expression with five fields i.e.
+	// minute, hour, day of month, month & day of week. Descriptors
+	// like @hourly, @daily, @weekly are supported as well.
+	Cron string `json:"cron"`
+
+	// TimeZone is the IANA name of the time zone used to interpret
+	// the cron expression e.g. Asia/Kolkata
+	//
+	// Defaults to UTC
+	//
+	// +default="UTC"
+	TimeZone string `json:"timeZone,omitempty"`
+
+	// StartTime when set, schedules the runs only after this time
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+
+	// EndTime when set, stops scheduling the runs after this time
+	EndTime *metav1.Time `json:"endTime,omitempty"`
+
+	// MissedRunPolicy decides if a missed run should be executed
+	//
+	// Defaults to Skip
+	//
+	// +default="Skip"
+	MissedRunPolicy MissedRunPolicy `json:"missedRunPolicy,omitempty"`
+
+	// StartingDeadlineSeconds is the duration after a scheduled
+	// time within which a run is considered to be on time. A run
+	// that can't start within this duration is treated as missed.
+	//
+	// Defaults to 60 seconds
+	//
+	// +default=60
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+}
+
+// ScheduleStatus provides the scheduling details of a Recipe
+type ScheduleStatus struct {
+	// LastScheduleTime is the scheduled time of the last run
+	LastScheduleTime *metav1.Time `json:"lastScheduleTime,omitempty"`
+
+	// NextScheduleTime is the time of the next run. This is not
+	// set if there are no more runs e.g. when end time is over.
+	NextScheduleTime *metav1.Time `json:"nextScheduleTime,omitempty"`
+
+	// MissedRuns is the number of runs that were skipped since
+	// they were missed
+	MissedRuns int `json:"missedRuns,omitempty"`
+
+	// Message provides details of the last scheduling decision
+	Message string `json:"message,omitempty"`
+}
@@ -32,7 +32,7 @@ package types
 //
 // NOTE:
 //	Each field path set here should represent its absolute field path
-var SupportedAbsolutePaths = []string{
+var SupportedAbsolutePaths = append([]string{
 	"apiVersion",
 	"kind",
 	// spec
@@ -40,36 +40,45 @@ var SupportedAbsolutePaths = []string{
 	"spec.resync.onNotEligibleResyncInSeconds",
 	"spec.resync.onErrorResyncInSeconds",
 	"spec.resync.intervalInSeconds",
+	"spec.resync.onWaitingForApprovalResyncInSeconds",
+	"spec.eligible.checks.[*].id",
 	"spec.eligible.checks.[*].kind",
 	"spec.eligible.checks.[*].apiVersion",
 	"spec.eligible.checks.[*].count",
 	"spec.eligible.checks.[*].when",
+	"spec.eligible.checks.[*].name",
+	"spec.eligible.checks.[*].namespace",
+	"spec.eligible.checks.[*].pathChecks.[*].path",
+	"spec.eligible.checks.[*].pathChecks.[*].pathCheckOperator",
+	"spec.eligible.checks.[*].pathChecks.[*].value",
+	"spec.eligible.checks.[*].pathChecks.[*].dataType",
 	"spec.eligible.checks.[*].labelSelector.matchExpressions.[*].key",
 	"spec.eligible.checks.[*].labelSelector.matchExpressions.[*].operator",
 	"spec.eligible.checks.[*].labelSelector.matchExpressions.[*].values",
+	"spec.eligible.condition.checkID",
+	"spec.eligible.condition.operator",
 	"spec.enabled.when",
-	// spec.tasks[*]
-	"spec.tasks.[*].name",
-	"spec.tasks.[*].failFast.when",
-	"spec.tasks.[*].ignoreError",
-	// spec.tasks.[*].create
-	"spec.tasks.[*].create.ignoreDiscovery",
-	"spec.tasks.[*].create.replicas",
-	// spec.tasks.[*].assert
-	"spec.tasks.[*].assert.stateCheck.stateCheckOperator",
-	"spec.tasks.[*].assert.stateCheck.count",
-	"spec.tasks.[*].assert.pathCheck.path",
-	"spec.tasks.[*].assert.pathCheck.pathCheckOperator",
-	"spec.tasks.[*].assert.pathCheck.value",
-	"spec.tasks.[*].assert.pathCheck.dataType",
-	"spec.tasks.[*].assert.errorOnAssertFailure",
-	// spec.tasks.[*].apply
-	"spec.tasks.[*].apply.ignoreDiscovery",
-	"spec.tasks.[*].apply.replicas",
-	// spec.tasks.[*].label
-	"spec.tasks.[*].label.includeByNames",
-	"spec.tasks.[*].label.autoUnset",
-}
+	"spec.enabled.rerunPolicy",
+	"spec.schedule.cron",
+	"spec.schedule.timeZone",
+	"spec.schedule.startTime",
+	"spec.schedule.endTime",
+	"spec.schedule.missedRunPolicy",
+	"spec.schedule.startingDeadlineSeconds",
+	"spec.lock.leaseDurationSeconds",
+	"spec.runHistoryLimit",
+	"spec.timeoutInSeconds",
+	"spec.suspend",
+	"spec.checkpoint.enabled",
+	"spec.targetCluster.secretName",
+	"spec.targetCluster.key",
+	"spec.serviceAccountName",
+	"spec.dependsOn.[*].name",
+	"spec.dependsOn.[*].phase",
+	"spec.dependsOn.[*].labelSelector.matchExpressions.[*].key",
+	"spec.dependsOn.[*].labelSelector.matchExpressions.[*].operator",
+	"spec.dependsOn.[*].labelSelector.matchExpressions.[*].values",
+}, TaskListFieldPaths(taskPaths...)...)
 
 // UserAllowedPathPrefixes represent the nested field paths
 // that can have further fields. These fields are not managed
@@ -84,16 +93,108 @@ var SupportedAbsolutePaths = []string{
 //
 // NOTE:
 //	Each prefix set here must end with a dot i.e. `.`
-var UserAllowedPathPrefixes = []string{
-	"metadata.",                                           // K8s controlled
-	"status.",                                             // dope controlled
-	"spec.tasks.[*].apply.state.",                         // can be any K8s resource
-	"spec.tasks.[*].delete.state.",                        // can be any K8s resource
-	"spec.tasks.[*].create.state.",                        // can be any K8s resource
-	"spec.tasks.[*].assert.state.",                        // can be any K8s resource
-	"spec.tasks.[*].label.state.",                         // can be any K8s resource
-	"spec.tasks.[*].label.applyLabels.",                   // can be any K8s labels
+var UserAllowedPathPrefixes = append([]string{
+	"metadata.", // K8s controlled
+	"status.",   // dope controlled
 	"spec.eligible.checks.[*].labelSelector.matchLabels.", // can be any label pairs
+	"spec.eligible.condition.conditions.",                 // can be nested to any depth
+	"spec.params.",                                        // can be any values
+	"spec.dependsOn.[*].labelSelector.matchLabels.",       // can be any label pairs
+}, TaskListFieldPaths(taskPathPrefixes...)...)
+
+// TaskListPaths represent the field paths of a Recipe that hold a
+// list of tasks
+var TaskListPaths = []string{
+	"spec.tasks",
+	"spec.onFailure",
+	"spec.finally",
+}
+
+// TaskListFieldPaths returns the absolute field paths of the
+// provided task field paths for every list of tasks
+//
+// NOTE:
+//	Provided field paths are relative to a task
+func TaskListFieldPaths(taskFieldPaths ...string) []string {
+	var out []string
+	for _, list := range TaskListPaths {
+		for _, path := range taskFieldPaths {
+			out = append(out, list+".[*]."+path)
+		}
+	}
+	return out
+}
+
+// taskPaths represent the field paths of a task that are
+// supported by Recipe custom resource schema
+var taskPaths = []string{
+	"name",
+	"failFast.when",
+	"ignoreError",
+	"impersonate.user",
+	"impersonate.groups",
+	"impersonate.serviceAccount.name",
+	"impersonate.serviceAccount.namespace",
+	"forEach.items",
+	"forEach.param",
+	"include.template",
+	"approval.configMap.name",
+	"approval.configMap.key",
+	"approval.configMap.approverKey",
+	"approval.timeoutInSeconds",
+	// create
+	"create.ignoreDiscovery",
+	"create.replicas",
+	"create.expectError.code",
+	"create.expectError.reason",
+	"create.expectError.messagePattern",
+	// assert
+	"assert.stateCheck.stateCheckOperator",
+	"assert.stateCheck.count",
+	"assert.pathCheck.path",
+	"assert.pathCheck.pathCheckOperator",
+	"assert.pathCheck.value",
+	"assert.pathCheck.dataType",
+	"assert.errorOnAssertFailure",
+	// apply
+	"apply.ignoreDiscovery",
+	"apply.replicas",
+	"apply.expectError.code",
+	"apply.expectError.reason",
+	"apply.expectError.messagePattern",
+	// delete
+	"delete.expectError.code",
+	"delete.expectError.reason",
+	"delete.expectError.messagePattern",
+	// label
+	"label.includeByNames",
+	"label.autoUnset",
+	"label.fieldSelector",
+	"label.removeLabels",
+	"label.removeAnnotations",
+	"label.labelSelector.matchExpressions.[*].key",
+	"label.labelSelector.matchExpressions.[*].operator",
+	"label.labelSelector.matchExpressions.[*].values",
+	"label.namespaceSelector.matchExpressions.[*].key",
+	"label.namespaceSelector.matchExpressions.[*].operator",
+	"label.namespaceSelector.matchExpressions.[*].values",
+}
+
+// taskPathPrefixes represent the field paths of a task that can
+// have further fields
+var taskPathPrefixes = []string{
+	"apply.state.",                         // can be any K8s resource
+	"delete.state.",                        // can be any K8s resource
+	"create.state.",                        // can be any K8s resource
+	"assert.state.",                        // can be any K8s resource
+	"label.state.",                         // can be any K8s resource
+	"label.applyLabels.",                   // can be any K8s labels
+	"label.applyAnnotations.",              // can be any K8s annotations
+	"label.labelSelector.matchLabels.",     // can be any label pairs
+	"label.namespaceSelector.matchLabels.", // can be any label pairs
+	"forEach.items.[*].",                   // can be any values
+	"forEach.list.state.",                  // can be any K8s resource
+	"include.params.",                      // can be any values
 }
 
 type SchemaStatus string
@@ -0,0 +1,40 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SuspensionStatus has the details of a suspended Recipe
+//
+// NOTE:
+//	Phase, reason & message of the Recipe prior to its suspension
+// are restored once this Recipe is resumed
+type SuspensionStatus struct {
+	// SuspendTime is the time when this Recipe was suspended
+	SuspendTime *metav1.Time `json:"suspendTime,omitempty"`
+
+	// Phase of the Recipe prior to its suspension
+	Phase RecipeStatusPhase `json:"phase,omitempty"`
+
+	// Reason of the Recipe prior to its suspension
+	Reason string `json:"reason,omitempty"`
+
+	// Message of the Recipe prior to its suspension
+	Message string `json:"message,omitempty"`
+}
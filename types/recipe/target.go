@@ -0,0 +1,47 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+// DefaultTargetClusterKey is the key of the Secret's data that
+// holds the kubeconfig of the target cluster by default
+const DefaultTargetClusterKey string = "kubeconfig"
+
+// TargetCluster refers to the kubernetes cluster against which
+// the tasks of a Recipe get executed
+//
+// NOTE:
+//	Lock & status of the Recipe are always managed in the cluster
+// where the Recipe is found. Eligibility checks & tasks are run
+// against the target cluster.
+type TargetCluster struct {
+	// SecretName is the name of the Secret that holds the kubeconfig
+	// of the target cluster. This Secret should be in the namespace
+	// of the Recipe.
+	//
+	// NOTE:
+	//	Kubeconfig should set its credentials inline i.e. token,
+	// client-certificate-data, client-key-data & certificate-authority-data.
+	// Exec plugins, auth providers & files e.g. tokenFile are not allowed.
+	SecretName string `json:"secretName"`
+
+	// Key of the Secret's data that holds the kubeconfig
+	//
+	// Defaults to kubeconfig
+	//
+	// +default="kubeconfig"
+	Key string `json:"key,omitempty"`
+}
@@ -38,6 +38,14 @@ const (
 	// IgnoreErrorAsWarning defines the rule to ignore error
 	// and treat it as a warning
 	IgnoreErrorAsWarning IgnoreErrorRule = "AsWarning"
+
+	// IgnoreErrorForbiddenAsPassed defines the rule to treat a
+	// forbidden error as passed
+	//
+	// NOTE:
+	//	Task fails if its action was not forbidden. This is useful
+	// to verify that an identity is denied an action.
+	IgnoreErrorForbiddenAsPassed IgnoreErrorRule = "ForbiddenAsPassed"
 )
 
 // FailFast holds the condition that determines if an error
@@ -47,6 +55,32 @@ type FailFast struct {
 	When FailFastRule `json:"when,omitempty"`
 }
 
+// Impersonate defines the identity that a task is run as
+//
+// NOTE:
+//	Either User or ServiceAccount should be set
+type Impersonate struct {
+	// User to be impersonated
+	User string `json:"user,omitempty"`
+
+	// Groups to be impersonated along with the user or service
+	// account
+	Groups []string `json:"groups,omitempty"`
+
+	// ServiceAccount to be impersonated
+	ServiceAccount *ServiceAccountReference `json:"serviceAccount,omitempty"`
+}
+
+// ServiceAccountReference refers to a ServiceAccount
+type ServiceAccountReference struct {
+	Name string `json:"name"`
+
+	// Namespace of the ServiceAccount
+	//
+	// Defaults to the namespace of the Recipe
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // Task that needs to be executed as part of a Recipe
 //
 // Task forms the fundamental unit of execution within a
@@ -60,6 +94,22 @@ type Task struct {
 	Label           *Label          `json:"label,omitempty"`
 	IgnoreErrorRule IgnoreErrorRule `json:"ignoreError,omitempty"`
 	FailFast        *FailFast       `json:"failFast,omitempty"`
+
+	// Impersonate runs this task as the provided identity instead
+	// of the identity that runs the Recipe. This can't be set if the
+	// Recipe is run as a service account.
+	Impersonate *Impersonate `json:"impersonate,omitempty"`
+
+	// ForEach runs this task once for every item
+	ForEach *ForEach `json:"forEach,omitempty"`
+
+	// Include runs the tasks of a RecipeTemplate in place of this
+	// task
+	Include *Include `json:"include,omitempty"`
+
+	// Approval pauses the Recipe at this task till this task
+	// is approved
+	Approval *Approval `json:"approval,omitempty"`
 }
 
 // String implements the Stringer interface
@@ -87,6 +137,18 @@ const (
 
 	// TaskStatusWarning implies a failed task
 	TaskStatusWarning TaskStatusPhase = "Warning"
+
+	// TaskStatusTimedOut implies a task that was not run or was
+	// stopped since the Recipe exceeded its timeout
+	TaskStatusTimedOut TaskStatusPhase = "TimedOut"
+
+	// TaskStatusCancelled implies a task that was not run since
+	// the Recipe was cancelled
+	TaskStatusCancelled TaskStatusPhase = "Cancelled"
+
+	// TaskStatusWaitingForApproval implies an approval task that
+	// is neither approved nor rejected
+	TaskStatusWaitingForApproval TaskStatusPhase = "WaitingForApproval"
 )
 
 // TaskCount holds various counts related to execution of tasks
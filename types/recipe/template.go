@@ -0,0 +1,71 @@
+/*
+Copyright 2020 The MayaData Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package types
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RecipeTemplate is a kubernetes custom resource that holds a
+// reusable sequence of tasks. Recipe tasks run these tasks by
+// including this template.
+type RecipeTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec RecipeTemplateSpec `json:"spec"`
+}
+
+// RecipeTemplateSpec defines the tasks of this template
+//
+// NOTE:
+//	Params are referred to in the tasks as $(params.<name>). A
+// reference that is the entire string value is replaced with the
+// param's value as is. A task's forEach.param may refer to a param
+// of this template.
+type RecipeTemplateSpec struct {
+	// Params that can be set by the including tasks
+	Params []TemplateParam `json:"params,omitempty"`
+
+	// Tasks that are run in place of the including task
+	Tasks []Task `json:"tasks"`
+}
+
+// TemplateParam defines a param of a RecipeTemplate
+type TemplateParam struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+
+	// Default value of this param. Param needs to be set by the
+	// including task if this is not set.
+	Default interface{} `json:"default,omitempty"`
+}
+
+// Include runs the tasks of a RecipeTemplate in place of the
+// including task
+//
+// NOTE:
+//	Included tasks are named as <including task name>/<template
+// task name>
+type Include struct {
+	// Template is the name of the RecipeTemplate. This template
+	// should be in the namespace of the Recipe.
+	Template string `json:"template"`
+
+	// Params set the values of the template's params
+	Params map[string]interface{} `json:"params,omitempty"`
+}
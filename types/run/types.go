@@ -259,6 +259,10 @@ const (
 	// ResourceCheckOperatorOR does an **OR** operation amongst the
 	// list of ResourceCheck(s)
 	ResourceCheckOperatorOR ResourceCheckOperator = "OR"
+
+	// ResourceCheckOperatorNOT negates the result of its only
+	// ResourceCheck
+	ResourceCheckOperatorNOT ResourceCheckOperator = "NOT"
 )
 
 // IsResourceCheckOperatorValid returns true if the given operator
@@ -266,7 +270,8 @@ const (
 func IsResourceCheckOperatorValid(op ResourceCheckOperator) bool {
 	switch op {
 	case ResourceCheckOperatorAND,
-		ResourceCheckOperatorOR:
+		ResourceCheckOperatorOR,
+		ResourceCheckOperatorNOT:
 		return true
 	default:
 		return false
@@ -447,13 +452,42 @@ type Assert struct {
 // ResourceCheck defines one or more resource related conditions
 // used to verify 'presence of', 'absence of', 'equals to' & other
 // checks against one or more resources observed in the cluster.
+//
+// NOTE:
+//	Select checks & nested checks are the operands of this check's
+// operator. Nesting can be used to express conditions like
+// "(A and B) or not C".
 type ResourceCheck struct {
-	// OR-ing or AND-ing of checks
+	// OR-ing, AND-ing or negation of checks
+	//
+	// Defaults to OR
 	CheckOperator ResourceCheckOperator `json:"resourceCheckOperator,omitempty"`
 
 	// List of resource select based checks to execute against the
 	// observed resources
 	SelectChecks []ResourceSelectCheck `json:"resourceSelectChecks,omitempty"`
+
+	// List of nested checks that are evaluated along with the
+	// select checks
+	Checks []ResourceCheck `json:"resourceChecks,omitempty"`
+}
+
+// ResourceCheckResult provides the result of evaluating a
+// ResourceCheck. Nested results follow the nesting of checks
+// & help in finding the branch that failed.
+type ResourceCheckResult struct {
+	CheckOperator      ResourceCheckOperator       `json:"resourceCheckOperator,omitempty"`
+	Passed             bool                        `json:"passed"`
+	Message            string                      `json:"message,omitempty"`
+	SelectCheckResults []ResourceSelectCheckResult `json:"resourceSelectCheckResults,omitempty"`
+	CheckResults       []ResourceCheckResult       `json:"resourceCheckResults,omitempty"`
+}
+
+// ResourceSelectCheckResult provides the result of evaluating a
+// ResourceSelectCheck
+type ResourceSelectCheckResult struct {
+	Index  int  `json:"index"`
+	Passed bool `json:"passed"`
 }
 
 // ResourceSelectCheck defines the condition to match, filter,
@@ -556,6 +590,9 @@ type Result struct {
 	SkippedResourcesInfo  []string    `json:"skippedResourcesInfo,omitempty"`
 	HasRunOnce            *bool       `json:"hasRunOnce,omitempty"`
 	HasSkippedOnce        *bool       `json:"hasSkippedOnce,omitempty"`
+
+	// CheckResult is set when resource checks are evaluated
+	CheckResult *ResourceCheckResult `json:"resourceCheckResult,omitempty"`
 }
 
 // SkipResult provides details of the action which was not executed